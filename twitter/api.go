@@ -0,0 +1,272 @@
+package twitter
+
+import "net/http"
+
+// API is the interface of a Client, whose methods return its services as
+// interfaces. Depend on API (or a service interface) instead of *Client to
+// substitute test doubles, such as the twittertest stubs.
+type API interface {
+	AccountsAPI() AccountsAPI
+	BlocksAPI() BlocksAPI
+	DirectMessagesAPI() DirectMessagesAPI
+	FavoritesAPI() FavoritesAPI
+	FollowersAPI() FollowersAPI
+	FriendsAPI() FriendsAPI
+	FriendshipsAPI() FriendshipsAPI
+	ListsAPI() ListsAPI
+	RateLimitsAPI() RateLimitsAPI
+	SearchAPI() SearchAPI
+	PremiumSearchAPI() PremiumSearchAPI
+	StatusesAPI() StatusesAPI
+	StreamsAPI() StreamsAPI
+	TimelinesAPI() TimelinesAPI
+	TrendsAPI() TrendsAPI
+	UsersAPI() UsersAPI
+	Do(method, path string, params *RequestParams, v interface{}) (*http.Response, error)
+}
+
+// AccountsAPI is the interface of the AccountService.
+type AccountsAPI interface {
+	UpdateProfile(params *AccountUpdateProfileParams) (*User, *http.Response, error)
+	VerifyCredentials(params *AccountVerifyParams) (*User, *http.Response, error)
+}
+
+// BlocksAPI is the interface of the BlockService.
+type BlocksAPI interface {
+	Create(params *BlockCreateParams) (User, *http.Response, error)
+	Destroy(params *BlockDestroyParams) (User, *http.Response, error)
+}
+
+// DirectMessagesAPI is the interface of the DirectMessageService.
+type DirectMessagesAPI interface {
+	Destroy(id int64, params *DirectMessageDestroyParams) (*DirectMessage, *http.Response, error)
+	EventsDestroy(id string) (*http.Response, error)
+	EventsList(params *DirectMessageEventsListParams) (*DirectMessageEvents, *http.Response, error)
+	EventsNew(params *DirectMessageEventsNewParams) (*DirectMessageEvent, *http.Response, error)
+	EventsShow(id string, params *DirectMessageEventsShowParams) (*DirectMessageEvent, *http.Response, error)
+	Get(params *DirectMessageGetParams) ([]DirectMessage, *http.Response, error)
+	New(params *DirectMessageNewParams) (*DirectMessage, *http.Response, error)
+	Sent(params *DirectMessageSentParams) ([]DirectMessage, *http.Response, error)
+	Show(id int64) (*DirectMessage, *http.Response, error)
+}
+
+// FavoritesAPI is the interface of the FavoriteService.
+type FavoritesAPI interface {
+	Create(params *FavoriteCreateParams) (*Tweet, *http.Response, error)
+	Destroy(params *FavoriteDestroyParams) (*Tweet, *http.Response, error)
+	List(params *FavoriteListParams) ([]Tweet, *http.Response, error)
+}
+
+// FollowersAPI is the interface of the FollowerService.
+type FollowersAPI interface {
+	IDs(params *FollowerIDParams) (*FollowerIDs, *http.Response, error)
+	List(params *FollowerListParams) (*Followers, *http.Response, error)
+}
+
+// FriendsAPI is the interface of the FriendService.
+type FriendsAPI interface {
+	IDs(params *FriendIDParams) (*FriendIDs, *http.Response, error)
+	List(params *FriendListParams) (*Friends, *http.Response, error)
+}
+
+// FriendshipsAPI is the interface of the FriendshipService.
+type FriendshipsAPI interface {
+	Create(params *FriendshipCreateParams) (*User, *http.Response, error)
+	Destroy(params *FriendshipDestroyParams) (*User, *http.Response, error)
+	Incoming(params *FriendshipPendingParams) (*FriendIDs, *http.Response, error)
+	Lookup(params *FriendshipLookupParams) (*[]FriendshipResponse, *http.Response, error)
+	Outgoing(params *FriendshipPendingParams) (*FriendIDs, *http.Response, error)
+	Show(params *FriendshipShowParams) (*Relationship, *http.Response, error)
+}
+
+// ListsAPI is the interface of the ListsService.
+type ListsAPI interface {
+	Create(name string, params *ListsCreateParams) (*List, *http.Response, error)
+	Destroy(params *ListsDestroyParams) (*List, *http.Response, error)
+	List(params *ListsListParams) ([]List, *http.Response, error)
+	Members(params *ListsMembersParams) (*Members, *http.Response, error)
+	MembersCreate(params *ListsMembersCreateParams) (*http.Response, error)
+	MembersCreateAll(params *ListsMembersCreateAllParams) (*http.Response, error)
+	MembersDestroy(params *ListsMembersDestroyParams) (*http.Response, error)
+	MembersDestroyAll(params *ListsMembersDestroyAllParams) (*http.Response, error)
+	MembersShow(params *ListsMembersShowParams) (*User, *http.Response, error)
+	Memberships(params *ListsMembershipsParams) (*Membership, *http.Response, error)
+	Ownerships(params *ListsOwnershipsParams) (*Ownership, *http.Response, error)
+	Show(params *ListsShowParams) (*List, *http.Response, error)
+	Statuses(params *ListsStatusesParams) ([]Tweet, *http.Response, error)
+	Subscribers(params *ListsSubscribersParams) (*Subscribers, *http.Response, error)
+	SubscribersCreate(params *ListsSubscribersCreateParams) (*List, *http.Response, error)
+	SubscribersDestroy(params *ListsSubscribersDestroyParams) (*http.Response, error)
+	SubscribersShow(params *ListsSubscribersShowParams) (*User, *http.Response, error)
+	Subscriptions(params *ListsSubscriptionsParams) (*Subscribed, *http.Response, error)
+	Update(params *ListsUpdateParams) (*http.Response, error)
+}
+
+// RateLimitsAPI is the interface of the RateLimitService.
+type RateLimitsAPI interface {
+	Status(params *RateLimitParams) (*RateLimit, *http.Response, error)
+}
+
+// SearchAPI is the interface of the SearchService.
+type SearchAPI interface {
+	Tweets(params *SearchTweetParams) (*Search, *http.Response, error)
+}
+
+// PremiumSearchAPI is the interface of the PremiumSearchService.
+type PremiumSearchAPI interface {
+	Count30Days(params *PremiumSearchCountTweetParams, label string) (*PremiumSearchCount, *http.Response, error)
+	CountFullArchive(params *PremiumSearchCountTweetParams, label string) (*PremiumSearchCount, *http.Response, error)
+	Search30Days(params *PremiumSearchTweetParams, label string) (*PremiumSearch, *http.Response, error)
+	SearchFullArchive(params *PremiumSearchTweetParams, label string) (*PremiumSearch, *http.Response, error)
+}
+
+// StatusesAPI is the interface of the StatusService.
+type StatusesAPI interface {
+	Destroy(id int64, params *StatusDestroyParams) (*Tweet, *http.Response, error)
+	Lookup(ids []int64, params *StatusLookupParams) ([]Tweet, *http.Response, error)
+	OEmbed(params *StatusOEmbedParams) (*OEmbedTweet, *http.Response, error)
+	ResumeThread(threadErr *ThreadError) ([]Tweet, *http.Response, error)
+	Retweet(id int64, params *StatusRetweetParams) (*Tweet, *http.Response, error)
+	Retweeters(params *StatusRetweeterParams) (*RetweeterIDs, *http.Response, error)
+	Retweets(id int64, params *StatusRetweetsParams) ([]Tweet, *http.Response, error)
+	RollbackThread(threadErr *ThreadError) (*http.Response, error)
+	Show(id int64, params *StatusShowParams) (*Tweet, *http.Response, error)
+	Thread(text string, params *ThreadParams) ([]Tweet, *http.Response, error)
+	Unretweet(id int64, params *StatusUnretweetParams) (*Tweet, *http.Response, error)
+	Update(status string, params *StatusUpdateParams) (*Tweet, *http.Response, error)
+}
+
+// StreamsAPI is the interface of the StreamService.
+type StreamsAPI interface {
+	Filter(params *StreamFilterParams) (*Stream, error)
+	Firehose(params *StreamFirehoseParams) (*Stream, error)
+	Sample(params *StreamSampleParams) (*Stream, error)
+	ShardedFilter(params *StreamFilterParams, extra ...*StreamService) (*ShardedStream, error)
+	Site(params *StreamSiteParams) (*Stream, error)
+	SiteAddUsers(controlURI string, userIDs []int64) (*http.Response, error)
+	SiteInfo(controlURI string) (*SiteStreamInfo, *http.Response, error)
+	SiteRemoveUsers(controlURI string, userIDs []int64) (*http.Response, error)
+	User(params *StreamUserParams) (*Stream, error)
+}
+
+// TimelinesAPI is the interface of the TimelineService.
+type TimelinesAPI interface {
+	HomeTimeline(params *HomeTimelineParams) ([]Tweet, *http.Response, error)
+	MentionTimeline(params *MentionTimelineParams) ([]Tweet, *http.Response, error)
+	RetweetsOfMeTimeline(params *RetweetsOfMeTimelineParams) ([]Tweet, *http.Response, error)
+	UserTimeline(params *UserTimelineParams) ([]Tweet, *http.Response, error)
+}
+
+// TrendsAPI is the interface of the TrendsService.
+type TrendsAPI interface {
+	Available() ([]Location, *http.Response, error)
+	Closest(params *ClosestParams) ([]Location, *http.Response, error)
+	Place(woeid int64, params *TrendsPlaceParams) ([]TrendsList, *http.Response, error)
+}
+
+// UsersAPI is the interface of the UserService.
+type UsersAPI interface {
+	Lookup(params *UserLookupParams) ([]User, *http.Response, error)
+	Search(query string, params *UserSearchParams) ([]User, *http.Response, error)
+	Show(params *UserShowParams) (*User, *http.Response, error)
+}
+
+var (
+	_ API               = (*Client)(nil)
+	_ AccountsAPI       = (*AccountService)(nil)
+	_ BlocksAPI         = (*BlockService)(nil)
+	_ DirectMessagesAPI = (*DirectMessageService)(nil)
+	_ FavoritesAPI      = (*FavoriteService)(nil)
+	_ FollowersAPI      = (*FollowerService)(nil)
+	_ FriendsAPI        = (*FriendService)(nil)
+	_ FriendshipsAPI    = (*FriendshipService)(nil)
+	_ ListsAPI          = (*ListsService)(nil)
+	_ RateLimitsAPI     = (*RateLimitService)(nil)
+	_ SearchAPI         = (*SearchService)(nil)
+	_ PremiumSearchAPI  = (*PremiumSearchService)(nil)
+	_ StatusesAPI       = (*StatusService)(nil)
+	_ StreamsAPI        = (*StreamService)(nil)
+	_ TimelinesAPI      = (*TimelineService)(nil)
+	_ TrendsAPI         = (*TrendsService)(nil)
+	_ UsersAPI          = (*UserService)(nil)
+)
+
+// AccountsAPI returns the Client's AccountService.
+func (c *Client) AccountsAPI() AccountsAPI {
+	return c.Accounts
+}
+
+// BlocksAPI returns the Client's BlockService.
+func (c *Client) BlocksAPI() BlocksAPI {
+	return c.Blocks
+}
+
+// DirectMessagesAPI returns the Client's DirectMessageService.
+func (c *Client) DirectMessagesAPI() DirectMessagesAPI {
+	return c.DirectMessages
+}
+
+// FavoritesAPI returns the Client's FavoriteService.
+func (c *Client) FavoritesAPI() FavoritesAPI {
+	return c.Favorites
+}
+
+// FollowersAPI returns the Client's FollowerService.
+func (c *Client) FollowersAPI() FollowersAPI {
+	return c.Followers
+}
+
+// FriendsAPI returns the Client's FriendService.
+func (c *Client) FriendsAPI() FriendsAPI {
+	return c.Friends
+}
+
+// FriendshipsAPI returns the Client's FriendshipService.
+func (c *Client) FriendshipsAPI() FriendshipsAPI {
+	return c.Friendships
+}
+
+// ListsAPI returns the Client's ListsService.
+func (c *Client) ListsAPI() ListsAPI {
+	return c.Lists
+}
+
+// RateLimitsAPI returns the Client's RateLimitService.
+func (c *Client) RateLimitsAPI() RateLimitsAPI {
+	return c.RateLimits
+}
+
+// SearchAPI returns the Client's SearchService.
+func (c *Client) SearchAPI() SearchAPI {
+	return c.Search
+}
+
+// PremiumSearchAPI returns the Client's PremiumSearchService.
+func (c *Client) PremiumSearchAPI() PremiumSearchAPI {
+	return c.PremiumSearch
+}
+
+// StatusesAPI returns the Client's StatusService.
+func (c *Client) StatusesAPI() StatusesAPI {
+	return c.Statuses
+}
+
+// StreamsAPI returns the Client's StreamService.
+func (c *Client) StreamsAPI() StreamsAPI {
+	return c.Streams
+}
+
+// TimelinesAPI returns the Client's TimelineService.
+func (c *Client) TimelinesAPI() TimelinesAPI {
+	return c.Timelines
+}
+
+// TrendsAPI returns the Client's TrendsService.
+func (c *Client) TrendsAPI() TrendsAPI {
+	return c.Trends
+}
+
+// UsersAPI returns the Client's UserService.
+func (c *Client) UsersAPI() UsersAPI {
+	return c.Users
+}
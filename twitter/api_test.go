@@ -0,0 +1,20 @@
+package twitter
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_API(t *testing.T) {
+	client := NewClient(&http.Client{})
+	var api API = client
+	assert.Equal(t, client.Accounts, api.AccountsAPI())
+	assert.Equal(t, client.DirectMessages, api.DirectMessagesAPI())
+	assert.Equal(t, client.Lists, api.ListsAPI())
+	assert.Equal(t, client.Statuses, api.StatusesAPI())
+	assert.Equal(t, client.Streams, api.StreamsAPI())
+	assert.Equal(t, client.Timelines, api.TimelinesAPI())
+	assert.Equal(t, client.Users, api.UsersAPI())
+}
@@ -23,3 +23,16 @@ func newAggressiveExponentialBackOff() *backoff.ExponentialBackOff {
 	b.Reset()
 	return b
 }
+
+// streamBackOffs returns new exponential and aggressive exponential BackOffs
+// for a Stream, from the client options or the defaults.
+func (o *clientOptions) streamBackOffs() (backoff.BackOff, backoff.BackOff) {
+	var exponential, aggressive backoff.BackOff = newExponentialBackOff(), newAggressiveExponentialBackOff()
+	if o.backOff != nil {
+		exponential = o.backOff()
+	}
+	if o.aggressiveBackOff != nil {
+		aggressive = o.aggressiveBackOff()
+	}
+	return exponential, aggressive
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -35,3 +36,15 @@ func (b *BackOffRecorder) NextBackOff() time.Duration {
 func (b *BackOffRecorder) Reset() {
 	b.Count = 0
 }
+
+func TestClientOptions_StreamBackOffs(t *testing.T) {
+	exponential, aggressive := (&clientOptions{}).streamBackOffs()
+	assert.Equal(t, newExponentialBackOff().InitialInterval, exponential.(*backoff.ExponentialBackOff).InitialInterval)
+	assert.Equal(t, newAggressiveExponentialBackOff().InitialInterval, aggressive.(*backoff.ExponentialBackOff).InitialInterval)
+
+	options := &clientOptions{}
+	WithStreamBackOff(func() backoff.BackOff { return &BackOffRecorder{} }, nil)(options)
+	exponential, aggressive = options.streamBackOffs()
+	assert.IsType(t, &BackOffRecorder{}, exponential)
+	assert.IsType(t, &backoff.ExponentialBackOff{}, aggressive)
+}
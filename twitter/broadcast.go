@@ -0,0 +1,196 @@
+package twitter
+
+import (
+	"sync"
+)
+
+// default number of messages buffered per Subscription
+const subscriptionBuffer = 100
+
+// SlowPolicy determines what a Broadcaster does when a Subscription's buffer
+// is full.
+type SlowPolicy int
+
+const (
+	// DropNewest drops messages which do not fit in the buffer.
+	DropNewest SlowPolicy = iota
+	// DropOldest drops the oldest buffered message to make room.
+	DropOldest
+	// Disconnect unsubscribes the Subscription and closes its channel.
+	Disconnect
+)
+
+// SubscribeParams are the parameters for Broadcaster.Subscribe.
+type SubscribeParams struct {
+	// number of messages buffered (default 100)
+	Buffer int
+	// Filter returns false for messages the Subscription should not receive
+	// (optional)
+	Filter func(message interface{}) bool
+	// what to do when the buffer is full (default DropNewest)
+	Policy SlowPolicy
+}
+
+// Broadcaster owns a Stream and sends each of its messages to every
+// Subscription, so several components can share one connection. Each
+// Subscription has its own buffered channel, so a slow subscriber does not
+// block the others.
+type Broadcaster struct {
+	stream        *Stream
+	mu            sync.Mutex
+	subscriptions map[*Subscription]bool
+	stopped       bool
+	stop          sync.Once
+	group         sync.WaitGroup
+}
+
+// Subscription receives messages from a Broadcaster on its Messages
+// channel, which is closed when the Subscription is unsubscribed,
+// disconnected, or the Broadcaster stops.
+type Subscription struct {
+	Messages     <-chan interface{}
+	messages     chan interface{}
+	filter       func(message interface{}) bool
+	policy       SlowPolicy
+	broadcaster  *Broadcaster
+	dropped      int64
+	disconnected bool
+}
+
+// NewBroadcaster returns a Broadcaster which sends the Stream's messages to
+// its Subscriptions. The Broadcaster takes ownership of the Stream.
+func NewBroadcaster(stream *Stream) *Broadcaster {
+	b := &Broadcaster{
+		stream:        stream,
+		subscriptions: make(map[*Subscription]bool),
+	}
+	b.group.Add(1)
+	go b.receive()
+	return b
+}
+
+// Subscribe returns a new Subscription which receives messages from now on.
+// After the Broadcaster has stopped, the Subscription's channel is closed.
+func (b *Broadcaster) Subscribe(params *SubscribeParams) *Subscription {
+	if params == nil {
+		params = &SubscribeParams{}
+	}
+	buffer := params.Buffer
+	if buffer <= 0 {
+		buffer = subscriptionBuffer
+	}
+	messages := make(chan interface{}, buffer)
+	sub := &Subscription{
+		Messages:    messages,
+		messages:    messages,
+		filter:      params.Filter,
+		policy:      params.Policy,
+		broadcaster: b,
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stopped {
+		close(messages)
+		return sub
+	}
+	b.subscriptions[sub] = true
+	return sub
+}
+
+// Subscriptions returns the number of current Subscriptions.
+func (b *Broadcaster) Subscriptions() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscriptions)
+}
+
+// Stop stops the Stream and closes every Subscription's channel.
+func (b *Broadcaster) Stop() {
+	b.stop.Do(b.stream.Stop)
+	b.group.Wait()
+}
+
+// receive sends Stream messages to Subscriptions until the Stream's
+// Messages channel is closed.
+func (b *Broadcaster) receive() {
+	defer b.group.Done()
+	for message := range b.stream.Messages {
+		b.mu.Lock()
+		for sub := range b.subscriptions {
+			if sub.filter == nil || sub.filter(message) {
+				sub.send(message)
+			}
+		}
+		b.mu.Unlock()
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stopped = true
+	for sub := range b.subscriptions {
+		sub.close()
+	}
+}
+
+// Unsubscribe stops sending messages to the Subscription and closes its
+// channel.
+func (s *Subscription) Unsubscribe() {
+	s.broadcaster.mu.Lock()
+	defer s.broadcaster.mu.Unlock()
+	if s.broadcaster.subscriptions[s] {
+		s.close()
+	}
+}
+
+// Dropped returns the number of messages dropped because the Subscription's
+// buffer was full.
+func (s *Subscription) Dropped() int64 {
+	s.broadcaster.mu.Lock()
+	defer s.broadcaster.mu.Unlock()
+	return s.dropped
+}
+
+// Disconnected returns true if the Subscription was disconnected by the
+// Disconnect policy.
+func (s *Subscription) Disconnected() bool {
+	s.broadcaster.mu.Lock()
+	defer s.broadcaster.mu.Unlock()
+	return s.disconnected
+}
+
+// send sends the message without blocking, applying the SlowPolicy if the
+// buffer is full. Callers must hold the Broadcaster lock.
+func (s *Subscription) send(message interface{}) {
+	select {
+	case s.messages <- message:
+		return
+	default:
+	}
+	switch s.policy {
+	case DropOldest:
+		for {
+			select {
+			case <-s.messages:
+				s.dropped++
+			default:
+			}
+			select {
+			case s.messages <- message:
+				return
+			default:
+			}
+		}
+	case Disconnect:
+		s.dropped++
+		s.disconnected = true
+		s.close()
+	default:
+		s.dropped++
+	}
+}
+
+// close removes the Subscription and closes its channel. Callers must hold
+// the Broadcaster lock.
+func (s *Subscription) close() {
+	delete(s.broadcaster.subscriptions, s)
+	close(s.messages)
+}
@@ -0,0 +1,94 @@
+package twitter
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestStream returns a Stream whose messages are sent by the caller.
+func newTestStream() (*Stream, chan interface{}) {
+	messages := make(chan interface{})
+	stream := &Stream{
+		Messages: messages,
+		done:     make(chan struct{}),
+		group:    &sync.WaitGroup{},
+	}
+	return stream, messages
+}
+
+// receiveAll receives messages until the channel is closed.
+func receiveAll(messages <-chan interface{}) []interface{} {
+	var received []interface{}
+	for message := range messages {
+		received = append(received, message)
+	}
+	return received
+}
+
+func TestBroadcaster(t *testing.T) {
+	stream, messages := newTestStream()
+	broadcaster := NewBroadcaster(stream)
+	all := broadcaster.Subscribe(nil)
+	tweets := broadcaster.Subscribe(&SubscribeParams{
+		Filter: func(message interface{}) bool {
+			_, ok := message.(*Tweet)
+			return ok
+		},
+	})
+	assert.Equal(t, 2, broadcaster.Subscriptions())
+
+	messages <- &Tweet{ID: 1}
+	messages <- &StallWarning{Code: "FALLING_BEHIND"}
+	messages <- &Tweet{ID: 2}
+	close(messages)
+
+	assert.Equal(t, []interface{}{&Tweet{ID: 1}, &StallWarning{Code: "FALLING_BEHIND"}, &Tweet{ID: 2}}, receiveAll(all.Messages))
+	assert.Equal(t, []interface{}{&Tweet{ID: 1}, &Tweet{ID: 2}}, receiveAll(tweets.Messages))
+	broadcaster.Stop()
+	assert.Equal(t, 0, broadcaster.Subscriptions())
+
+	// subscribing after the stream stopped returns a closed Subscription
+	late := broadcaster.Subscribe(nil)
+	assert.Empty(t, receiveAll(late.Messages))
+}
+
+func TestBroadcaster_Unsubscribe(t *testing.T) {
+	stream, messages := newTestStream()
+	broadcaster := NewBroadcaster(stream)
+	sub := broadcaster.Subscribe(nil)
+	other := broadcaster.Subscribe(nil)
+	messages <- &Tweet{ID: 1}
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+	messages <- &Tweet{ID: 2}
+	assert.Equal(t, []interface{}{&Tweet{ID: 1}}, receiveAll(sub.Messages))
+	assert.Equal(t, 1, broadcaster.Subscriptions())
+	assert.Equal(t, &Tweet{ID: 1}, <-other.Messages)
+	assert.Equal(t, &Tweet{ID: 2}, <-other.Messages)
+	close(messages)
+	broadcaster.Stop()
+}
+
+func TestBroadcaster_SlowPolicy(t *testing.T) {
+	stream, messages := newTestStream()
+	broadcaster := NewBroadcaster(stream)
+	newest := broadcaster.Subscribe(&SubscribeParams{Buffer: 2, Policy: DropNewest})
+	oldest := broadcaster.Subscribe(&SubscribeParams{Buffer: 2, Policy: DropOldest})
+	disconnect := broadcaster.Subscribe(&SubscribeParams{Buffer: 2, Policy: Disconnect})
+	for i := int64(1); i <= 4; i++ {
+		messages <- &Tweet{ID: i}
+	}
+	close(messages)
+	broadcaster.Stop()
+
+	assert.Equal(t, []interface{}{&Tweet{ID: 1}, &Tweet{ID: 2}}, receiveAll(newest.Messages))
+	assert.Equal(t, int64(2), newest.Dropped())
+	assert.Equal(t, []interface{}{&Tweet{ID: 3}, &Tweet{ID: 4}}, receiveAll(oldest.Messages))
+	assert.Equal(t, int64(2), oldest.Dropped())
+	assert.Equal(t, []interface{}{&Tweet{ID: 1}, &Tweet{ID: 2}}, receiveAll(disconnect.Messages))
+	assert.Equal(t, int64(1), disconnect.Dropped())
+	assert.True(t, disconnect.Disconnected())
+	assert.False(t, newest.Disconnected())
+}
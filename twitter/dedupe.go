@@ -0,0 +1,181 @@
+package twitter
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// default number of Tweet IDs a Deduper remembers
+const dedupeSize = 10000
+
+// DeduperParams are the parameters for NewDeduper.
+type DeduperParams struct {
+	// maximum number of Tweet IDs remembered (default 10000)
+	Size int
+	// duration Tweet IDs are remembered (default no expiry)
+	Window time.Duration
+	// treat retweets of the same original Tweet as duplicates of each other
+	Retweets bool
+}
+
+// DedupeStats counts the Tweets seen and suppressed by a Deduper.
+type DedupeStats struct {
+	Tweets     int64
+	Suppressed int64
+}
+
+// Deduper suppresses duplicate Tweets, such as those received again after a
+// Stream reconnects, matched by several ShardedStream shards, or fetched as
+// BackfilledTweets. Recently seen Tweet IDs are kept in a bounded set which
+// evicts the least recently seen IDs first. Messages other than Tweets are
+// never suppressed.
+type Deduper struct {
+	size     int
+	window   time.Duration
+	retweets bool
+	mu       sync.Mutex
+	order    *list.List
+	entries  map[dedupeKey]*list.Element
+	stats    DedupeStats
+	now      func() time.Time
+}
+
+// dedupeKey identifies a Tweet by its ID, or a retweet by the ID of its
+// original Tweet, so an original is not a duplicate of its retweets.
+type dedupeKey struct {
+	id      int64
+	retweet bool
+}
+
+type dedupeEntry struct {
+	key  dedupeKey
+	seen time.Time
+}
+
+// NewDeduper returns a new Deduper.
+func NewDeduper(params *DeduperParams) *Deduper {
+	if params == nil {
+		params = &DeduperParams{}
+	}
+	size := params.Size
+	if size <= 0 {
+		size = dedupeSize
+	}
+	return &Deduper{
+		size:     size,
+		window:   params.Window,
+		retweets: params.Retweets,
+		order:    list.New(),
+		entries:  make(map[dedupeKey]*list.Element, size),
+		now:      time.Now,
+	}
+}
+
+// Duplicate records the message and returns true if it is a Tweet which was
+// already seen. With Retweets, a retweet is also a duplicate if another
+// retweet of the same original Tweet was seen, but the original Tweet itself
+// is not.
+func (d *Deduper) Duplicate(message interface{}) bool {
+	var tweet *Tweet
+	switch msg := message.(type) {
+	case *Tweet:
+		tweet = msg
+	case *BackfilledTweet:
+		tweet = msg.Tweet
+	}
+	if tweet == nil {
+		return false
+	}
+	key := dedupeKey{id: tweet.ID}
+	if d.retweets && tweet.RetweetedStatus != nil {
+		key = dedupeKey{id: tweet.RetweetedStatus.ID, retweet: true}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stats.Tweets++
+	now := d.now()
+	if elem, ok := d.entries[key]; ok {
+		entry := elem.Value.(*dedupeEntry)
+		if d.window == 0 || now.Sub(entry.seen) < d.window {
+			d.order.MoveToFront(elem)
+			d.stats.Suppressed++
+			return true
+		}
+		// expired, remember it as newly seen
+		entry.seen = now
+		d.order.MoveToFront(elem)
+		return false
+	}
+	d.entries[key] = d.order.PushFront(&dedupeEntry{key: key, seen: now})
+	if d.order.Len() > d.size {
+		oldest := d.order.Back()
+		d.order.Remove(oldest)
+		delete(d.entries, oldest.Value.(*dedupeEntry).key)
+	}
+	return false
+}
+
+// Stats returns counts of the Tweets seen and suppressed so far.
+func (d *Deduper) Stats() DedupeStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.stats
+}
+
+// Chan receives messages from the given channel and returns a channel of
+// the messages which are not duplicates. The returned channel is closed
+// once the given channel is closed or done is closed, so close done to stop
+// receiving before the given channel closes.
+func (d *Deduper) Chan(messages <-chan interface{}, done <-chan struct{}) <-chan interface{} {
+	out := make(chan interface{})
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				if d.Duplicate(message) {
+					continue
+				}
+				select {
+				case out <- message:
+				case <-done:
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Demux returns a Demux which passes messages which are not duplicates to
+// the given Demux.
+func (d *Deduper) Demux(demux Demux) Demux {
+	return dedupeDemux{deduper: d, demux: demux}
+}
+
+// dedupeDemux is a Demux which drops duplicate Tweets.
+type dedupeDemux struct {
+	deduper *Deduper
+	demux   Demux
+}
+
+// Handle passes the message to the wrapped Demux unless it is a duplicate.
+func (d dedupeDemux) Handle(message interface{}) {
+	if !d.deduper.Duplicate(message) {
+		d.demux.Handle(message)
+	}
+}
+
+// HandleChan passes messages which are not duplicates to the wrapped Demux.
+func (d dedupeDemux) HandleChan(messages <-chan interface{}) {
+	for message := range messages {
+		d.Handle(message)
+	}
+}
@@ -0,0 +1,105 @@
+package twitter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeduper_Duplicate(t *testing.T) {
+	deduper := NewDeduper(nil)
+	tweet := &Tweet{ID: 20}
+	assert.False(t, deduper.Duplicate(tweet))
+	assert.True(t, deduper.Duplicate(&Tweet{ID: 20}))
+	assert.True(t, deduper.Duplicate(&BackfilledTweet{Tweet: tweet}))
+	assert.False(t, deduper.Duplicate(&StreamLimit{}))
+	assert.False(t, deduper.Duplicate(&StreamLimit{}))
+	// retweets are distinct Tweets by default
+	assert.False(t, deduper.Duplicate(&Tweet{ID: 21, RetweetedStatus: tweet}))
+	assert.Equal(t, DedupeStats{Tweets: 4, Suppressed: 2}, deduper.Stats())
+}
+
+func TestDeduper_Retweets(t *testing.T) {
+	deduper := NewDeduper(&DeduperParams{Retweets: true})
+	original := &Tweet{ID: 20}
+	assert.False(t, deduper.Duplicate(&Tweet{ID: 21, RetweetedStatus: original}))
+	assert.True(t, deduper.Duplicate(&Tweet{ID: 22, RetweetedStatus: original}))
+	// the original is delivered even after its retweets
+	assert.False(t, deduper.Duplicate(original))
+	assert.True(t, deduper.Duplicate(original))
+	assert.True(t, deduper.Duplicate(&Tweet{ID: 23, RetweetedStatus: original}))
+	assert.False(t, deduper.Duplicate(&Tweet{ID: 24, RetweetedStatus: &Tweet{ID: 19}}))
+}
+
+func TestDeduper_Size(t *testing.T) {
+	deduper := NewDeduper(&DeduperParams{Size: 2})
+	assert.False(t, deduper.Duplicate(&Tweet{ID: 1}))
+	assert.False(t, deduper.Duplicate(&Tweet{ID: 2}))
+	// seeing 1 again makes 2 the least recently seen
+	assert.True(t, deduper.Duplicate(&Tweet{ID: 1}))
+	assert.False(t, deduper.Duplicate(&Tweet{ID: 3}))
+	assert.False(t, deduper.Duplicate(&Tweet{ID: 2}))
+	assert.True(t, deduper.Duplicate(&Tweet{ID: 3}))
+}
+
+func TestDeduper_Window(t *testing.T) {
+	now := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	deduper := NewDeduper(&DeduperParams{Window: time.Minute})
+	deduper.now = func() time.Time { return now }
+	assert.False(t, deduper.Duplicate(&Tweet{ID: 1}))
+	now = now.Add(30 * time.Second)
+	assert.True(t, deduper.Duplicate(&Tweet{ID: 1}))
+	now = now.Add(time.Minute)
+	assert.False(t, deduper.Duplicate(&Tweet{ID: 1}))
+}
+
+func TestDeduper_Chan(t *testing.T) {
+	in := make(chan interface{})
+	go func() {
+		for _, id := range []int64{1, 2, 1, 3, 2} {
+			in <- &Tweet{ID: id}
+		}
+		close(in)
+	}()
+	var ids []int64
+	for message := range NewDeduper(nil).Chan(in, nil) {
+		ids = append(ids, message.(*Tweet).ID)
+	}
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+}
+
+func TestDeduper_ChanDone(t *testing.T) {
+	in := make(chan interface{})
+	done := make(chan struct{})
+	out := NewDeduper(nil).Chan(in, done)
+	in <- &Tweet{ID: 1}
+	// the consumer stops reading, closing done stops the goroutine
+	close(done)
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-out:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("expected the Chan goroutine to stop")
+		}
+	}
+}
+
+func TestDeduper_Demux(t *testing.T) {
+	counts := &counter{}
+	demux := NewDeduper(nil).Demux(newCounterDemux(counts))
+	ch := make(chan interface{})
+	go func() {
+		ch <- &Tweet{ID: 1}
+		ch <- &BackfilledTweet{Tweet: &Tweet{ID: 1}}
+		ch <- &Tweet{ID: 2}
+		ch <- &StallWarning{}
+		close(ch)
+	}()
+	demux.HandleChan(ch)
+	assert.Equal(t, &counter{all: 3, tweet: 2, stallWarning: 1}, counts)
+}
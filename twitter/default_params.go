@@ -0,0 +1,198 @@
+package twitter
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dghubble/sling"
+)
+
+const formContentType = "application/x-www-form-urlencoded"
+
+// DefaultParams are parameters a Client adds to every request to an
+// endpoint which supports them, unless the request sets them itself. For
+// example, set TweetMode "extended" so Tweets are never truncated.
+type DefaultParams struct {
+	TweetMode         string
+	IncludeEntities   *bool
+	IncludeExtAltText *bool
+	TrimUser          *bool
+	SkipStatus        *bool
+}
+
+// values returns the default parameters which are set.
+func (p *DefaultParams) values() url.Values {
+	values := url.Values{}
+	if p.TweetMode != "" {
+		values.Set("tweet_mode", p.TweetMode)
+	}
+	for key, value := range map[string]*bool{
+		"include_entities":     p.IncludeEntities,
+		"include_ext_alt_text": p.IncludeExtAltText,
+		"trim_user":            p.TrimUser,
+		"skip_status":          p.SkipStatus,
+	} {
+		if value != nil {
+			values.Set(key, strconv.FormatBool(*value))
+		}
+	}
+	return values
+}
+
+// WithDefaultParams adds the DefaultParams to requests to the REST API
+// endpoints which support them, unless the per-call params set them.
+func WithDefaultParams(params *DefaultParams) ClientOption {
+	return func(o *clientOptions) {
+		o.defaultParams = params
+	}
+}
+
+// Default parameters supported by REST API endpoints, by path relative to
+// the API base URL. An ":id.json" segment matches any ID.
+var (
+	tweetParams = []string{"tweet_mode", "include_entities", "include_ext_alt_text", "trim_user"}
+	userParams  = []string{"tweet_mode", "include_entities", "skip_status"}
+
+	defaultParamsSupport = map[string][]string{
+		"statuses/show.json":              tweetParams,
+		"statuses/lookup.json":            tweetParams,
+		"statuses/update.json":            {"tweet_mode", "trim_user"},
+		"statuses/destroy/:id.json":       {"tweet_mode", "trim_user"},
+		"statuses/retweet/:id.json":       {"tweet_mode", "trim_user"},
+		"statuses/unretweet/:id.json":     {"tweet_mode", "trim_user"},
+		"statuses/retweets/:id.json":      {"tweet_mode", "trim_user"},
+		"statuses/home_timeline.json":     tweetParams,
+		"statuses/user_timeline.json":     tweetParams,
+		"statuses/mentions_timeline.json": tweetParams,
+		"statuses/retweets_of_me.json":    tweetParams,
+		"search/tweets.json":              {"tweet_mode", "include_entities", "include_ext_alt_text"},
+		"favorites/list.json":             {"tweet_mode", "include_entities", "include_ext_alt_text"},
+		"favorites/create.json":           {"tweet_mode", "include_entities"},
+		"favorites/destroy.json":          {"tweet_mode", "include_entities"},
+		"lists/statuses.json":             {"tweet_mode", "include_entities", "include_ext_alt_text"},
+		"account/verify_credentials.json": userParams,
+		"account/update_profile.json":     userParams,
+		"users/show.json":                 {"tweet_mode", "include_entities"},
+		"users/lookup.json":               {"tweet_mode", "include_entities"},
+		"users/search.json":               {"tweet_mode", "include_entities"},
+		"blocks/create.json":              userParams,
+		"blocks/destroy.json":             userParams,
+		"followers/list.json":             {"tweet_mode", "skip_status"},
+		"friends/list.json":               {"tweet_mode", "skip_status"},
+		"lists/members.json":              userParams,
+		"lists/members/show.json":         userParams,
+		"lists/subscribers.json":          userParams,
+		"lists/subscribers/show.json":     userParams,
+		"direct_messages.json":            {"include_entities", "skip_status"},
+		"direct_messages/sent.json":       {"include_entities"},
+		"direct_messages/destroy.json":    {"include_entities"},
+		"collections/entries.json":        {"tweet_mode"},
+		"mutes/users/list.json":           userParams,
+	}
+)
+
+// supportedDefaultParams returns the default parameters supported by the
+// endpoint at the path, relative to the API base URL.
+func supportedDefaultParams(path string) []string {
+	if params, ok := defaultParamsSupport[path]; ok {
+		return params
+	}
+	segments := strings.Split(path, "/")
+	for pattern, params := range defaultParamsSupport {
+		patternSegments := strings.Split(pattern, "/")
+		if len(patternSegments) != len(segments) {
+			continue
+		}
+		matched := true
+		for i, segment := range patternSegments {
+			if segment != segments[i] && !(segment == ":id.json" && strings.HasSuffix(segments[i], ".json")) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return params
+		}
+	}
+	return nil
+}
+
+// defaultParamsDoer is a sling.Doer which adds default parameters to
+// requests before sending them with the wrapped Doer.
+type defaultParamsDoer struct {
+	doer   sling.Doer
+	base   *url.URL
+	params url.Values
+}
+
+// newDefaultParamsDoer returns a Doer which adds the params to requests to
+// endpoints under the base URL before sending them with the http.Client.
+func newDefaultParamsDoer(httpClient *http.Client, base string, params *DefaultParams) *defaultParamsDoer {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	baseURL, _ := url.Parse(base)
+	return &defaultParamsDoer{
+		doer:   httpClient,
+		base:   baseURL,
+		params: params.values(),
+	}
+}
+
+// Do adds the default parameters the request's endpoint supports and which
+// the request does not already set, then sends the request. Form bodies
+// receive the parameters, otherwise the query does.
+func (d *defaultParamsDoer) Do(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != d.base.Host || !strings.HasPrefix(req.URL.Path, d.base.Path) {
+		return d.doer.Do(req)
+	}
+	supported := supportedDefaultParams(strings.TrimPrefix(req.URL.Path, d.base.Path))
+	if len(supported) == 0 {
+		return d.doer.Do(req)
+	}
+	query := req.URL.Query()
+	isForm := strings.HasPrefix(req.Header.Get("Content-Type"), formContentType)
+	var form url.Values
+	if isForm && req.Body != nil {
+		data, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if form, err = url.ParseQuery(string(data)); err != nil {
+			return nil, err
+		}
+	}
+	added := false
+	for _, key := range supported {
+		value, ok := d.params[key]
+		if !ok || query.Get(key) != "" || form.Get(key) != "" {
+			continue
+		}
+		if isForm {
+			if form == nil {
+				form = url.Values{}
+			}
+			form[key] = value
+		} else {
+			query[key] = value
+		}
+		added = true
+	}
+	if isForm {
+		body := form.Encode()
+		req.Body = ioutil.NopCloser(strings.NewReader(body))
+		req.ContentLength = int64(len(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(strings.NewReader(body)), nil
+		}
+	}
+	if added && !isForm {
+		req.URL.RawQuery = query.Encode()
+	}
+	return d.doer.Do(req)
+}
@@ -0,0 +1,96 @@
+package twitter
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDefaultParams_query(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+
+	expected := map[string]string{"screen_name": "golang", "tweet_mode": "extended", "include_entities": "true", "trim_user": "true"}
+	mux.HandleFunc("/1.1/statuses/user_timeline.json", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, "GET", r)
+		assertQuery(t, expected, r)
+		fmt.Fprintf(w, `[]`)
+	})
+	mux.HandleFunc("/1.1/followers/ids.json", func(w http.ResponseWriter, r *http.Request) {
+		assertQuery(t, map[string]string{"screen_name": "golang"}, r)
+		fmt.Fprintf(w, `{}`)
+	})
+
+	client := NewClient(httpClient, WithDefaultParams(&DefaultParams{
+		TweetMode:       "extended",
+		IncludeEntities: Bool(true),
+		TrimUser:        Bool(true),
+		SkipStatus:      Bool(true),
+	}))
+	_, _, err := client.Timelines.UserTimeline(&UserTimelineParams{ScreenName: "golang"})
+	assert.Nil(t, err)
+	// per-call params override defaults
+	expected["trim_user"] = "false"
+	expected["tweet_mode"] = "compat"
+	_, _, err = client.Timelines.UserTimeline(&UserTimelineParams{ScreenName: "golang", TrimUser: Bool(false), TweetMode: "compat"})
+	assert.Nil(t, err)
+	// endpoints which don't support the params don't receive them
+	_, _, err = client.Followers.IDs(&FollowerIDParams{ScreenName: "golang"})
+	assert.Nil(t, err)
+}
+
+func TestWithDefaultParams_form(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+
+	mux.HandleFunc("/1.1/statuses/update.json", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, "POST", r)
+		assertQuery(t, map[string]string{}, r)
+		assertPostForm(t, map[string]string{"status": "very informative tweet", "tweet_mode": "extended", "trim_user": "false"}, r)
+		fmt.Fprintf(w, `{"id": 581980947630845953}`)
+	})
+	mux.HandleFunc("/1.1/statuses/destroy/20.json", func(w http.ResponseWriter, r *http.Request) {
+		assertPostForm(t, map[string]string{"id": "20", "tweet_mode": "extended", "trim_user": "false"}, r)
+		fmt.Fprintf(w, `{"id": 20}`)
+	})
+
+	client := NewClient(httpClient, WithDefaultParams(&DefaultParams{TweetMode: "extended", TrimUser: Bool(false)}))
+	tweet, _, err := client.Statuses.Update("very informative tweet", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(581980947630845953), tweet.ID)
+	_, _, err = client.Statuses.Destroy(20, nil)
+	assert.Nil(t, err)
+}
+
+func TestWithDefaultParams_postQuery(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+
+	mux.HandleFunc("/1.1/favorites/create.json", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, "POST", r)
+		assertQuery(t, map[string]string{"id": "12345", "tweet_mode": "extended"}, r)
+		fmt.Fprintf(w, `{"id": 12345}`)
+	})
+
+	client := NewClient(httpClient, WithDefaultParams(&DefaultParams{TweetMode: "extended"}))
+	_, _, err := client.Favorites.Create(&FavoriteCreateParams{ID: 12345})
+	assert.Nil(t, err)
+}
+
+func TestSupportedDefaultParams(t *testing.T) {
+	cases := []struct {
+		path     string
+		expected []string
+	}{
+		{"statuses/show.json", tweetParams},
+		{"statuses/retweet/123.json", []string{"tweet_mode", "trim_user"}},
+		{"lists/members/show.json", userParams},
+		{"statuses/oembed.json", nil},
+		{"followers/ids.json", nil},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, supportedDefaultParams(c.path), c.path)
+	}
+}
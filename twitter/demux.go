@@ -13,6 +13,7 @@ type Demux interface {
 type SwitchDemux struct {
 	All              func(message interface{})
 	Tweet            func(tweet *Tweet)
+	BackfilledTweet  func(tweet *BackfilledTweet)
 	DM               func(dm *DirectMessage)
 	StatusDeletion   func(deletion *StatusDeletion)
 	LocationDeletion func(LocationDeletion *LocationDeletion)
@@ -23,7 +24,11 @@ type SwitchDemux struct {
 	Warning          func(warning *StallWarning)
 	FriendsList      func(friendsList *FriendsList)
 	Event            func(event *Event)
-	Other            func(message interface{})
+	SiteMessage      func(message *SiteStreamMessage)
+	StreamControl    func(control *StreamControl)
+	// Events handlers are called for Events of their kind, after Event
+	Events map[EventKind]func(event *Event)
+	Other  func(message interface{})
 }
 
 // NewSwitchDemux returns a new SwitchMux which has NoOp handler functions.
@@ -31,6 +36,7 @@ func NewSwitchDemux() SwitchDemux {
 	return SwitchDemux{
 		All:              func(message interface{}) {},
 		Tweet:            func(tweet *Tweet) {},
+		BackfilledTweet:  func(tweet *BackfilledTweet) {},
 		DM:               func(dm *DirectMessage) {},
 		StatusDeletion:   func(deletion *StatusDeletion) {},
 		LocationDeletion: func(LocationDeletion *LocationDeletion) {},
@@ -41,6 +47,9 @@ func NewSwitchDemux() SwitchDemux {
 		Warning:          func(warning *StallWarning) {},
 		FriendsList:      func(friendsList *FriendsList) {},
 		Event:            func(event *Event) {},
+		SiteMessage:      func(message *SiteStreamMessage) {},
+		StreamControl:    func(control *StreamControl) {},
+		Events:           make(map[EventKind]func(event *Event)),
 		Other:            func(message interface{}) {},
 	}
 }
@@ -53,6 +62,8 @@ func (d SwitchDemux) Handle(message interface{}) {
 	switch msg := message.(type) {
 	case *Tweet:
 		d.Tweet(msg)
+	case *BackfilledTweet:
+		d.BackfilledTweet(msg)
 	case *DirectMessage:
 		d.DM(msg)
 	case *StatusDeletion:
@@ -73,6 +84,13 @@ func (d SwitchDemux) Handle(message interface{}) {
 		d.FriendsList(msg)
 	case *Event:
 		d.Event(msg)
+		if handler := d.Events[msg.Event]; handler != nil {
+			handler(msg)
+		}
+	case *SiteStreamMessage:
+		d.SiteMessage(msg)
+	case *StreamControl:
+		d.StreamControl(msg)
 	default:
 		d.Other(msg)
 	}
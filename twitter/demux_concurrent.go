@@ -0,0 +1,184 @@
+package twitter
+
+import (
+	"runtime"
+	"sync"
+)
+
+// default number of messages buffered per ConcurrentDemux worker
+const concurrentDemuxBuffer = 100
+
+// ConcurrentDemuxParams are the parameters for NewConcurrentDemux.
+type ConcurrentDemuxParams struct {
+	// number of worker goroutines (default runtime.NumCPU())
+	Workers int
+	// number of messages buffered per worker (default 100)
+	Buffer int
+	// Key returns the ordering key of a message (default KeyByUser)
+	Key func(message interface{}) int64
+	// Panic is called with the message and recovered value if the Demux
+	// panics while handling a message. If nil, panics are not recovered.
+	Panic func(message interface{}, recovered interface{})
+}
+
+// ConcurrentDemux passes messages to a Demux from a pool of worker
+// goroutines, so one slow handler does not hold up the whole stream.
+// Messages with the same key are always handled by the same worker, in the
+// order they were received, while messages with different keys may be
+// handled in parallel. The wrapped Demux must be safe for concurrent use.
+//
+// The client must Close() the ConcurrentDemux when finished, which waits
+// until queued messages have been handled.
+type ConcurrentDemux struct {
+	demux   Demux
+	key     func(message interface{}) int64
+	panic   func(message interface{}, recovered interface{})
+	workers []chan queuedMessage
+	mu      sync.RWMutex
+	closed  bool
+	// Handle calls which are sending to a worker
+	sending sync.WaitGroup
+	group   sync.WaitGroup
+}
+
+// queuedMessage is a message queued for a worker, with the WaitGroup of the
+// HandleChan call which queued it, if any.
+type queuedMessage struct {
+	message interface{}
+	handled *sync.WaitGroup
+}
+
+// NewConcurrentDemux returns a ConcurrentDemux which passes messages to the
+// given Demux and starts its worker goroutines.
+func NewConcurrentDemux(demux Demux, params *ConcurrentDemuxParams) *ConcurrentDemux {
+	if params == nil {
+		params = &ConcurrentDemuxParams{}
+	}
+	workers := params.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	buffer := params.Buffer
+	if buffer <= 0 {
+		buffer = concurrentDemuxBuffer
+	}
+	d := &ConcurrentDemux{
+		demux:   demux,
+		key:     params.Key,
+		panic:   params.Panic,
+		workers: make([]chan queuedMessage, workers),
+	}
+	if d.key == nil {
+		d.key = KeyByUser
+	}
+	for i := range d.workers {
+		d.workers[i] = make(chan queuedMessage, buffer)
+		d.group.Add(1)
+		go d.work(d.workers[i])
+	}
+	return d
+}
+
+// Handle queues the message for the worker responsible for its key. Blocks
+// while that worker's buffer is full. Messages received after Close() are
+// dropped.
+func (d *ConcurrentDemux) Handle(message interface{}) {
+	d.queue(queuedMessage{message: message})
+}
+
+// HandleChan queues messages until the channel is closed, then waits until
+// they have been handled.
+func (d *ConcurrentDemux) HandleChan(messages <-chan interface{}) {
+	var handled sync.WaitGroup
+	for message := range messages {
+		handled.Add(1)
+		if !d.queue(queuedMessage{message: message, handled: &handled}) {
+			handled.Done()
+		}
+	}
+	handled.Wait()
+}
+
+// queue sends the message to the worker responsible for its key, without
+// holding the lock while blocked. Returns false if the message was dropped
+// because the ConcurrentDemux is closed.
+func (d *ConcurrentDemux) queue(queued queuedMessage) bool {
+	d.mu.RLock()
+	if d.closed {
+		d.mu.RUnlock()
+		return false
+	}
+	// Close waits for sends which started before it
+	d.sending.Add(1)
+	d.mu.RUnlock()
+	defer d.sending.Done()
+	key := uint64(d.key(queued.message))
+	d.workers[key%uint64(len(d.workers))] <- queued
+	return true
+}
+
+// Close stops accepting messages and blocks until queued messages have been
+// handled and the workers have stopped.
+func (d *ConcurrentDemux) Close() {
+	d.mu.Lock()
+	wasClosed := d.closed
+	d.closed = true
+	d.mu.Unlock()
+	if !wasClosed {
+		// workers keep handling messages, so blocked sends complete
+		d.sending.Wait()
+		for _, worker := range d.workers {
+			close(worker)
+		}
+	}
+	d.group.Wait()
+}
+
+// work handles the messages queued for a worker until it is closed.
+func (d *ConcurrentDemux) work(messages <-chan queuedMessage) {
+	defer d.group.Done()
+	for queued := range messages {
+		d.handle(queued)
+	}
+}
+
+// handle passes the message to the wrapped Demux, recovering from panics if
+// there is a Panic handler.
+func (d *ConcurrentDemux) handle(queued queuedMessage) {
+	if queued.handled != nil {
+		defer queued.handled.Done()
+	}
+	if d.panic != nil {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				d.panic(queued.message, recovered)
+			}
+		}()
+	}
+	d.demux.Handle(queued.message)
+}
+
+// KeyByUser returns the ID of the user who authored a Tweet or sent a
+// Direct Message, the source user of an Event, or the user a Site Stream
+// message is for. Other messages have key 0.
+func KeyByUser(message interface{}) int64 {
+	switch msg := message.(type) {
+	case *Tweet:
+		if msg.User != nil {
+			return msg.User.ID
+		}
+	case *BackfilledTweet:
+		if msg.Tweet != nil && msg.User != nil {
+			return msg.User.ID
+		}
+	case *DirectMessage:
+		return msg.SenderID
+	case *Event:
+		if msg.Source != nil {
+			return msg.Source.ID
+		}
+	case *SiteStreamMessage:
+		return msg.ForUser
+	}
+	return 0
+}
@@ -0,0 +1,200 @@
+package twitter
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentDemux_Ordering(t *testing.T) {
+	var mu sync.Mutex
+	handled := make(map[int64][]int64)
+	demux := NewSwitchDemux()
+	demux.Tweet = func(tweet *Tweet) {
+		// slow handling for one user shouldn't reorder others
+		if tweet.User.ID == 1 {
+			time.Sleep(time.Millisecond)
+		}
+		mu.Lock()
+		handled[tweet.User.ID] = append(handled[tweet.User.ID], tweet.ID)
+		mu.Unlock()
+	}
+	concurrent := NewConcurrentDemux(demux, &ConcurrentDemuxParams{Workers: 4, Buffer: 1})
+	ch := make(chan interface{})
+	go func() {
+		for id := int64(0); id < 40; id++ {
+			ch <- &Tweet{ID: id, User: &User{ID: id % 3}}
+		}
+		close(ch)
+	}()
+	concurrent.HandleChan(ch)
+	concurrent.Close()
+
+	assert.Len(t, handled, 3)
+	for userID, ids := range handled {
+		var expected []int64
+		for id := userID; id < 40; id += 3 {
+			expected = append(expected, id)
+		}
+		assert.Equal(t, expected, ids, "user %d", userID)
+	}
+}
+
+func TestConcurrentDemux_Parallel(t *testing.T) {
+	release := make(chan struct{})
+	handled := make(chan int64, 2)
+	demux := NewSwitchDemux()
+	demux.Tweet = func(tweet *Tweet) {
+		if tweet.User.ID == 1 {
+			// block until another key has been handled
+			<-release
+		}
+		handled <- tweet.ID
+	}
+	concurrent := NewConcurrentDemux(demux, &ConcurrentDemuxParams{Workers: 2})
+	concurrent.Handle(&Tweet{ID: 10, User: &User{ID: 1}})
+	concurrent.Handle(&Tweet{ID: 20, User: &User{ID: 2}})
+	select {
+	case id := <-handled:
+		assert.Equal(t, int64(20), id)
+	case <-time.After(defaultTestTimeout):
+		t.Fatal("expected Tweet with a different key to be handled")
+	}
+	close(release)
+	concurrent.Close()
+	assert.Equal(t, int64(10), <-handled)
+}
+
+func TestConcurrentDemux_Panic(t *testing.T) {
+	var mu sync.Mutex
+	var recovered []interface{}
+	counts := &counter{}
+	demux := NewSwitchDemux()
+	demux.Tweet = func(tweet *Tweet) {
+		if tweet.ID == 1 {
+			panic("handler failed")
+		}
+		counts.tweet++
+	}
+	concurrent := NewConcurrentDemux(demux, &ConcurrentDemuxParams{
+		Workers: 1,
+		Panic: func(message interface{}, value interface{}) {
+			mu.Lock()
+			defer mu.Unlock()
+			recovered = append(recovered, value)
+		},
+	})
+	concurrent.Handle(&Tweet{ID: 1})
+	concurrent.Handle(&Tweet{ID: 2})
+	concurrent.Close()
+	assert.Equal(t, []interface{}{"handler failed"}, recovered)
+	assert.Equal(t, 1, counts.tweet)
+}
+
+func TestConcurrentDemux_Close(t *testing.T) {
+	counts := &counter{}
+	concurrent := NewConcurrentDemux(newCounterDemux(counts), &ConcurrentDemuxParams{Workers: 1})
+	messages, _ := exampleMessages()
+	for _, message := range messages {
+		concurrent.Handle(message)
+	}
+	// queued messages are drained on Close
+	concurrent.Close()
+	assert.Equal(t, len(messages), counts.all)
+	// messages after Close are dropped
+	concurrent.Handle(&Tweet{})
+	concurrent.Close()
+	assert.Equal(t, len(messages), counts.all)
+}
+
+func TestConcurrentDemux_CloseWhileBlocked(t *testing.T) {
+	var mu sync.Mutex
+	var handled []int64
+	release := make(chan struct{})
+	var concurrent *ConcurrentDemux
+	demux := NewSwitchDemux()
+	demux.Tweet = func(tweet *Tweet) {
+		if tweet.ID == 1 {
+			<-release
+			// handlers may queue messages while Close is waiting
+			concurrent.Handle(&Tweet{ID: 100})
+		}
+		mu.Lock()
+		handled = append(handled, tweet.ID)
+		mu.Unlock()
+	}
+	concurrent = NewConcurrentDemux(demux, &ConcurrentDemuxParams{Workers: 1, Buffer: 1})
+	concurrent.Handle(&Tweet{ID: 1})
+	concurrent.Handle(&Tweet{ID: 2})
+	// blocks until the worker has room
+	go concurrent.Handle(&Tweet{ID: 3})
+	closed := make(chan struct{})
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		concurrent.Close()
+		close(closed)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	select {
+	case <-closed:
+	case <-time.After(defaultTestTimeout):
+		t.Fatal("expected Close to return")
+	}
+	assert.Equal(t, []int64{1, 2, 3}, handled)
+}
+
+func TestConcurrentDemux_HandleChanConcurrent(t *testing.T) {
+	counts := &counter{}
+	var mu sync.Mutex
+	demux := NewSwitchDemux()
+	demux.Tweet = func(tweet *Tweet) {
+		mu.Lock()
+		counts.tweet++
+		mu.Unlock()
+	}
+	concurrent := NewConcurrentDemux(demux, &ConcurrentDemuxParams{Workers: 2})
+	ch := make(chan interface{})
+	done := make(chan struct{})
+	go func() {
+		// other goroutines may Handle messages while HandleChan waits
+		for i := 0; i < 100; i++ {
+			concurrent.Handle(&Tweet{ID: int64(i), User: &User{ID: int64(i)}})
+		}
+		close(done)
+	}()
+	go func() {
+		for i := 0; i < 100; i++ {
+			ch <- &Tweet{ID: int64(i), User: &User{ID: int64(i)}}
+		}
+		close(ch)
+	}()
+	concurrent.HandleChan(ch)
+	<-done
+	concurrent.Close()
+	assert.Equal(t, 200, counts.tweet)
+}
+
+func TestConcurrentDemux_PanicWithoutHandler(t *testing.T) {
+	demux := NewSwitchDemux()
+	demux.Tweet = func(tweet *Tweet) {
+		panic("handler failed")
+	}
+	concurrent := NewConcurrentDemux(demux, &ConcurrentDemuxParams{Workers: 1})
+	defer concurrent.Close()
+	// without a Panic handler, panics are not recovered
+	assert.PanicsWithValue(t, "handler failed", func() {
+		concurrent.handle(queuedMessage{message: &Tweet{}})
+	})
+}
+
+func TestKeyByUser(t *testing.T) {
+	assert.Equal(t, int64(12), KeyByUser(&Tweet{User: &User{ID: 12}}))
+	assert.Equal(t, int64(12), KeyByUser(&BackfilledTweet{Tweet: &Tweet{User: &User{ID: 12}}}))
+	assert.Equal(t, int64(13), KeyByUser(&DirectMessage{SenderID: 13}))
+	assert.Equal(t, int64(14), KeyByUser(&Event{Source: &User{ID: 14}}))
+	assert.Equal(t, int64(0), KeyByUser(&Tweet{}))
+	assert.Equal(t, int64(0), KeyByUser(&StreamLimit{}))
+}
@@ -0,0 +1,247 @@
+package twitter
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// DeadLetter is a message which a handler failed to handle, even after
+// retrying.
+type DeadLetter struct {
+	Message  interface{}
+	Handler  string
+	Err      error
+	Attempts int
+	Time     time.Time
+}
+
+// A DeadLetterSink receives DeadLetters for later inspection or
+// reprocessing.
+type DeadLetterSink interface {
+	DeadLetter(letter *DeadLetter)
+}
+
+// DeadLetterFunc is an adapter to allow a function to be used as a
+// DeadLetterSink.
+type DeadLetterFunc func(letter *DeadLetter)
+
+// DeadLetter calls f(letter).
+func (f DeadLetterFunc) DeadLetter(letter *DeadLetter) {
+	f(letter)
+}
+
+// DeadLetterWriter is a DeadLetterSink which writes each DeadLetter as a
+// line of JSON. Messages are written as their original JSON if it was
+// retained (see WithRawJSON), otherwise as their JSON encoding, and error
+// messages as their error string.
+type DeadLetterWriter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	err     error
+}
+
+// NewDeadLetterWriter returns a DeadLetterWriter which writes to w.
+func NewDeadLetterWriter(w io.Writer) *DeadLetterWriter {
+	return &DeadLetterWriter{encoder: json.NewEncoder(w)}
+}
+
+type deadLetterRecord struct {
+	Handler  string          `json:"handler"`
+	Error    string          `json:"error"`
+	Attempts int             `json:"attempts"`
+	Time     time.Time       `json:"time"`
+	Message  json.RawMessage `json:"message"`
+	// why the message could not be encoded, if it couldn't
+	MessageError string `json:"message_error,omitempty"`
+}
+
+// DeadLetter writes the DeadLetter as a line of JSON. If the message can't
+// be encoded, the DeadLetter is written with a null message and the
+// encoding error. Write errors are reported by Err.
+func (w *DeadLetterWriter) DeadLetter(letter *DeadLetter) {
+	record := deadLetterRecord{
+		Handler:  letter.Handler,
+		Attempts: letter.Attempts,
+		Time:     letter.Time,
+	}
+	if letter.Err != nil {
+		record.Error = letter.Err.Error()
+	}
+	message, err := deadLetterMessage(letter.Message)
+	if err != nil {
+		record.MessageError = err.Error()
+	} else {
+		record.Message = message
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.encoder.Encode(record); err != nil && w.err == nil {
+		w.err = err
+	}
+}
+
+// Err returns the first error writing a DeadLetter, if any.
+func (w *DeadLetterWriter) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// deadLetterMessage returns the original JSON of the message if it was
+// retained, the error string of error messages, or the message's JSON
+// encoding.
+func deadLetterMessage(message interface{}) (json.RawMessage, error) {
+	var raw json.RawMessage
+	switch msg := message.(type) {
+	case *Tweet:
+		raw = msg.Raw
+	case *BackfilledTweet:
+		if msg.Tweet != nil {
+			raw = msg.Raw
+		}
+	case *DirectMessage:
+		raw = msg.Raw
+	case *UnknownMessage:
+		raw = msg.Raw
+	case error:
+		return json.Marshal(msg.Error())
+	}
+	if len(raw) > 0 {
+		return raw, nil
+	}
+	return json.Marshal(message)
+}
+
+// ErrorSwitchDemux receives messages and uses a type switch to send each
+// typed message to a handler function which may return an error. Handlers
+// which return an error are retried, and messages which still fail are sent
+// to the DeadLetters sink along with the error and the handler's name.
+type ErrorSwitchDemux struct {
+	All              func(message interface{}) error
+	Tweet            func(tweet *Tweet) error
+	BackfilledTweet  func(tweet *BackfilledTweet) error
+	DM               func(dm *DirectMessage) error
+	StatusDeletion   func(deletion *StatusDeletion) error
+	LocationDeletion func(LocationDeletion *LocationDeletion) error
+	StreamLimit      func(limit *StreamLimit) error
+	StatusWithheld   func(statusWithheld *StatusWithheld) error
+	UserWithheld     func(userWithheld *UserWithheld) error
+	StreamDisconnect func(disconnect *StreamDisconnect) error
+	Warning          func(warning *StallWarning) error
+	FriendsList      func(friendsList *FriendsList) error
+	Event            func(event *Event) error
+	SiteMessage      func(message *SiteStreamMessage) error
+	StreamControl    func(control *StreamControl) error
+	// Events handlers are called for Events of their kind, after Event
+	Events map[EventKind]func(event *Event) error
+	Other  func(message interface{}) error
+	// number of times a failing handler is retried
+	Retries int
+	// duration to wait between retries
+	RetryWait time.Duration
+	// DeadLetters receives messages which failed every attempt (optional)
+	DeadLetters DeadLetterSink
+}
+
+// NewErrorSwitchDemux returns a new ErrorSwitchDemux which has NoOp handler
+// functions and does not retry.
+func NewErrorSwitchDemux() ErrorSwitchDemux {
+	return ErrorSwitchDemux{
+		All:              func(message interface{}) error { return nil },
+		Tweet:            func(tweet *Tweet) error { return nil },
+		BackfilledTweet:  func(tweet *BackfilledTweet) error { return nil },
+		DM:               func(dm *DirectMessage) error { return nil },
+		StatusDeletion:   func(deletion *StatusDeletion) error { return nil },
+		LocationDeletion: func(LocationDeletion *LocationDeletion) error { return nil },
+		StreamLimit:      func(limit *StreamLimit) error { return nil },
+		StatusWithheld:   func(statusWithheld *StatusWithheld) error { return nil },
+		UserWithheld:     func(userWithheld *UserWithheld) error { return nil },
+		StreamDisconnect: func(disconnect *StreamDisconnect) error { return nil },
+		Warning:          func(warning *StallWarning) error { return nil },
+		FriendsList:      func(friendsList *FriendsList) error { return nil },
+		Event:            func(event *Event) error { return nil },
+		SiteMessage:      func(message *SiteStreamMessage) error { return nil },
+		StreamControl:    func(control *StreamControl) error { return nil },
+		Events:           make(map[EventKind]func(event *Event) error),
+		Other:            func(message interface{}) error { return nil },
+	}
+}
+
+// Handle determines the type of a message and calls the corresponding
+// receiver function with the typed message, retrying on errors. All
+// messages are passed to the All func. Messages with unmatched types are
+// passed to the Other func.
+func (d ErrorSwitchDemux) Handle(message interface{}) {
+	d.call("All", message, func() error { return d.All(message) })
+	switch msg := message.(type) {
+	case *Tweet:
+		d.call("Tweet", message, func() error { return d.Tweet(msg) })
+	case *BackfilledTweet:
+		d.call("BackfilledTweet", message, func() error { return d.BackfilledTweet(msg) })
+	case *DirectMessage:
+		d.call("DM", message, func() error { return d.DM(msg) })
+	case *StatusDeletion:
+		d.call("StatusDeletion", message, func() error { return d.StatusDeletion(msg) })
+	case *LocationDeletion:
+		d.call("LocationDeletion", message, func() error { return d.LocationDeletion(msg) })
+	case *StreamLimit:
+		d.call("StreamLimit", message, func() error { return d.StreamLimit(msg) })
+	case *StatusWithheld:
+		d.call("StatusWithheld", message, func() error { return d.StatusWithheld(msg) })
+	case *UserWithheld:
+		d.call("UserWithheld", message, func() error { return d.UserWithheld(msg) })
+	case *StreamDisconnect:
+		d.call("StreamDisconnect", message, func() error { return d.StreamDisconnect(msg) })
+	case *StallWarning:
+		d.call("Warning", message, func() error { return d.Warning(msg) })
+	case *FriendsList:
+		d.call("FriendsList", message, func() error { return d.FriendsList(msg) })
+	case *Event:
+		d.call("Event", message, func() error { return d.Event(msg) })
+		if handler := d.Events[msg.Event]; handler != nil {
+			d.call(string(msg.Event), message, func() error { return handler(msg) })
+		}
+	case *SiteStreamMessage:
+		d.call("SiteMessage", message, func() error { return d.SiteMessage(msg) })
+	case *StreamControl:
+		d.call("StreamControl", message, func() error { return d.StreamControl(msg) })
+	default:
+		d.call("Other", message, func() error { return d.Other(msg) })
+	}
+}
+
+// HandleChan receives messages and calls the corresponding receiver function
+// with the typed message, retrying on errors. All messages are passed to the
+// All func. Messages with unmatched type are passed to the Other func.
+func (d ErrorSwitchDemux) HandleChan(messages <-chan interface{}) {
+	for message := range messages {
+		d.Handle(message)
+	}
+}
+
+// call calls the handler until it succeeds or retries are exhausted, then
+// sends the message to the DeadLetters sink if it failed.
+func (d ErrorSwitchDemux) call(name string, message interface{}, handler func() error) {
+	var err error
+	attempts := 0
+	for attempts <= d.Retries {
+		if attempts > 0 && d.RetryWait > 0 {
+			time.Sleep(d.RetryWait)
+		}
+		attempts++
+		if err = handler(); err == nil {
+			return
+		}
+	}
+	if d.DeadLetters != nil {
+		d.DeadLetters.DeadLetter(&DeadLetter{
+			Message:  message,
+			Handler:  name,
+			Err:      err,
+			Attempts: attempts,
+			Time:     time.Now(),
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package twitter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorSwitchDemux_Handle(t *testing.T) {
+	messages, expectedCounts := exampleMessages()
+	counts := &counter{}
+	demux := newErrorCounterDemux(counts)
+	for _, message := range messages {
+		demux.Handle(message)
+	}
+	assert.Equal(t, expectedCounts, counts)
+}
+
+func TestErrorSwitchDemux_HandleChan(t *testing.T) {
+	messages, expectedCounts := exampleMessages()
+	counts := &counter{}
+	demux := newErrorCounterDemux(counts)
+	ch := make(chan interface{})
+	go func() {
+		for _, msg := range messages {
+			ch <- msg
+		}
+		close(ch)
+	}()
+	demux.HandleChan(ch)
+	assert.Equal(t, expectedCounts, counts)
+}
+
+func TestErrorSwitchDemux_Retries(t *testing.T) {
+	errDatabase := errors.New("database unavailable")
+	attempts := 0
+	var letters []*DeadLetter
+	demux := NewErrorSwitchDemux()
+	demux.Retries = 2
+	demux.DeadLetters = DeadLetterFunc(func(letter *DeadLetter) {
+		letters = append(letters, letter)
+	})
+
+	// succeeds on the last retry
+	demux.Tweet = func(tweet *Tweet) error {
+		attempts++
+		if attempts < 3 {
+			return errDatabase
+		}
+		return nil
+	}
+	demux.Handle(&Tweet{ID: 1})
+	assert.Equal(t, 3, attempts)
+	assert.Empty(t, letters)
+
+	// fails every attempt
+	attempts = 0
+	demux.Tweet = func(tweet *Tweet) error {
+		attempts++
+		return errDatabase
+	}
+	tweet := &Tweet{ID: 2}
+	demux.Handle(tweet)
+	assert.Equal(t, 3, attempts)
+	if assert.Len(t, letters, 1) {
+		assert.Equal(t, tweet, letters[0].Message)
+		assert.Equal(t, "Tweet", letters[0].Handler)
+		assert.Equal(t, errDatabase, letters[0].Err)
+		assert.Equal(t, 3, letters[0].Attempts)
+		assert.False(t, letters[0].Time.IsZero())
+	}
+}
+
+func TestDeadLetterWriter(t *testing.T) {
+	var buf bytes.Buffer
+	demux := NewErrorSwitchDemux()
+	demux.DeadLetters = NewDeadLetterWriter(&buf)
+	demux.Warning = func(warning *StallWarning) error {
+		return errors.New("full")
+	}
+	demux.Handle(&StallWarning{Code: "FALLING_BEHIND", PercentFull: 60})
+
+	record := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "Warning", record["handler"])
+	assert.Equal(t, "full", record["error"])
+	assert.Equal(t, float64(1), record["attempts"])
+	assert.Equal(t, map[string]interface{}{"code": "FALLING_BEHIND", "message": "", "percent_full": float64(60)}, record["message"])
+}
+
+func TestDeadLetterWriter_rawMessages(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewDeadLetterWriter(&buf)
+	raw := `{"id":10,"text":"hi","unknown_field":true}`
+	messages := []interface{}{
+		&Tweet{ID: 10, Raw: json.RawMessage(raw)},
+		&BackfilledTweet{Tweet: &Tweet{ID: 10, Raw: json.RawMessage(raw)}},
+		&UnknownMessage{Raw: json.RawMessage(`{"new_message":{}}`)},
+		errors.New("twitter: stream failed"),
+		// can't be encoded
+		make(chan int),
+	}
+	for _, message := range messages {
+		writer.DeadLetter(&DeadLetter{Message: message, Handler: "Other", Err: errors.New("failed")})
+	}
+	assert.NoError(t, writer.Err())
+
+	var records []deadLetterRecord
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var record deadLetterRecord
+		assert.NoError(t, decoder.Decode(&record))
+		records = append(records, record)
+	}
+	if assert.Len(t, records, 5) {
+		assert.JSONEq(t, raw, string(records[0].Message))
+		assert.JSONEq(t, raw, string(records[1].Message))
+		assert.JSONEq(t, `{"new_message":{}}`, string(records[2].Message))
+		assert.Equal(t, `"twitter: stream failed"`, string(records[3].Message))
+		assert.Equal(t, "null", string(records[4].Message))
+		assert.Equal(t, "json: unsupported type: chan int", records[4].MessageError)
+		assert.Equal(t, "failed", records[4].Error)
+	}
+}
+
+// errWriter is an io.Writer which always fails.
+type errWriter struct{}
+
+func (errWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestDeadLetterWriter_Err(t *testing.T) {
+	writer := NewDeadLetterWriter(errWriter{})
+	writer.DeadLetter(&DeadLetter{Message: &Tweet{ID: 1}, Handler: "Tweet"})
+	assert.EqualError(t, writer.Err(), "disk full")
+}
+
+// newErrorCounterDemux returns an ErrorSwitchDemux which counts message
+// types.
+func newErrorCounterDemux(counter *counter) ErrorSwitchDemux {
+	demux := NewErrorSwitchDemux()
+	demux.All = func(interface{}) error {
+		counter.all++
+		return nil
+	}
+	demux.Tweet = func(*Tweet) error {
+		counter.tweet++
+		return nil
+	}
+	demux.BackfilledTweet = func(*BackfilledTweet) error {
+		counter.backfilledTweet++
+		return nil
+	}
+	demux.DM = func(*DirectMessage) error {
+		counter.dm++
+		return nil
+	}
+	demux.StatusDeletion = func(*StatusDeletion) error {
+		counter.statusDeletion++
+		return nil
+	}
+	demux.LocationDeletion = func(*LocationDeletion) error {
+		counter.locationDeletion++
+		return nil
+	}
+	demux.StreamLimit = func(*StreamLimit) error {
+		counter.streamLimit++
+		return nil
+	}
+	demux.StatusWithheld = func(*StatusWithheld) error {
+		counter.statusWithheld++
+		return nil
+	}
+	demux.UserWithheld = func(*UserWithheld) error {
+		counter.userWithheld++
+		return nil
+	}
+	demux.StreamDisconnect = func(*StreamDisconnect) error {
+		counter.streamDisconnect++
+		return nil
+	}
+	demux.Warning = func(*StallWarning) error {
+		counter.stallWarning++
+		return nil
+	}
+	demux.FriendsList = func(*FriendsList) error {
+		counter.friendsList++
+		return nil
+	}
+	demux.Event = func(*Event) error {
+		counter.event++
+		return nil
+	}
+	demux.SiteMessage = func(*SiteStreamMessage) error {
+		counter.siteMessage++
+		return nil
+	}
+	demux.StreamControl = func(*StreamControl) error {
+		counter.streamControl++
+		return nil
+	}
+	demux.Other = func(interface{}) error {
+		counter.other++
+		return nil
+	}
+	return demux
+}
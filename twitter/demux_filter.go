@@ -0,0 +1,132 @@
+package twitter
+
+import (
+	"sync"
+)
+
+// MessageFilter is a named predicate over stream messages. Keep returns
+// false for messages which should be dropped.
+type MessageFilter struct {
+	Name string
+	Keep func(message interface{}) bool
+}
+
+// TweetFilter returns a MessageFilter which applies the predicate to Tweets
+// and BackfilledTweets. Other messages are always kept.
+func TweetFilter(name string, keep func(tweet *Tweet) bool) MessageFilter {
+	return MessageFilter{
+		Name: name,
+		Keep: func(message interface{}) bool {
+			switch msg := message.(type) {
+			case *Tweet:
+				return keep(msg)
+			case *BackfilledTweet:
+				if msg.Tweet != nil {
+					return keep(msg.Tweet)
+				}
+			}
+			return true
+		},
+	}
+}
+
+// DropRetweets returns a MessageFilter which drops retweets.
+func DropRetweets() MessageFilter {
+	return TweetFilter("retweets", func(tweet *Tweet) bool {
+		return tweet.RetweetedStatus == nil
+	})
+}
+
+// DropReplies returns a MessageFilter which drops replies.
+func DropReplies() MessageFilter {
+	return TweetFilter("replies", func(tweet *Tweet) bool {
+		return tweet.InReplyToStatusID == 0 && tweet.InReplyToUserID == 0
+	})
+}
+
+// DropSensitive returns a MessageFilter which drops Tweets marked
+// possibly_sensitive.
+func DropSensitive() MessageFilter {
+	return TweetFilter("sensitive", func(tweet *Tweet) bool {
+		return !tweet.PossiblySensitive
+	})
+}
+
+// DropUsers returns a MessageFilter which drops Tweets by the given users,
+// including their retweets.
+func DropUsers(userIDs ...int64) MessageFilter {
+	blocked := make(map[int64]bool, len(userIDs))
+	for _, id := range userIDs {
+		blocked[id] = true
+	}
+	return TweetFilter("users", func(tweet *Tweet) bool {
+		if tweet.User != nil && blocked[tweet.User.ID] {
+			return false
+		}
+		retweeted := tweet.RetweetedStatus
+		return retweeted == nil || retweeted.User == nil || !blocked[retweeted.User.ID]
+	})
+}
+
+// OnlyLanguages returns a MessageFilter which drops Tweets whose machine
+// detected language is not one of the given BCP 47 language codes.
+func OnlyLanguages(langs ...string) MessageFilter {
+	allowed := make(map[string]bool, len(langs))
+	for _, lang := range langs {
+		allowed[lang] = true
+	}
+	return TweetFilter("languages", func(tweet *Tweet) bool {
+		return allowed[tweet.Lang]
+	})
+}
+
+// FilterDemux is a Demux which passes messages kept by every filter to a
+// wrapped Demux. Filters are checked in order and counts of the messages
+// dropped by each filter are kept.
+type FilterDemux struct {
+	demux   Demux
+	filters []MessageFilter
+	mu      sync.Mutex
+	dropped map[string]int64
+}
+
+// NewFilterDemux returns a FilterDemux which passes messages kept by the
+// filters to the given Demux.
+func NewFilterDemux(demux Demux, filters ...MessageFilter) *FilterDemux {
+	return &FilterDemux{
+		demux:   demux,
+		filters: filters,
+		dropped: make(map[string]int64),
+	}
+}
+
+// Handle passes the message to the wrapped Demux unless a filter drops it.
+func (d *FilterDemux) Handle(message interface{}) {
+	for _, filter := range d.filters {
+		if !filter.Keep(message) {
+			d.mu.Lock()
+			d.dropped[filter.Name]++
+			d.mu.Unlock()
+			return
+		}
+	}
+	d.demux.Handle(message)
+}
+
+// HandleChan passes messages kept by the filters to the wrapped Demux.
+func (d *FilterDemux) HandleChan(messages <-chan interface{}) {
+	for message := range messages {
+		d.Handle(message)
+	}
+}
+
+// Dropped returns the number of messages dropped by each filter, by name.
+func (d *FilterDemux) Dropped() map[string]int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	dropped := make(map[string]int64, len(d.dropped))
+	for name, count := range d.dropped {
+		dropped[name] = count
+	}
+	return dropped
+}
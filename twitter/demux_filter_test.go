@@ -0,0 +1,75 @@
+package twitter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterDemux_Handle(t *testing.T) {
+	var kept []interface{}
+	demux := NewSwitchDemux()
+	demux.All = func(message interface{}) {
+		kept = append(kept, message)
+	}
+	filter := NewFilterDemux(demux,
+		DropRetweets(),
+		DropReplies(),
+		OnlyLanguages("en"),
+		DropUsers(66),
+		DropSensitive(),
+		TweetFilter("short", func(tweet *Tweet) bool {
+			return len(tweet.Text) >= 5
+		}),
+	)
+
+	original := &Tweet{ID: 1, Text: "hello gophers", Lang: "en", User: &User{ID: 2}}
+	messages := []interface{}{
+		original,
+		&Tweet{ID: 3, Text: "RT hello gophers", Lang: "en", RetweetedStatus: original},
+		&Tweet{ID: 4, Text: "@dghubble hi there", Lang: "en", InReplyToStatusID: 1, InReplyToUserID: 2},
+		&Tweet{ID: 5, Text: "hola gophers", Lang: "es"},
+		&Tweet{ID: 6, Text: "blocked user", Lang: "en", User: &User{ID: 66}},
+		&Tweet{ID: 7, Text: "sensitive", Lang: "en", PossiblySensitive: true},
+		&Tweet{ID: 8, Text: "hi", Lang: "en"},
+		&BackfilledTweet{&Tweet{ID: 9, Text: "hola de nuevo", Lang: "es"}},
+		&StallWarning{Code: "FALLING_BEHIND"},
+	}
+	for _, message := range messages {
+		filter.Handle(message)
+	}
+	assert.Equal(t, []interface{}{original, &StallWarning{Code: "FALLING_BEHIND"}}, kept)
+	expected := map[string]int64{
+		"retweets":  1,
+		"replies":   1,
+		"languages": 2,
+		"users":     1,
+		"sensitive": 1,
+		"short":     1,
+	}
+	assert.Equal(t, expected, filter.Dropped())
+}
+
+func TestFilterDemux_HandleChan(t *testing.T) {
+	messages, expectedCounts := exampleMessages()
+	counts := &counter{}
+	filter := NewFilterDemux(newCounterDemux(counts), DropRetweets())
+	ch := make(chan interface{})
+	go func() {
+		for _, msg := range messages {
+			ch <- msg
+		}
+		close(ch)
+	}()
+	filter.HandleChan(ch)
+	assert.Equal(t, expectedCounts, counts)
+	assert.Empty(t, filter.Dropped())
+}
+
+func TestDropUsers_Retweets(t *testing.T) {
+	filter := DropUsers(66)
+	retweet := &Tweet{ID: 2, User: &User{ID: 3}, RetweetedStatus: &Tweet{ID: 1, User: &User{ID: 66}}}
+	assert.False(t, filter.Keep(retweet))
+	assert.True(t, filter.Keep(&Tweet{ID: 3}))
+	assert.True(t, filter.Keep(&Event{}))
+}
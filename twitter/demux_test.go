@@ -37,6 +37,7 @@ func TestDemux_HandleChan(t *testing.T) {
 type counter struct {
 	all              int
 	tweet            int
+	backfilledTweet  int
 	dm               int
 	statusDeletion   int
 	locationDeletion int
@@ -47,9 +48,31 @@ type counter struct {
 	stallWarning     int
 	friendsList      int
 	event            int
+	siteMessage      int
+	streamControl    int
 	other            int
 }
 
+func TestDemux_Events(t *testing.T) {
+	var favorites, follows, all int
+	demux := NewSwitchDemux()
+	demux.Event = func(*Event) {
+		all++
+	}
+	demux.Events[EventFavorite] = func(*Event) {
+		favorites++
+	}
+	demux.Events[EventFollow] = func(*Event) {
+		follows++
+	}
+	for _, kind := range []EventKind{EventFavorite, EventFollow, EventFavorite, EventListCreated} {
+		demux.Handle(&Event{Event: kind})
+	}
+	assert.Equal(t, 4, all)
+	assert.Equal(t, 2, favorites)
+	assert.Equal(t, 1, follows)
+}
+
 // newCounterDemux returns a Demux which counts message types.
 func newCounterDemux(counter *counter) Demux {
 	demux := NewSwitchDemux()
@@ -59,6 +82,9 @@ func newCounterDemux(counter *counter) Demux {
 	demux.Tweet = func(*Tweet) {
 		counter.tweet++
 	}
+	demux.BackfilledTweet = func(*BackfilledTweet) {
+		counter.backfilledTweet++
+	}
 	demux.DM = func(*DirectMessage) {
 		counter.dm++
 	}
@@ -89,6 +115,12 @@ func newCounterDemux(counter *counter) Demux {
 	demux.Event = func(*Event) {
 		counter.event++
 	}
+	demux.SiteMessage = func(*SiteStreamMessage) {
+		counter.siteMessage++
+	}
+	demux.StreamControl = func(*StreamControl) {
+		counter.streamControl++
+	}
 	demux.Other = func(interface{}) {
 		counter.other++
 	}
@@ -100,6 +132,7 @@ func newCounterDemux(counter *counter) Demux {
 func exampleMessages() (messages []interface{}, expectedCounts *counter) {
 	var (
 		tweet            = &Tweet{}
+		backfilledTweet  = &BackfilledTweet{Tweet: tweet}
 		dm               = &DirectMessage{}
 		statusDeletion   = &StatusDeletion{}
 		locationDeletion = &LocationDeletion{}
@@ -110,15 +143,18 @@ func exampleMessages() (messages []interface{}, expectedCounts *counter) {
 		stallWarning     = &StallWarning{}
 		friendsList      = &FriendsList{}
 		event            = &Event{}
+		siteMessage      = &SiteStreamMessage{}
+		streamControl    = &StreamControl{}
 		otherA           = func() {}
 		otherB           = struct{}{}
 	)
-	messages = []interface{}{tweet, dm, statusDeletion, locationDeletion,
+	messages = []interface{}{tweet, backfilledTweet, dm, statusDeletion, locationDeletion,
 		streamLimit, statusWithheld, userWithheld, streamDisconnect,
-		stallWarning, friendsList, event, otherA, otherB}
+		stallWarning, friendsList, event, siteMessage, streamControl, otherA, otherB}
 	expectedCounts = &counter{
 		all:              len(messages),
 		tweet:            1,
+		backfilledTweet:  1,
 		dm:               1,
 		statusDeletion:   1,
 		locationDeletion: 1,
@@ -129,6 +165,8 @@ func exampleMessages() (messages []interface{}, expectedCounts *counter) {
 		stallWarning:     1,
 		friendsList:      1,
 		event:            1,
+		siteMessage:      1,
+		streamControl:    1,
 		other:            2,
 	}
 	return messages, expectedCounts
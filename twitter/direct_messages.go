@@ -1,6 +1,7 @@
 package twitter
 
 import (
+	"encoding/json"
 	"net/http"
 	"time"
 
@@ -15,12 +16,27 @@ type DirectMessageEvents struct {
 
 // DirectMessageEvent is a single Direct Message sent or received.
 type DirectMessageEvent struct {
-	CreatedAt string                     `json:"created_timestamp,omitempty"`
+	CreatedAt Time                       `json:"created_timestamp"`
 	ID        string                     `json:"id,omitempty"`
 	Type      string                     `json:"type"`
 	Message   *DirectMessageEventMessage `json:"message_create"`
 }
 
+// MarshalJSON encodes the Direct Message event, omitting an unset CreatedAt
+// and encoding CreatedAt as epoch milliseconds by default.
+func (e DirectMessageEvent) MarshalJSON() ([]byte, error) {
+	type event DirectMessageEvent
+	aux := struct {
+		event
+		CreatedAt *Time `json:"created_timestamp,omitempty"`
+	}{event: event(e)}
+	if e.CreatedAt.raw != "" || !e.CreatedAt.IsZero() {
+		createdAt := e.CreatedAt.withFormat(EpochMillisFormat)
+		aux.CreatedAt = &createdAt
+	}
+	return json.Marshal(aux)
+}
+
 // DirectMessageEventMessage contains message contents, along with sender and
 // target recipient.
 type DirectMessageEventMessage struct {
@@ -163,7 +179,7 @@ func (s *DirectMessageService) EventsDestroy(id string) (*http.Response, error)
 
 // DirectMessage is a direct message to a single recipient (DEPRECATED).
 type DirectMessage struct {
-	CreatedAt           string    `json:"created_at"`
+	CreatedAt           Time      `json:"created_at"`
 	Entities            *Entities `json:"entities"`
 	ID                  int64     `json:"id"`
 	IDStr               string    `json:"id_str"`
@@ -174,11 +190,15 @@ type DirectMessage struct {
 	SenderID            int64     `json:"sender_id"`
 	SenderScreenName    string    `json:"sender_screen_name"`
 	Text                string    `json:"text"`
+	// original JSON, if retained (see WithRawJSON)
+	Raw json.RawMessage `json:"-"`
 }
 
 // CreatedAtTime returns the time a Direct Message was created (DEPRECATED).
+//
+// Deprecated: Use CreatedAt.Time.
 func (d DirectMessage) CreatedAtTime() (time.Time, error) {
-	return time.Parse(time.RubyDate, d.CreatedAt)
+	return d.CreatedAt.parse()
 }
 
 // directMessageShowParams are the parameters for DirectMessageService.Show
@@ -10,7 +10,7 @@ import (
 
 var (
 	testDMEvent = DirectMessageEvent{
-		CreatedAt: "1542410751275",
+		CreatedAt: testTime("1542410751275"),
 		ID:        "1063573894173323269",
 		Type:      "message_create",
 		Message: &DirectMessageEventMessage{
@@ -0,0 +1,230 @@
+package geo
+
+import (
+	"math"
+	"strconv"
+)
+
+// MaxLocations is the maximum number of bounding boxes a Filter stream
+// accepts in StreamFilterParams.Locations.
+const MaxLocations = 25
+
+// cover is a bounding box and the parts of exterior rings inside it.
+type cover struct {
+	box   Box
+	rings []Ring
+}
+
+// waste returns the area of the box not covered by its rings.
+func (c cover) waste() float64 {
+	area := 0.0
+	for _, ring := range c.rings {
+		area += ring.area()
+	}
+	return c.box.Area() - area
+}
+
+// BoundingBoxes returns at most limit Boxes which together cover the Fence.
+// Starting from the bounding box of each Polygon, the box with the most
+// area outside the Fence is repeatedly split in half and shrunk to the parts
+// of the Fence inside each half, to reduce false positives from matching
+// the boxes. If limit is less than 1, MaxLocations is used.
+func (f *Fence) BoundingBoxes(limit int) []Box {
+	if limit < 1 {
+		limit = MaxLocations
+	}
+	var covers []cover
+	for _, polygon := range f.Polygons {
+		if len(polygon) == 0 {
+			continue
+		}
+		if box, ok := bounds(polygon[0]); ok {
+			covers = append(covers, cover{box: box, rings: []Ring{polygon[0]}})
+		}
+	}
+	for len(covers) > limit {
+		covers = mergeClosest(covers)
+	}
+	// bound the number of splits, since splits may only shrink a box
+	for splits := 0; len(covers) < limit && splits < 4*limit; splits++ {
+		i := mostWasteful(covers)
+		if i < 0 {
+			break
+		}
+		halves := split(covers[i])
+		if len(halves) == 0 {
+			break
+		}
+		covers = append(append(covers[:i:i], halves...), covers[i+1:]...)
+	}
+	boxes := make([]Box, len(covers))
+	for i, c := range covers {
+		boxes[i] = c.box
+	}
+	return boxes
+}
+
+// Locations returns the BoundingBoxes of the Fence formatted for
+// StreamFilterParams.Locations.
+func (f *Fence) Locations(limit int) []string {
+	return Locations(f.BoundingBoxes(limit))
+}
+
+// Locations formats Boxes as StreamFilterParams.Locations coordinates.
+func Locations(boxes []Box) []string {
+	var locations []string
+	for _, box := range boxes {
+		for _, value := range []float64{box.West, box.South, box.East, box.North} {
+			locations = append(locations, strconv.FormatFloat(value, 'f', -1, 64))
+		}
+	}
+	return locations
+}
+
+// mostWasteful returns the index of the cover with the most uncovered area,
+// or -1 if every cover is (nearly) filled.
+func mostWasteful(covers []cover) int {
+	index, most := -1, 0.0
+	for i, c := range covers {
+		waste := c.waste()
+		if waste > most && waste > 1e-9*c.box.Area() {
+			index, most = i, waste
+		}
+	}
+	return index
+}
+
+// split halves the cover's box along its longer side and returns covers
+// shrunk to the parts of its rings inside each half.
+func split(c cover) []cover {
+	left, right := c.box, c.box
+	if c.box.East-c.box.West >= c.box.North-c.box.South {
+		mid := (c.box.West + c.box.East) / 2
+		left.East, right.West = mid, mid
+	} else {
+		mid := (c.box.South + c.box.North) / 2
+		left.North, right.South = mid, mid
+	}
+	var halves []cover
+	for _, half := range []Box{left, right} {
+		var rings []Ring
+		var points []Point
+		for _, ring := range c.rings {
+			clipped := simplify(clip(ring, half))
+			if len(clipped) > 0 {
+				rings = append(rings, clipped)
+				points = append(points, clipped...)
+			}
+		}
+		if box, ok := bounds(points); ok {
+			halves = append(halves, cover{box: box, rings: rings})
+		}
+	}
+	return halves
+}
+
+// mergeClosest merges the pair of covers whose combined box grows the least.
+func mergeClosest(covers []cover) []cover {
+	bi, bj, best := 0, 1, math.Inf(1)
+	for i := range covers {
+		for j := i + 1; j < len(covers); j++ {
+			growth := union(covers[i].box, covers[j].box).Area() - covers[i].box.Area() - covers[j].box.Area()
+			if growth < best {
+				bi, bj, best = i, j, growth
+			}
+		}
+	}
+	merged := cover{
+		box:   union(covers[bi].box, covers[bj].box),
+		rings: append(append([]Ring{}, covers[bi].rings...), covers[bj].rings...),
+	}
+	result := append([]cover{merged}, covers[:bi]...)
+	result = append(result, covers[bi+1:bj]...)
+	return append(result, covers[bj+1:]...)
+}
+
+// union returns the Box bounding both Boxes.
+func union(a, b Box) Box {
+	return Box{
+		West:  math.Min(a.West, b.West),
+		South: math.Min(a.South, b.South),
+		East:  math.Max(a.East, b.East),
+		North: math.Max(a.North, b.North),
+	}
+}
+
+// clip returns the part of the Ring inside the Box using the
+// Sutherland-Hodgman algorithm.
+func clip(ring Ring, b Box) Ring {
+	edges := []struct {
+		inside    func(Point) bool
+		intersect func(p, q Point) Point
+	}{
+		{func(p Point) bool { return p[0] >= b.West }, func(p, q Point) Point { return atX(p, q, b.West) }},
+		{func(p Point) bool { return p[0] <= b.East }, func(p, q Point) Point { return atX(p, q, b.East) }},
+		{func(p Point) bool { return p[1] >= b.South }, func(p, q Point) Point { return atY(p, q, b.South) }},
+		{func(p Point) bool { return p[1] <= b.North }, func(p, q Point) Point { return atY(p, q, b.North) }},
+	}
+	output := ring
+	for _, edge := range edges {
+		input := output
+		output = nil
+		for i, current := range input {
+			previous := input[(i+len(input)-1)%len(input)]
+			if edge.inside(current) {
+				if !edge.inside(previous) {
+					output = append(output, edge.intersect(previous, current))
+				}
+				output = append(output, current)
+			} else if edge.inside(previous) {
+				output = append(output, edge.intersect(previous, current))
+			}
+		}
+	}
+	return output
+}
+
+// simplify removes collinear vertices from the Ring, including zero-width
+// spikes left along the edges of a clipping Box. Returns nil if fewer than 3
+// vertices remain.
+func simplify(ring Ring) Ring {
+	const epsilon = 1e-12
+	ring = append(Ring{}, ring...)
+	for removed := true; removed && len(ring) >= 3; {
+		removed = false
+		for i := 0; i < len(ring) && len(ring) >= 3; i++ {
+			prev, next := ring[(i+len(ring)-1)%len(ring)], ring[(i+1)%len(ring)]
+			if math.Abs(orientation(prev, ring[i], next)) <= epsilon {
+				ring = append(ring[:i], ring[i+1:]...)
+				removed = true
+				i--
+			}
+		}
+	}
+	if len(ring) < 3 {
+		return nil
+	}
+	return ring
+}
+
+// atX returns the point on segment pq with the given longitude.
+func atX(p, q Point, x float64) Point {
+	t := (x - p[0]) / (q[0] - p[0])
+	return Point{x, p[1] + t*(q[1]-p[1])}
+}
+
+// atY returns the point on segment pq with the given latitude.
+func atY(p, q Point, y float64) Point {
+	t := (y - p[1]) / (q[1] - p[1])
+	return Point{p[0] + t*(q[0]-p[0]), y}
+}
+
+// area returns the area enclosed by the Ring in square degrees.
+func (r Ring) area() float64 {
+	sum := 0.0
+	for i := range r {
+		j := (i + 1) % len(r)
+		sum += r[i][0]*r[j][1] - r[j][0]*r[i][1]
+	}
+	return math.Abs(sum) / 2
+}
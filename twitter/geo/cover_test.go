@@ -0,0 +1,61 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFence_BoundingBoxes(t *testing.T) {
+	fence := testFence()
+	// a single box bounds the whole L
+	assert.Equal(t, []Box{{0, 0, 2, 2}}, fence.BoundingBoxes(1))
+
+	// more boxes exclude the notch of the L
+	boxes := fence.BoundingBoxes(2)
+	assert.Len(t, boxes, 2)
+	total := 0.0
+	for _, box := range boxes {
+		total += box.Area()
+	}
+	assert.InDelta(t, 3.0, total, 1e-9)
+
+	// every point of the fence is covered
+	boxes = fence.BoundingBoxes(MaxLocations)
+	assert.True(t, len(boxes) <= MaxLocations)
+	for _, p := range []Point{{0.1, 0.1}, {1.9, 0.9}, {0.9, 1.9}, {1, 1}} {
+		covered := false
+		for _, box := range boxes {
+			covered = covered || box.Contains(p)
+		}
+		assert.True(t, covered, "point %v not covered", p)
+	}
+}
+
+func TestFence_BoundingBoxesTriangle(t *testing.T) {
+	fence := NewFence(Polygon{Ring{{0, 0}, {4, 0}, {0, 4}, {0, 0}}})
+	boxes := fence.BoundingBoxes(0)
+	assert.Len(t, boxes, MaxLocations)
+	total := 0.0
+	for _, box := range boxes {
+		total += box.Area()
+	}
+	// much closer to the triangle's area (8) than its bounding box (16)
+	assert.True(t, total < 10, "total area %v", total)
+}
+
+func TestFence_BoundingBoxesMerge(t *testing.T) {
+	fence := NewFence(
+		Polygon{square(0, 0, 1, 1)},
+		Polygon{square(10, 10, 11, 11)},
+		Polygon{square(1.5, 0, 2.5, 1)},
+	)
+	boxes := fence.BoundingBoxes(2)
+	assert.ElementsMatch(t, []Box{{0, 0, 2.5, 1}, {10, 10, 11, 11}}, boxes)
+}
+
+func TestLocations(t *testing.T) {
+	fence := NewFence(Polygon{square(-122.75, 36.8, -121.75, 37.8)})
+	assert.Equal(t, []string{"-122.75", "36.8", "-121.75", "37.8"}, fence.Locations(MaxLocations))
+	assert.Nil(t, Locations(nil))
+}
@@ -0,0 +1,315 @@
+/*
+Package geo provides geofencing of Tweets against polygons.
+
+The Filter stream's Locations parameter only accepts bounding boxes and
+matches Tweets whose Place bounding box overlaps them, so a query for a city
+returns Tweets from a rectangle around it. A Fence holds the polygons of the
+area of interest (e.g. parsed from GeoJSON), computes covering bounding boxes
+for StreamFilterParams.Locations, and checks Tweets against the polygons.
+
+	fence, err := geo.Parse(geojson)
+	params := &twitter.StreamFilterParams{
+		Locations: fence.Locations(25),
+	}
+	stream, err := client.Streams.Filter(params)
+	...
+	demux.Tweet = func(tweet *twitter.Tweet) {
+		if fence.Match(tweet, geo.PlaceOverlap) {
+			fmt.Println(tweet.Text)
+		}
+	}
+
+Coordinates are (longitude, latitude) pairs, as in GeoJSON and the Twitter
+API. Polygons crossing the antimeridian are not supported.
+*/
+package geo
+
+import (
+	"github.com/dghubble/go-twitter/twitter"
+)
+
+// Point is a longitude, latitude pair.
+type Point [2]float64
+
+// Ring is a closed line of Points. The last Point may repeat the first.
+type Ring []Point
+
+// Polygon is an exterior Ring followed by any interior Rings (holes).
+type Polygon []Ring
+
+// Box is a bounding box given by its west and east longitudes and south and
+// north latitudes.
+type Box struct {
+	West  float64
+	South float64
+	East  float64
+	North float64
+}
+
+// Fence is an area made of one or more Polygons.
+type Fence struct {
+	Polygons []Polygon
+}
+
+// NewFence returns a Fence for the given Polygons.
+func NewFence(polygons ...Polygon) *Fence {
+	return &Fence{Polygons: polygons}
+}
+
+// PlaceRule determines whether a Tweet without exact coordinates matches a
+// Fence based on its Place bounding box.
+type PlaceRule int
+
+const (
+	// PlaceIgnore never matches Tweets by their Place.
+	PlaceIgnore PlaceRule = iota
+	// PlaceOverlap matches Places whose bounding box overlaps the Fence,
+	// like the Filter stream does.
+	PlaceOverlap
+	// PlaceCentroid matches Places whose bounding box center is inside the
+	// Fence.
+	PlaceCentroid
+	// PlaceWithin matches Places whose bounding box is entirely inside the
+	// Fence.
+	PlaceWithin
+)
+
+// Match returns true if the Tweet's exact coordinates are inside the Fence.
+// Tweets without coordinates are matched by their Place bounding box
+// according to the PlaceRule.
+func (f *Fence) Match(tweet *twitter.Tweet, rule PlaceRule) bool {
+	if tweet == nil {
+		return false
+	}
+	if tweet.Coordinates != nil {
+		return f.Contains(Point(tweet.Coordinates.Coordinates))
+	}
+	if tweet.Place == nil || tweet.Place.BoundingBox == nil {
+		return false
+	}
+	box, ok := PlaceBox(tweet.Place.BoundingBox)
+	if !ok {
+		return false
+	}
+	switch rule {
+	case PlaceOverlap:
+		return f.Intersects(box)
+	case PlaceCentroid:
+		return f.Contains(box.Center())
+	case PlaceWithin:
+		return f.Covers(box)
+	}
+	return false
+}
+
+// PlaceBox returns the Box bounding a Place's BoundingBox. Returns false if
+// it has no coordinates.
+func PlaceBox(bbox *twitter.BoundingBox) (Box, bool) {
+	var points []Point
+	for _, ring := range bbox.Coordinates {
+		for _, point := range ring {
+			points = append(points, Point(point))
+		}
+	}
+	return bounds(points)
+}
+
+// Contains returns true if the Point is inside any Polygon of the Fence.
+func (f *Fence) Contains(p Point) bool {
+	for _, polygon := range f.Polygons {
+		if polygon.Contains(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Intersects returns true if the Box overlaps any Polygon of the Fence.
+func (f *Fence) Intersects(b Box) bool {
+	for _, polygon := range f.Polygons {
+		if polygon.Intersects(b) {
+			return true
+		}
+	}
+	return false
+}
+
+// Covers returns true if the Box is entirely inside a Polygon of the Fence.
+func (f *Fence) Covers(b Box) bool {
+	for _, polygon := range f.Polygons {
+		if polygon.Covers(b) {
+			return true
+		}
+	}
+	return false
+}
+
+// Bounds returns the Box bounding every Polygon of the Fence. Returns false
+// if the Fence has no Points.
+func (f *Fence) Bounds() (Box, bool) {
+	var points []Point
+	for _, polygon := range f.Polygons {
+		if len(polygon) > 0 {
+			points = append(points, polygon[0]...)
+		}
+	}
+	return bounds(points)
+}
+
+// Contains returns true if the Point is inside the exterior Ring and not
+// inside any holes.
+func (p Polygon) Contains(point Point) bool {
+	if len(p) == 0 || !p[0].Contains(point) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if hole.Contains(point) {
+			return false
+		}
+	}
+	return true
+}
+
+// Intersects returns true if the Box overlaps the Polygon.
+func (p Polygon) Intersects(b Box) bool {
+	if len(p) == 0 {
+		return false
+	}
+	outer, ok := bounds(p[0])
+	if !ok || !outer.Intersects(b) {
+		return false
+	}
+	// a vertex of the exterior is inside the box
+	for _, point := range p[0] {
+		if b.Contains(point) {
+			return true
+		}
+	}
+	// a corner of the box is inside the polygon
+	for _, corner := range b.corners() {
+		if p.Contains(corner) {
+			return true
+		}
+	}
+	// an edge of the polygon crosses an edge of the box
+	return p.crosses(b)
+}
+
+// Covers returns true if the Box is entirely inside the Polygon.
+func (p Polygon) Covers(b Box) bool {
+	for _, corner := range b.corners() {
+		if !p.Contains(corner) {
+			return false
+		}
+	}
+	// no part of the boundary, such as a hole, may lie inside the box
+	for _, ring := range p {
+		for _, point := range ring {
+			if b.strictlyContains(point) {
+				return false
+			}
+		}
+	}
+	return !p.crosses(b)
+}
+
+// crosses returns true if an edge of any Ring properly crosses an edge of
+// the Box.
+func (p Polygon) crosses(b Box) bool {
+	corners := b.corners()
+	for _, ring := range p {
+		for i := range ring {
+			a, c := ring[i], ring[(i+1)%len(ring)]
+			for j := range corners {
+				if segmentsCross(a, c, corners[j], corners[(j+1)%len(corners)]) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Contains returns true if the Point is inside the Ring, using the even-odd
+// rule.
+func (r Ring) Contains(point Point) bool {
+	inside := false
+	x, y := point[0], point[1]
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		xi, yi := r[i][0], r[i][1]
+		xj, yj := r[j][0], r[j][1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Contains returns true if the Point is inside the Box, inclusive.
+func (b Box) Contains(p Point) bool {
+	return p[0] >= b.West && p[0] <= b.East && p[1] >= b.South && p[1] <= b.North
+}
+
+// Intersects returns true if the Boxes overlap.
+func (b Box) Intersects(o Box) bool {
+	return b.West <= o.East && o.West <= b.East && b.South <= o.North && o.South <= b.North
+}
+
+// Center returns the center Point of the Box.
+func (b Box) Center() Point {
+	return Point{(b.West + b.East) / 2, (b.South + b.North) / 2}
+}
+
+// Area returns the area of the Box in square degrees.
+func (b Box) Area() float64 {
+	return (b.East - b.West) * (b.North - b.South)
+}
+
+// strictlyContains returns true if the Point is inside the Box, exclusive.
+func (b Box) strictlyContains(p Point) bool {
+	return p[0] > b.West && p[0] < b.East && p[1] > b.South && p[1] < b.North
+}
+
+// corners returns the corners of the Box in counter-clockwise order.
+func (b Box) corners() []Point {
+	return []Point{{b.West, b.South}, {b.East, b.South}, {b.East, b.North}, {b.West, b.North}}
+}
+
+// bounds returns the Box bounding the Points. Returns false if there are no
+// Points.
+func bounds(points []Point) (Box, bool) {
+	if len(points) == 0 {
+		return Box{}, false
+	}
+	box := Box{West: points[0][0], South: points[0][1], East: points[0][0], North: points[0][1]}
+	for _, p := range points[1:] {
+		if p[0] < box.West {
+			box.West = p[0]
+		}
+		if p[0] > box.East {
+			box.East = p[0]
+		}
+		if p[1] < box.South {
+			box.South = p[1]
+		}
+		if p[1] > box.North {
+			box.North = p[1]
+		}
+	}
+	return box, true
+}
+
+// segmentsCross returns true if segment ab properly crosses segment cd.
+func segmentsCross(a, b, c, d Point) bool {
+	d1 := orientation(c, d, a)
+	d2 := orientation(c, d, b)
+	d3 := orientation(a, b, c)
+	d4 := orientation(a, b, d)
+	return ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0))
+}
+
+// orientation returns the cross product of (b - a) and (c - a), which is
+// positive if a, b, c turn counter-clockwise.
+func orientation(a, b, c Point) float64 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+}
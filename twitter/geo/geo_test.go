@@ -0,0 +1,100 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/dghubble/go-twitter/twitter"
+	"github.com/stretchr/testify/assert"
+)
+
+// square returns a closed Ring for the square with the given corners.
+func square(west, south, east, north float64) Ring {
+	return Ring{{west, south}, {east, south}, {east, north}, {west, north}, {west, south}}
+}
+
+// testFence is an L shape made of a 2x1 and 1x2 rectangle, with a small
+// hole in the corner square.
+func testFence() *Fence {
+	outer := Ring{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}, {0, 0}}
+	hole := square(0.25, 0.25, 0.75, 0.75)
+	return NewFence(Polygon{outer, hole})
+}
+
+func TestFence_Contains(t *testing.T) {
+	fence := testFence()
+	assert.True(t, fence.Contains(Point{1.5, 0.5}))
+	assert.True(t, fence.Contains(Point{0.5, 1.5}))
+	// outside of the L, but inside its bounding box
+	assert.False(t, fence.Contains(Point{1.5, 1.5}))
+	// inside the hole
+	assert.False(t, fence.Contains(Point{0.5, 0.5}))
+	assert.False(t, fence.Contains(Point{3, 3}))
+}
+
+func TestFence_Intersects(t *testing.T) {
+	fence := testFence()
+	// overlapping the L
+	assert.True(t, fence.Intersects(Box{1.5, 0.5, 3, 3}))
+	// containing the whole L
+	assert.True(t, fence.Intersects(Box{-1, -1, 3, 3}))
+	// inside the L
+	assert.True(t, fence.Intersects(Box{1.2, 0.2, 1.8, 0.8}))
+	// crossing the L without containing vertices or corners
+	assert.True(t, fence.Intersects(Box{0.8, -1, 0.9, 3}))
+	// in the notch of the L
+	assert.False(t, fence.Intersects(Box{1.2, 1.2, 1.8, 1.8}))
+	// inside the hole
+	assert.False(t, fence.Intersects(Box{0.4, 0.4, 0.6, 0.6}))
+	assert.False(t, fence.Intersects(Box{5, 5, 6, 6}))
+}
+
+func TestFence_Covers(t *testing.T) {
+	fence := testFence()
+	assert.True(t, fence.Covers(Box{1.2, 0.2, 1.8, 0.8}))
+	assert.False(t, fence.Covers(Box{1.5, 0.5, 3, 3}))
+	// surrounding the hole
+	assert.False(t, fence.Covers(Box{0.1, 0.1, 0.9, 0.9}))
+	// spanning the notch
+	assert.False(t, fence.Covers(Box{0.5, 0.8, 1.5, 1.5}))
+}
+
+func TestFence_Bounds(t *testing.T) {
+	box, ok := testFence().Bounds()
+	assert.True(t, ok)
+	assert.Equal(t, Box{0, 0, 2, 2}, box)
+	_, ok = NewFence().Bounds()
+	assert.False(t, ok)
+}
+
+func TestFence_Match(t *testing.T) {
+	fence := testFence()
+	place := func(box Box) *twitter.Place {
+		return &twitter.Place{BoundingBox: &twitter.BoundingBox{
+			Type:        "Polygon",
+			Coordinates: [][][2]float64{{{box.West, box.South}, {box.East, box.South}, {box.East, box.North}, {box.West, box.North}}},
+		}}
+	}
+	point := func(lon, lat float64) *twitter.Coordinates {
+		return &twitter.Coordinates{Type: "Point", Coordinates: [2]float64{lon, lat}}
+	}
+	assert.True(t, fence.Match(&twitter.Tweet{Coordinates: point(1.5, 0.5)}, PlaceIgnore))
+	assert.False(t, fence.Match(&twitter.Tweet{Coordinates: point(1.5, 1.5)}, PlaceOverlap))
+	// coordinates take precedence over the place
+	assert.False(t, fence.Match(&twitter.Tweet{Coordinates: point(1.5, 1.5), Place: place(Box{1.2, 0.2, 1.8, 0.8})}, PlaceOverlap))
+
+	overlapping := &twitter.Tweet{Place: place(Box{0.9, 0.9, 3, 3})}
+	assert.False(t, fence.Match(overlapping, PlaceIgnore))
+	assert.True(t, fence.Match(overlapping, PlaceOverlap))
+	assert.False(t, fence.Match(overlapping, PlaceCentroid))
+	assert.False(t, fence.Match(overlapping, PlaceWithin))
+
+	centered := &twitter.Tweet{Place: place(Box{1.2, 0.2, 2.5, 0.8})}
+	assert.True(t, fence.Match(centered, PlaceCentroid))
+	assert.False(t, fence.Match(centered, PlaceWithin))
+
+	within := &twitter.Tweet{Place: place(Box{1.2, 0.2, 1.8, 0.8})}
+	assert.True(t, fence.Match(within, PlaceWithin))
+
+	assert.False(t, fence.Match(&twitter.Tweet{}, PlaceOverlap))
+	assert.False(t, fence.Match(nil, PlaceOverlap))
+}
@@ -0,0 +1,109 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var errPosition = errors.New("geo: GeoJSON positions must have a longitude and latitude")
+
+// geoJSON is a GeoJSON object of any type.
+// https://datatracker.ietf.org/doc/html/rfc7946
+type geoJSON struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+	Geometry    *geoJSON        `json:"geometry"`
+	Geometries  []*geoJSON      `json:"geometries"`
+	Features    []*geoJSON      `json:"features"`
+}
+
+// Parse returns a Fence of the polygons in a GeoJSON Polygon, MultiPolygon,
+// GeometryCollection, Feature, or FeatureCollection. Other geometry types
+// are ignored.
+func Parse(data []byte) (*Fence, error) {
+	object := new(geoJSON)
+	if err := json.Unmarshal(data, object); err != nil {
+		return nil, err
+	}
+	polygons, err := object.polygons()
+	if err != nil {
+		return nil, err
+	}
+	return NewFence(polygons...), nil
+}
+
+// polygons returns the Polygons of the GeoJSON object.
+func (g *geoJSON) polygons() ([]Polygon, error) {
+	switch g.Type {
+	case "Polygon":
+		var coordinates [][][]float64
+		if err := json.Unmarshal(g.Coordinates, &coordinates); err != nil {
+			return nil, err
+		}
+		polygon, err := newPolygon(coordinates)
+		if err != nil {
+			return nil, err
+		}
+		return []Polygon{polygon}, nil
+	case "MultiPolygon":
+		var coordinates [][][][]float64
+		if err := json.Unmarshal(g.Coordinates, &coordinates); err != nil {
+			return nil, err
+		}
+		var polygons []Polygon
+		for _, polygonCoordinates := range coordinates {
+			polygon, err := newPolygon(polygonCoordinates)
+			if err != nil {
+				return nil, err
+			}
+			polygons = append(polygons, polygon)
+		}
+		return polygons, nil
+	case "Feature":
+		if g.Geometry == nil {
+			return nil, nil
+		}
+		return g.Geometry.polygons()
+	case "FeatureCollection":
+		return collect(g.Features)
+	case "GeometryCollection":
+		return collect(g.Geometries)
+	case "Point", "MultiPoint", "LineString", "MultiLineString":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("geo: unsupported GeoJSON type %q", g.Type)
+}
+
+// collect returns the Polygons of each GeoJSON object.
+func collect(objects []*geoJSON) ([]Polygon, error) {
+	var polygons []Polygon
+	for _, object := range objects {
+		if object == nil {
+			continue
+		}
+		more, err := object.polygons()
+		if err != nil {
+			return nil, err
+		}
+		polygons = append(polygons, more...)
+	}
+	return polygons, nil
+}
+
+// newPolygon returns a Polygon from GeoJSON Polygon coordinates, ignoring
+// any altitudes.
+func newPolygon(coordinates [][][]float64) (Polygon, error) {
+	polygon := make(Polygon, 0, len(coordinates))
+	for _, ringCoordinates := range coordinates {
+		ring := make(Ring, 0, len(ringCoordinates))
+		for _, position := range ringCoordinates {
+			if len(position) < 2 {
+				return nil, errPosition
+			}
+			ring = append(ring, Point{position[0], position[1]})
+		}
+		polygon = append(polygon, ring)
+	}
+	return polygon, nil
+}
@@ -0,0 +1,46 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_Polygon(t *testing.T) {
+	data := []byte(`{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 1], [0, 0]], [[0.2, 0.2, 10], [0.4, 0.2, 10], [0.4, 0.4, 10], [0.2, 0.2, 10]]]}`)
+	fence, err := Parse(data)
+	assert.NoError(t, err)
+	expected := NewFence(Polygon{
+		Ring{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}},
+		Ring{{0.2, 0.2}, {0.4, 0.2}, {0.4, 0.4}, {0.2, 0.2}},
+	})
+	assert.Equal(t, expected, fence)
+}
+
+func TestParse_FeatureCollection(t *testing.T) {
+	data := []byte(`{
+		"type": "FeatureCollection",
+		"features": [
+			{"type": "Feature", "properties": {"name": "a"}, "geometry": {"type": "MultiPolygon", "coordinates": [[[[0, 0], [1, 0], [1, 1], [0, 0]]], [[[5, 5], [6, 5], [6, 6], [5, 5]]]]}},
+			{"type": "Feature", "geometry": {"type": "Point", "coordinates": [3, 3]}},
+			{"type": "Feature", "geometry": null},
+			{"type": "Feature", "geometry": {"type": "GeometryCollection", "geometries": [{"type": "Polygon", "coordinates": [[[8, 8], [9, 8], [9, 9], [8, 8]]]}]}}
+		]
+	}`)
+	fence, err := Parse(data)
+	assert.NoError(t, err)
+	assert.Len(t, fence.Polygons, 3)
+	assert.True(t, fence.Contains(Point{5.9, 5.1}))
+	assert.True(t, fence.Contains(Point{8.9, 8.1}))
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse([]byte(`{`))
+	assert.Error(t, err)
+	_, err = Parse([]byte(`{"type": "Circle"}`))
+	assert.EqualError(t, err, `geo: unsupported GeoJSON type "Circle"`)
+	_, err = Parse([]byte(`{"type": "Polygon", "coordinates": [[[0], [1, 0], [1, 1]]]}`))
+	assert.Equal(t, errPosition, err)
+	_, err = Parse([]byte(`{"type": "Polygon", "coordinates": "nope"}`))
+	assert.Error(t, err)
+}
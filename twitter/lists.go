@@ -10,7 +10,7 @@ import (
 type List struct {
 	Slug            string `json:"slug"`
 	Name            string `json:"name"`
-	CreatedAt       string `json:"created_at"`
+	CreatedAt       Time   `json:"created_at"`
 	URI             string `json:"uri"`
 	SubscriberCount int    `json:"subscriber_count"`
 	IDStr           string `json:"id_str"`
@@ -0,0 +1,195 @@
+package twitter
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
+// UnknownMessage is a stream message whose type could not be determined.
+// Raw holds the original message JSON.
+type UnknownMessage struct {
+	Raw json.RawMessage
+}
+
+// MarshalJSON returns the original message JSON.
+func (m UnknownMessage) MarshalJSON() ([]byte, error) {
+	if len(m.Raw) == 0 {
+		return []byte("null"), nil
+	}
+	return m.Raw, nil
+}
+
+// MarshalJSON returns the original JSON of the Tweet if it was retained (see
+// WithRawJSON), otherwise the JSON encoding of its fields.
+func (t Tweet) MarshalJSON() ([]byte, error) {
+	if len(t.Raw) > 0 {
+		return t.Raw, nil
+	}
+	type tweet Tweet
+	return json.Marshal(tweet(t))
+}
+
+// MarshalJSON returns the original JSON of the User if it was retained (see
+// WithRawJSON), otherwise the JSON encoding of its fields.
+func (u User) MarshalJSON() ([]byte, error) {
+	if len(u.Raw) > 0 {
+		return u.Raw, nil
+	}
+	type user User
+	return json.Marshal(user(u))
+}
+
+// MarshalJSON returns the original JSON of the Direct Message if it was
+// retained (see WithRawJSON), otherwise the JSON encoding of its fields.
+func (d DirectMessage) MarshalJSON() ([]byte, error) {
+	if len(d.Raw) > 0 {
+		return d.Raw, nil
+	}
+	type directMessage DirectMessage
+	return json.Marshal(directMessage(d))
+}
+
+// rawJSONDecoder is a sling.ResponseDecoder which decodes JSON responses and
+// retains the original JSON in Raw fields.
+type rawJSONDecoder struct{}
+
+// Decode decodes the Response Body into the value pointed to by v.
+func (d rawJSONDecoder) Decode(resp *http.Response, v interface{}) error {
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	attachRawJSON(v, data)
+	return nil
+}
+
+// attachMessageRawJSON retains the original JSON of a stream message
+// decoded from the token, unwrapping Direct Message and Site Stream
+// envelopes. The token must not be modified afterward.
+func attachMessageRawJSON(message interface{}, token []byte) {
+	switch msg := message.(type) {
+	case *DirectMessage:
+		notice := new(struct {
+			DirectMessage json.RawMessage `json:"direct_message"`
+		})
+		if json.Unmarshal(token, notice) == nil {
+			attachRawJSON(msg, notice.DirectMessage)
+		}
+	case *SiteStreamMessage:
+		envelope := new(siteStreamEnvelope)
+		if json.Unmarshal(token, envelope) == nil {
+			attachMessageRawJSON(msg.Message, envelope.Message)
+		}
+	default:
+		attachRawJSON(message, token)
+	}
+}
+
+// attachRawJSON sets the Raw field of each struct within the decoded value to
+// the part of the JSON data it was decoded from. The data must not be
+// modified afterward.
+func attachRawJSON(v interface{}, data []byte) {
+	attachRaw(reflect.ValueOf(v), data)
+}
+
+func attachRaw(v reflect.Value, data []byte) {
+	if !v.IsValid() || len(data) == 0 {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			attachRaw(v.Elem(), data)
+		}
+	case reflect.Slice:
+		if v.Type() == rawMessageType || !hasRawField(v.Type().Elem()) {
+			return
+		}
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return
+		}
+		for i := 0; i < v.Len() && i < len(items); i++ {
+			attachRaw(v.Index(i), items[i])
+		}
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			value := v.Field(i)
+			if field.PkgPath != "" && !field.Anonymous {
+				continue
+			}
+			if field.Name == "Raw" && field.Type == rawMessageType {
+				if value.CanSet() {
+					value.SetBytes(data)
+				}
+				continue
+			}
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if field.Anonymous && name == "" {
+				// embedded struct fields are decoded from the same object
+				attachRaw(value, data)
+				continue
+			}
+			if name == "-" || !hasRawField(field.Type) {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			if fields == nil && json.Unmarshal(data, &fields) != nil {
+				return
+			}
+			attachRaw(value, fields[name])
+		}
+	}
+}
+
+// cache of whether types may contain a Raw field
+var rawTypes sync.Map
+
+// hasRawField returns true if values of the type may contain a struct with a
+// Raw field.
+func hasRawField(t reflect.Type) bool {
+	if has, ok := rawTypes.Load(t); ok {
+		return has.(bool)
+	}
+	has := typeHasRawField(t, make(map[reflect.Type]bool))
+	rawTypes.Store(t, has)
+	return has
+}
+
+func typeHasRawField(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return typeHasRawField(t.Elem(), visiting)
+	case reflect.Interface:
+		return true
+	case reflect.Struct:
+		if visiting[t] {
+			return false
+		}
+		visiting[t] = true
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.Name == "Raw" && field.Type == rawMessageType {
+				return true
+			}
+			if (field.PkgPath == "" || field.Anonymous) && typeHasRawField(field.Type, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package twitter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const rawTweetJSON = `{"id": 2, "text": "RT gophers", "new_field": {"a": 1}, "user": {"id": 3, "screen_name": "dghubble", "new_user_field": true}, "retweeted_status": {"id": 1, "text": "gophers", "user": {"id": 4, "screen_name": "golang"}}}`
+
+func TestWithRawJSON(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+
+	mux.HandleFunc("/1.1/statuses/show.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, rawTweetJSON)
+	})
+	mux.HandleFunc("/1.1/search/tweets.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"statuses": [%s, {"id": 5, "extra": "x"}]}`, rawTweetJSON)
+	})
+
+	client := NewClient(httpClient, WithRawJSON())
+	tweet, _, err := client.Statuses.Show(2, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), tweet.ID)
+	assert.JSONEq(t, rawTweetJSON, string(tweet.Raw))
+	assert.JSONEq(t, `{"id": 3, "screen_name": "dghubble", "new_user_field": true}`, string(tweet.User.Raw))
+	assert.JSONEq(t, `{"id": 1, "text": "gophers", "user": {"id": 4, "screen_name": "golang"}}`, string(tweet.RetweetedStatus.Raw))
+	assert.JSONEq(t, `{"id": 4, "screen_name": "golang"}`, string(tweet.RetweetedStatus.User.Raw))
+
+	// re-marshals verbatim
+	data, err := json.Marshal(tweet)
+	assert.Nil(t, err)
+	assert.JSONEq(t, rawTweetJSON, string(data))
+
+	search, _, err := client.Search.Tweets(&SearchTweetParams{Query: "gophers"})
+	assert.Nil(t, err)
+	if assert.Len(t, search.Statuses, 2) {
+		assert.JSONEq(t, rawTweetJSON, string(search.Statuses[0].Raw))
+		assert.JSONEq(t, `{"id": 5, "extra": "x"}`, string(search.Statuses[1].Raw))
+	}
+}
+
+func TestWithoutRawJSON(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+
+	mux.HandleFunc("/1.1/statuses/show.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, rawTweetJSON)
+	})
+
+	client := NewClient(httpClient)
+	tweet, _, err := client.Statuses.Show(2, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, tweet.Raw)
+	assert.Nil(t, tweet.User.Raw)
+
+	// marshals known fields
+	data, err := json.Marshal(tweet)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"screen_name":"dghubble"`)
+	assert.NotContains(t, string(data), "new_field")
+}
+
+func TestStream_RawJSON(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+
+	mux.HandleFunc("/1.1/statuses/sample.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Transfer-Encoding", "chunked")
+		fmt.Fprint(w,
+			`{"id": 1, "text": "a", "retweet_count": 0, "new_field": 1}`+"\r\n"+
+				`{"id": 2, "text": "b", "retweet_count": 0, "new_field": 2}`+"\r\n"+
+				`{"direct_message": {"id": 3, "text": "hi", "sender": {"id": 4, "new": 1}}}`+"\r\n",
+		)
+	})
+
+	client := NewClient(httpClient, WithRawJSON())
+	stream, err := client.Streams.Sample(nil)
+	assert.Nil(t, err)
+	var messages []interface{}
+	for message := range stream.Messages {
+		messages = append(messages, message)
+		if len(messages) == 3 {
+			break
+		}
+	}
+	stream.Stop()
+	if assert.Len(t, messages, 3) {
+		assert.Equal(t, `{"id": 1, "text": "a", "retweet_count": 0, "new_field": 1}`, string(messages[0].(*Tweet).Raw))
+		assert.Equal(t, `{"id": 2, "text": "b", "retweet_count": 0, "new_field": 2}`, string(messages[1].(*Tweet).Raw))
+		dm := messages[2].(*DirectMessage)
+		assert.JSONEq(t, `{"id": 3, "text": "hi", "sender": {"id": 4, "new": 1}}`, string(dm.Raw))
+		assert.JSONEq(t, `{"id": 4, "new": 1}`, string(dm.Sender.Raw))
+	}
+}
+
+func TestAttachRawJSON_Nested(t *testing.T) {
+	msgJSON := []byte(`{"for_user": 1, "message": {"event": "favorite", "source": {"id": 2}, "target_object": {"id": 3, "x": 1}}}`)
+	msg := getMessage(msgJSON)
+	attachMessageRawJSON(msg, msgJSON)
+	event := msg.(*SiteStreamMessage).Message.(*Event)
+	assert.JSONEq(t, `{"id": 2}`, string(event.Source.Raw))
+	assert.JSONEq(t, `{"id": 3, "x": 1}`, string(event.TargetTweet().Raw))
+
+	backfilled := &BackfilledTweet{&Tweet{}}
+	json.Unmarshal([]byte(`{"id": 1, "new": true}`), backfilled)
+	attachRawJSON(backfilled, []byte(`{"id": 1, "new": true}`))
+	assert.Equal(t, `{"id": 1, "new": true}`, string(backfilled.Raw))
+}
+
+func TestAttachMessageRawJSON_SiteDirectMessage(t *testing.T) {
+	msgJSON := []byte(`{"for_user": 1, "message": {"direct_message": {"id": 2, "sender": {"id": 3}}}}`)
+	msg := getMessage(msgJSON)
+	attachMessageRawJSON(msg, msgJSON)
+	dm := msg.(*SiteStreamMessage).Message.(*DirectMessage)
+	assert.JSONEq(t, `{"id": 2, "sender": {"id": 3}}`, string(dm.Raw))
+	assert.JSONEq(t, `{"id": 3}`, string(dm.Sender.Raw))
+}
+
+func TestUnknownMessage_MarshalJSON(t *testing.T) {
+	msgJSON := []byte(`{"unknown_data": {"new_twitter_type": "unexpected"}}`)
+	data, err := json.Marshal(getMessage(msgJSON))
+	assert.Nil(t, err)
+	assert.JSONEq(t, string(msgJSON), string(data))
+}
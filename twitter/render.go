@@ -0,0 +1,252 @@
+package twitter
+
+import (
+	"html"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// TextRunKind is the kind of a TextRun.
+type TextRunKind int
+
+// Kinds of TextRuns.
+const (
+	TextPlain TextRunKind = iota
+	TextHashtag
+	TextMention
+	TextSymbol
+	TextURL
+	TextMedia
+)
+
+// TextRun is a run of a Tweet's display text, either plain text or an
+// entity.
+type TextRun struct {
+	Kind TextRunKind
+	// unescaped text as displayed, e.g. "#golang", "@gopher", "$TWTR", or a
+	// URL's display URL
+	Text string
+	// hashtag or symbol text, mentioned screen name, or expanded URL
+	Value string
+}
+
+// Runs splits the displayed text into plain text and entity runs, in order.
+// Entity Indices are in code points, not bytes. Plain text is unescaped
+// and t.co URLs are expanded, while media links (which display as
+// attachments) are omitted.
+func (v *TweetView) Runs() []TextRun {
+	type span struct {
+		start, end int
+		run        TextRun
+	}
+	var spans []span
+	if entities := v.Entities; entities != nil {
+		for _, hashtag := range entities.Hashtags {
+			spans = append(spans, span{hashtag.Indices.Start(), hashtag.Indices.End(), TextRun{TextHashtag, "#" + hashtag.Text, hashtag.Text}})
+		}
+		for _, mention := range entities.UserMentions {
+			spans = append(spans, span{mention.Indices.Start(), mention.Indices.End(), TextRun{TextMention, "@" + mention.ScreenName, mention.ScreenName}})
+		}
+		for _, symbol := range entities.Symbols {
+			spans = append(spans, span{symbol.Indices.Start(), symbol.Indices.End(), TextRun{TextSymbol, "$" + symbol.Text, symbol.Text}})
+		}
+		for _, u := range entities.Urls {
+			spans = append(spans, span{u.Indices.Start(), u.Indices.End(), TextRun{TextURL, displayURL(u), expandedURL(u)}})
+		}
+	}
+	for _, media := range v.Media() {
+		spans = append(spans, span{media.Indices.Start(), media.Indices.End(), TextRun{TextMedia, displayURL(media.URLEntity), expandedURL(media.URLEntity)}})
+	}
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].start < spans[j].start
+	})
+
+	// indices usually refer to the unescaped text, but use the text as
+	// received if the entities don't line up with it
+	runes, escaped := []rune(html.UnescapeString(v.FullText)), false
+	for _, s := range spans {
+		if !entityAt(runes, s.start, s.end, s.run) {
+			runes, escaped = []rune(v.FullText), true
+			break
+		}
+	}
+	start, end := v.DisplayTextRange.Start(), v.DisplayTextRange.End()
+	if end > len(runes) {
+		end = len(runes)
+	}
+	if start < 0 || start > end {
+		return nil
+	}
+	var runs []TextRun
+	plain := func(from, to int) {
+		if from < to {
+			text := string(runes[from:to])
+			if escaped {
+				text = html.UnescapeString(text)
+			}
+			runs = append(runs, TextRun{Kind: TextPlain, Text: text, Value: text})
+		}
+	}
+	pos := start
+	for _, s := range spans {
+		// skip entities outside the display range or overlapping others
+		if s.start < pos || s.end > end || s.start >= s.end {
+			continue
+		}
+		plain(pos, s.start)
+		if s.run.Kind != TextMedia {
+			runs = append(runs, s.run)
+		}
+		pos = s.end
+	}
+	plain(pos, end)
+	// trim trailing whitespace, such as before omitted media links
+	if n := len(runs); n > 0 && runs[n-1].Kind == TextPlain {
+		runs[n-1].Text = strings.TrimRight(runs[n-1].Text, " \t\n")
+		runs[n-1].Value = runs[n-1].Text
+		if runs[n-1].Text == "" {
+			runs = runs[:n-1]
+		}
+	}
+	return runs
+}
+
+// entityAt returns true if the hashtag, mention, or symbol run's value is at
+// the indices of the text. Other runs can't be checked and return true.
+func entityAt(runes []rune, start, end int, run TextRun) bool {
+	switch run.Kind {
+	case TextHashtag, TextMention, TextSymbol:
+		if start < 0 || start >= end || end > len(runes) {
+			return false
+		}
+		return strings.EqualFold(string(runes[start+1:end]), run.Value)
+	}
+	return true
+}
+
+// displayURL returns the URL's display URL, falling back to its expanded or
+// t.co URL.
+func displayURL(u URLEntity) string {
+	if u.DisplayURL != "" {
+		return u.DisplayURL
+	}
+	return expandedURL(u)
+}
+
+// expandedURL returns the URL's expanded URL, falling back to its t.co URL.
+func expandedURL(u URLEntity) string {
+	if u.ExpandedURL != "" {
+		return u.ExpandedURL
+	}
+	return u.URL
+}
+
+// RenderFormat is an output format of a Renderer.
+type RenderFormat int
+
+// Render formats.
+const (
+	// RenderText renders plain text with expanded URLs.
+	RenderText RenderFormat = iota
+	// RenderHTML renders escaped HTML with links.
+	RenderHTML
+	// RenderMarkdown renders escaped Markdown with links.
+	RenderMarkdown
+)
+
+// Default link templates of a Renderer.
+const (
+	DefaultHashtagURL = "https://twitter.com/hashtag/{value}"
+	DefaultMentionURL = "https://twitter.com/{value}"
+	DefaultSymbolURL  = "https://twitter.com/search?q=%24{value}"
+)
+
+// Renderer renders the display text of Tweets as plain text, HTML, or
+// Markdown. Link templates replace "{value}" with the escaped hashtag,
+// screen name, or symbol. Empty templates use the defaults, which link to
+// twitter.com.
+type Renderer struct {
+	Format     RenderFormat
+	HashtagURL string
+	MentionURL string
+	SymbolURL  string
+}
+
+// Render returns the Tweet's display text in the Renderer's format, or ""
+// for a nil Tweet. For retweets, the original Tweet's text is rendered.
+func (r *Renderer) Render(tweet *Tweet) string {
+	if tweet == nil {
+		return ""
+	}
+	return r.RenderRuns(tweet.View().Runs())
+}
+
+// RenderRuns returns the runs in the Renderer's format.
+func (r *Renderer) RenderRuns(runs []TextRun) string {
+	var b strings.Builder
+	for _, run := range runs {
+		if run.Kind == TextMedia {
+			continue
+		}
+		if run.Kind == TextPlain || r.Format == RenderText {
+			text := run.Text
+			if run.Kind == TextURL {
+				text = run.Value
+			}
+			b.WriteString(r.escape(text))
+			continue
+		}
+		b.WriteString(r.link(run.Text, r.href(run)))
+	}
+	return b.String()
+}
+
+// href returns the URL an entity run links to.
+func (r *Renderer) href(run TextRun) string {
+	template := ""
+	switch run.Kind {
+	case TextURL:
+		return run.Value
+	case TextHashtag:
+		template = orDefault(r.HashtagURL, DefaultHashtagURL)
+	case TextMention:
+		template = orDefault(r.MentionURL, DefaultMentionURL)
+	case TextSymbol:
+		template = orDefault(r.SymbolURL, DefaultSymbolURL)
+	}
+	return strings.Replace(template, "{value}", url.PathEscape(run.Value), -1)
+}
+
+// link returns a link in the Renderer's format.
+func (r *Renderer) link(text, href string) string {
+	if r.Format == RenderHTML {
+		return `<a href="` + html.EscapeString(href) + `">` + html.EscapeString(text) + `</a>`
+	}
+	return "[" + r.escape(text) + "](" + strings.NewReplacer("(", "%28", ")", "%29", " ", "%20").Replace(href) + ")"
+}
+
+// markdownEscaper escapes characters with meaning in Markdown.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "#", `\#`, "~", `\~`, "|", `\|`, "!", `\!`,
+)
+
+// escape escapes text for the Renderer's format.
+func (r *Renderer) escape(text string) string {
+	switch r.Format {
+	case RenderHTML:
+		return html.EscapeString(text)
+	case RenderMarkdown:
+		return markdownEscaper.Replace(text)
+	}
+	return text
+}
+
+// orDefault returns the value, or the default if the value is empty.
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
@@ -0,0 +1,107 @@
+package twitter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// extended mode Tweet with emoji before its entities and a trailing photo
+const testRenderTweetJSON = `{
+	"full_text": "@gopher 🐹🐹 I &lt;3 #golang &amp; $GOOG, see https://t.co/a1 https://t.co/p2",
+	"display_text_range": [8, 52],
+	"user": {"screen_name": "alice"},
+	"entities": {
+		"hashtags": [{"text": "golang", "indices": [16, 23]}],
+		"symbols": [{"text": "GOOG", "indices": [26, 31]}],
+		"user_mentions": [{"screen_name": "gopher", "indices": [0, 7]}],
+		"urls": [{"url": "https://t.co/a1", "expanded_url": "https://go.dev/blog", "display_url": "go.dev/blog", "indices": [37, 52]}],
+		"media": [{"url": "https://t.co/p2", "expanded_url": "https://twitter.com/alice/status/1/photo/1", "display_url": "pic.twitter.com/p2", "indices": [53, 68]}]
+	}
+}`
+
+func testRenderTweet(t *testing.T) *Tweet {
+	tweet := new(Tweet)
+	assert.Nil(t, json.Unmarshal([]byte(testRenderTweetJSON), tweet))
+	return tweet
+}
+
+func TestTweetView_Runs(t *testing.T) {
+	expected := []TextRun{
+		{TextPlain, "🐹🐹 I <3 ", "🐹🐹 I <3 "},
+		{TextHashtag, "#golang", "golang"},
+		{TextPlain, " & ", " & "},
+		{TextSymbol, "$GOOG", "GOOG"},
+		{TextPlain, ", see ", ", see "},
+		{TextURL, "go.dev/blog", "https://go.dev/blog"},
+	}
+	tweet := testRenderTweet(t)
+	tweet.DisplayTextRange = Indices{8, 68}
+	assert.Equal(t, expected, tweet.View().Runs())
+}
+
+func TestTweetView_Runs_escapedIndices(t *testing.T) {
+	// indices which refer to the escaped text are also supported
+	tweet := &Tweet{
+		Text:     "&lt;3 #go",
+		Entities: &Entities{Hashtags: []HashtagEntity{{Text: "go", Indices: Indices{6, 9}}}},
+	}
+	expected := []TextRun{
+		{TextPlain, "<3 ", "<3 "},
+		{TextHashtag, "#go", "go"},
+	}
+	assert.Equal(t, expected, tweet.View().Runs())
+}
+
+func TestTweetView_Runs_invalidIndices(t *testing.T) {
+	tweet := &Tweet{
+		Text: "hello world",
+		Entities: &Entities{
+			Hashtags: []HashtagEntity{{Text: "nope", Indices: Indices{20, 25}}},
+			Urls:     []URLEntity{{URL: "https://t.co/x", Indices: Indices{4, 2}}},
+		},
+	}
+	assert.Equal(t, []TextRun{{TextPlain, "hello world", "hello world"}}, tweet.View().Runs())
+}
+
+func TestRenderer_Render(t *testing.T) {
+	tweet := testRenderTweet(t)
+	cases := []struct {
+		renderer *Renderer
+		expected string
+	}{
+		{&Renderer{}, "🐹🐹 I <3 #golang & $GOOG, see https://go.dev/blog"},
+		{&Renderer{Format: RenderHTML}, `🐹🐹 I &lt;3 <a href="https://twitter.com/hashtag/golang">#golang</a> &amp; <a href="https://twitter.com/search?q=%24GOOG">$GOOG</a>, see <a href="https://go.dev/blog">go.dev/blog</a>`},
+		{&Renderer{Format: RenderMarkdown}, `🐹🐹 I \<3 [\#golang](https://twitter.com/hashtag/golang) & [$GOOG](https://twitter.com/search?q=%24GOOG), see [go.dev/blog](https://go.dev/blog)`},
+		{&Renderer{Format: RenderHTML, HashtagURL: "/tags/{value}", SymbolURL: "/quote?s={value}"}, `🐹🐹 I &lt;3 <a href="/tags/golang">#golang</a> &amp; <a href="/quote?s=GOOG">$GOOG</a>, see <a href="https://go.dev/blog">go.dev/blog</a>`},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, c.renderer.Render(tweet))
+	}
+}
+
+func TestRenderer_Render_mention(t *testing.T) {
+	tweet := &Tweet{
+		Text: "hi @gopher_1 (x)",
+		Entities: &Entities{
+			UserMentions: []MentionEntity{{ScreenName: "gopher_1", Indices: Indices{3, 12}}},
+		},
+	}
+	renderer := &Renderer{Format: RenderMarkdown, MentionURL: "https://example.com/u/{value}"}
+	assert.Equal(t, `hi [@gopher\_1](https://example.com/u/gopher_1) (x)`, renderer.Render(tweet))
+}
+
+func TestRenderer_Render_markdownImage(t *testing.T) {
+	// an escaped "!" keeps a link-like text from rendering as an image
+	tweet := &Tweet{Text: "wow! ![gopher](https://example.com/gopher.png)"}
+	renderer := &Renderer{Format: RenderMarkdown}
+	assert.Equal(t, `wow\! \!\[gopher\](https://example.com/gopher.png)`, renderer.Render(tweet))
+}
+
+func TestRenderer_Render_nil(t *testing.T) {
+	for _, format := range []RenderFormat{RenderText, RenderHTML, RenderMarkdown} {
+		renderer := &Renderer{Format: format}
+		assert.Equal(t, "", renderer.Render(nil))
+	}
+}
@@ -0,0 +1,61 @@
+package twitter
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var errFormAndJSON = errors.New("twitter: request params may set Form or JSON, not both")
+
+// RequestParams are the parameters of a Client.Do request. Query and Form
+// are url tagged structs, like the typed params structs, or url.Values. JSON
+// is encoded as the request body. Set at most one of Form and JSON.
+type RequestParams struct {
+	Query interface{}
+	Form  interface{}
+	JSON  interface{}
+}
+
+// Do sends a request to an API endpoint without a service method (e.g. mutes,
+// saved searches, or help endpoints) and decodes the JSON response into v,
+// if v is non-nil. The path is resolved against the API base URL, so pass
+// paths like "mutes/users/ids.json" or an absolute URL. Requests use the
+// Client's http.Client and options and errors are returned as APIError, like
+// service requests.
+func (c *Client) Do(method, path string, params *RequestParams, v interface{}) (*http.Response, error) {
+	if params == nil {
+		params = &RequestParams{}
+	}
+	if params.Form != nil && params.JSON != nil {
+		return nil, errFormAndJSON
+	}
+	s := c.sling.New().Path(path)
+	query, ok := params.Query.(url.Values)
+	if !ok {
+		s.QueryStruct(params.Query)
+	}
+	if form, ok := params.Form.(url.Values); ok {
+		s.Body(strings.NewReader(form.Encode())).Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		s.BodyForm(params.Form)
+	}
+	s.BodyJSON(params.JSON)
+
+	req, err := s.Request()
+	if err != nil {
+		return nil, err
+	}
+	req.Method = strings.ToUpper(method)
+	if len(query) > 0 {
+		values := req.URL.Query()
+		for key, vals := range query {
+			values[key] = append(values[key], vals...)
+		}
+		req.URL.RawQuery = values.Encode()
+	}
+	apiError := new(APIError)
+	resp, err := s.Do(req, v, apiError)
+	return resp, relevantError(err, *apiError)
+}
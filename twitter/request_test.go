@@ -0,0 +1,106 @@
+package twitter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mutedIDs struct {
+	IDs        []int64 `json:"ids"`
+	NextCursor int64   `json:"next_cursor"`
+}
+
+func TestClient_Do_query(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+
+	mux.HandleFunc("/1.1/mutes/users/ids.json", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, "GET", r)
+		assertQuery(t, map[string]string{"cursor": "5", "stringify_ids": "false"}, r)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"ids":[123,456],"next_cursor":0}`)
+	})
+
+	client := NewClient(httpClient)
+	params := struct {
+		Cursor       int64 `url:"cursor"`
+		StringifyIDs bool  `url:"stringify_ids"`
+	}{5, false}
+	ids := new(mutedIDs)
+	resp, err := client.Do("get", "mutes/users/ids.json", &RequestParams{Query: params}, ids)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, &mutedIDs{IDs: []int64{123, 456}}, ids)
+
+	_, err = client.Do("GET", "mutes/users/ids.json?cursor=5", &RequestParams{Query: url.Values{"stringify_ids": {"false"}}}, ids)
+	assert.Nil(t, err)
+}
+
+func TestClient_Do_form(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+
+	mux.HandleFunc("/1.1/saved_searches/create.json", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, "POST", r)
+		assertPostForm(t, map[string]string{"query": "#golang"}, r)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":9,"query":"#golang"}`)
+	})
+
+	client := NewClient(httpClient)
+	var search map[string]interface{}
+	_, err := client.Do("POST", "saved_searches/create.json", &RequestParams{Form: url.Values{"query": {"#golang"}}}, &search)
+	assert.Nil(t, err)
+	assert.Equal(t, "#golang", search["query"])
+
+	params := &struct {
+		Query string `url:"query"`
+	}{"#golang"}
+	_, err = client.Do("POST", "saved_searches/create.json", &RequestParams{Form: params}, nil)
+	assert.Nil(t, err)
+}
+
+func TestClient_Do_json(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+
+	mux.HandleFunc("/1.1/collections/entries/curate.json", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, "POST", r)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		data, _ := ioutil.ReadAll(r.Body)
+		assert.Equal(t, `{"id":"custom-1"}`+"\n", string(data))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"objects":{}}`)
+	})
+
+	client := NewClient(httpClient)
+	var raw json.RawMessage
+	_, err := client.Do("POST", "collections/entries/curate.json", &RequestParams{JSON: map[string]string{"id": "custom-1"}}, &raw)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"objects":{}}`, string(raw))
+}
+
+func TestClient_Do_errors(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+
+	mux.HandleFunc("/1.1/help/configuration.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprintf(w, `{"errors":[{"message":"Could not authenticate you.","code":32}]}`)
+	})
+
+	client := NewClient(httpClient)
+	resp, err := client.Do("GET", "help/configuration.json", nil, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, APIError{Errors: []ErrorDetail{{Message: "Could not authenticate you.", Code: 32}}}, err)
+
+	_, err = client.Do("POST", "help/configuration.json", &RequestParams{Form: url.Values{}, JSON: struct{}{}}, nil)
+	assert.Equal(t, errFormAndJSON, err)
+}
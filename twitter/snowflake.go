@@ -0,0 +1,133 @@
+package twitter
+
+import (
+	"time"
+)
+
+// Snowflake IDs are 64-bit integers with a millisecond timestamp (relative
+// to the Twitter epoch), datacenter and worker IDs, and a sequence number.
+// https://developer.twitter.com/en/docs/twitter-ids
+const (
+	// Twitter epoch in Unix milliseconds (2010-11-04T01:42:54.657Z)
+	snowflakeEpoch           = 1288834974657
+	snowflakeTimeShift       = 22
+	snowflakeDatacenterShift = 17
+	snowflakeWorkerShift     = 12
+	snowflakeSequenceMask    = 1<<12 - 1
+	snowflakeNodeMask        = 1<<5 - 1
+	// first Snowflake ID, earlier IDs were sequential and do not embed a time
+	firstSnowflakeID = 29700859247
+)
+
+// Snowflake is a decoded Snowflake ID.
+type Snowflake struct {
+	Time       time.Time
+	Datacenter int64
+	Worker     int64
+	Sequence   int64
+}
+
+// ParseSnowflake decodes the time, datacenter, worker, and sequence number
+// of a Tweet or Direct Message ID. IDs issued before Snowflake IDs were
+// introduced in November 2010 do not embed these and return a zero Snowflake.
+func ParseSnowflake(id int64) Snowflake {
+	if id < firstSnowflakeID {
+		return Snowflake{}
+	}
+	return Snowflake{
+		Time:       SnowflakeTime(id),
+		Datacenter: (id >> snowflakeDatacenterShift) & snowflakeNodeMask,
+		Worker:     (id >> snowflakeWorkerShift) & snowflakeNodeMask,
+		Sequence:   id & snowflakeSequenceMask,
+	}
+}
+
+// SnowflakeTime returns the time embedded in a Tweet or Direct Message ID.
+// IDs issued before Snowflake IDs were introduced return the zero time.
+func SnowflakeTime(id int64) time.Time {
+	if id < firstSnowflakeID {
+		return time.Time{}
+	}
+	millis := id>>snowflakeTimeShift + snowflakeEpoch
+	return time.Unix(0, millis*int64(time.Millisecond)).UTC()
+}
+
+// MinSnowflakeID returns the smallest ID which could have been issued at the
+// given time (at millisecond precision). Times before the Twitter epoch
+// return 0.
+func MinSnowflakeID(t time.Time) int64 {
+	millis := t.UnixNano()/int64(time.Millisecond) - snowflakeEpoch
+	if millis < 0 {
+		return 0
+	}
+	return millis << snowflakeTimeShift
+}
+
+// MaxSnowflakeID returns the largest ID which could have been issued at the
+// given time (at millisecond precision). Times before the Twitter epoch
+// return 0.
+func MaxSnowflakeID(t time.Time) int64 {
+	millis := t.UnixNano()/int64(time.Millisecond) - snowflakeEpoch
+	if millis < 0 {
+		return 0
+	}
+	return millis<<snowflakeTimeShift | (1<<snowflakeTimeShift - 1)
+}
+
+// setSnowflakeBounds sets the since_id and max_id which bound IDs issued at
+// or after start and before end. Zero times leave their bound unchanged.
+// Snowflake IDs are not issued before the Twitter epoch, so an end at or
+// before it sets max_id to 1 to match nothing, since an unset max_id would
+// leave the query unbounded.
+func setSnowflakeBounds(start, end time.Time, sinceID, maxID *int64) {
+	if !start.IsZero() {
+		if *sinceID = MinSnowflakeID(start) - 1; *sinceID < 0 {
+			*sinceID = 0
+		}
+	}
+	if !end.IsZero() {
+		if *maxID = MinSnowflakeID(end) - 1; *maxID < 1 {
+			*maxID = 1
+		}
+	}
+}
+
+// Between sets SinceID and MaxID to match Tweets created at or after start
+// and before end. A zero start or end leaves that bound as it was, and an end
+// before the Twitter epoch (November 2010) matches no Tweets.
+func (p *SearchTweetParams) Between(start, end time.Time) *SearchTweetParams {
+	setSnowflakeBounds(start, end, &p.SinceID, &p.MaxID)
+	return p
+}
+
+// Between sets SinceID and MaxID to match Tweets created at or after start
+// and before end. A zero start or end leaves that bound as it was, and an end
+// before the Twitter epoch (November 2010) matches no Tweets.
+func (p *UserTimelineParams) Between(start, end time.Time) *UserTimelineParams {
+	setSnowflakeBounds(start, end, &p.SinceID, &p.MaxID)
+	return p
+}
+
+// Between sets SinceID and MaxID to match Tweets created at or after start
+// and before end. A zero start or end leaves that bound as it was, and an end
+// before the Twitter epoch (November 2010) matches no Tweets.
+func (p *HomeTimelineParams) Between(start, end time.Time) *HomeTimelineParams {
+	setSnowflakeBounds(start, end, &p.SinceID, &p.MaxID)
+	return p
+}
+
+// Between sets SinceID and MaxID to match Tweets created at or after start
+// and before end. A zero start or end leaves that bound as it was, and an end
+// before the Twitter epoch (November 2010) matches no Tweets.
+func (p *MentionTimelineParams) Between(start, end time.Time) *MentionTimelineParams {
+	setSnowflakeBounds(start, end, &p.SinceID, &p.MaxID)
+	return p
+}
+
+// Between sets SinceID and MaxID to match Tweets created at or after start
+// and before end. A zero start or end leaves that bound as it was, and an end
+// before the Twitter epoch (November 2010) matches no Tweets.
+func (p *RetweetsOfMeTimelineParams) Between(start, end time.Time) *RetweetsOfMeTimelineParams {
+	setSnowflakeBounds(start, end, &p.SinceID, &p.MaxID)
+	return p
+}
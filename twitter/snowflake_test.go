@@ -0,0 +1,103 @@
+package twitter
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSnowflake(t *testing.T) {
+	// Direct Message event created_timestamp "1542410751275"
+	snowflake := ParseSnowflake(1063573894173323269)
+	assert.Equal(t, time.Date(2018, time.November, 16, 23, 25, 51, 275000000, time.UTC), snowflake.Time)
+
+	id := MinSnowflakeID(snowflake.Time) | 3<<17 | 17<<12 | 42
+	expected := Snowflake{Time: snowflake.Time, Datacenter: 3, Worker: 17, Sequence: 42}
+	assert.Equal(t, expected, ParseSnowflake(id))
+
+	// IDs issued before Snowflake IDs do not embed a time
+	for _, id := range []int64{0, 20, firstSnowflakeID - 1} {
+		assert.Equal(t, Snowflake{}, ParseSnowflake(id))
+		assert.True(t, SnowflakeTime(id).IsZero())
+	}
+	assert.False(t, SnowflakeTime(firstSnowflakeID).IsZero())
+}
+
+func TestSnowflakeIDs(t *testing.T) {
+	at := time.Date(2018, time.November, 16, 23, 25, 51, 275000000, time.UTC)
+	minID, maxID := MinSnowflakeID(at), MaxSnowflakeID(at)
+	assert.True(t, minID <= 1063573894173323269)
+	assert.True(t, maxID >= 1063573894173323269)
+	assert.Equal(t, at, SnowflakeTime(minID))
+	assert.Equal(t, at, SnowflakeTime(maxID))
+	assert.Equal(t, at.Add(-time.Millisecond), SnowflakeTime(minID-1))
+	assert.Equal(t, at.Add(time.Millisecond), SnowflakeTime(maxID+1))
+
+	before := time.Date(2009, time.January, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, int64(0), MinSnowflakeID(before))
+	assert.Equal(t, int64(0), MaxSnowflakeID(before))
+}
+
+func TestSearchTweetParams_Between(t *testing.T) {
+	start := time.Date(2018, time.November, 16, 9, 0, 0, 0, time.UTC)
+	end := time.Date(2018, time.November, 16, 10, 0, 0, 0, time.UTC)
+	params := (&SearchTweetParams{Query: "gophers"}).Between(start, end)
+	assert.Equal(t, MinSnowflakeID(start)-1, params.SinceID)
+	assert.Equal(t, MinSnowflakeID(end)-1, params.MaxID)
+	assert.Equal(t, end.Add(-time.Millisecond), SnowflakeTime(params.MaxID))
+
+	httpClient, mux, server := testServer()
+	defer server.Close()
+	mux.HandleFunc("/1.1/search/tweets.json", func(w http.ResponseWriter, r *http.Request) {
+		assertQuery(t, map[string]string{
+			"q":        "gophers",
+			"since_id": fmt.Sprint(params.SinceID),
+			"max_id":   fmt.Sprint(params.MaxID),
+		}, r)
+	})
+	client := NewClient(httpClient)
+	_, _, err := client.Search.Tweets(params)
+	assert.Nil(t, err)
+}
+
+func TestTimelineParams_Between(t *testing.T) {
+	start := time.Date(2018, time.November, 16, 9, 0, 0, 0, time.UTC)
+	sinceID := MinSnowflakeID(start) - 1
+
+	user := (&UserTimelineParams{}).Between(start, time.Time{})
+	assert.Equal(t, &UserTimelineParams{SinceID: sinceID}, user)
+	home := (&HomeTimelineParams{}).Between(start, time.Time{})
+	assert.Equal(t, &HomeTimelineParams{SinceID: sinceID}, home)
+	mentions := (&MentionTimelineParams{}).Between(time.Time{}, start)
+	assert.Equal(t, &MentionTimelineParams{MaxID: sinceID}, mentions)
+	// zero times leave bounds which were already set
+	retweets := (&RetweetsOfMeTimelineParams{SinceID: 5, MaxID: 6}).Between(time.Time{}, time.Time{})
+	assert.Equal(t, &RetweetsOfMeTimelineParams{SinceID: 5, MaxID: 6}, retweets)
+	user = (&UserTimelineParams{MaxID: 6}).Between(start, time.Time{})
+	assert.Equal(t, &UserTimelineParams{SinceID: sinceID, MaxID: 6}, user)
+}
+
+func TestSearchTweetParams_BetweenBeforeEpoch(t *testing.T) {
+	start := time.Date(2009, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC)
+	// an end before the Twitter epoch matches nothing rather than everything
+	params := (&SearchTweetParams{Query: "gophers"}).Between(start, end)
+	assert.Equal(t, &SearchTweetParams{Query: "gophers", MaxID: 1}, params)
+	params = (&SearchTweetParams{}).Between(time.Time{}, time.Unix(0, snowflakeEpoch*int64(time.Millisecond)))
+	assert.Equal(t, int64(1), params.MaxID)
+	// a start before the Twitter epoch leaves the query unbounded below
+	params = (&SearchTweetParams{}).Between(start, time.Time{})
+	assert.Equal(t, &SearchTweetParams{}, params)
+
+	httpClient, mux, server := testServer()
+	defer server.Close()
+	mux.HandleFunc("/1.1/search/tweets.json", func(w http.ResponseWriter, r *http.Request) {
+		assertQuery(t, map[string]string{"q": "gophers", "max_id": "1"}, r)
+	})
+	client := NewClient(httpClient)
+	_, _, err := client.Search.Tweets((&SearchTweetParams{Query: "gophers"}).Between(start, end))
+	assert.Nil(t, err)
+}
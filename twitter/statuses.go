@@ -1,10 +1,13 @@
 package twitter
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
+	"unicode"
 
+	"github.com/dghubble/go-twitter/twitter/twittertext"
 	"github.com/dghubble/sling"
 )
 
@@ -12,7 +15,7 @@ import (
 // https://dev.twitter.com/overview/api/tweets
 type Tweet struct {
 	Coordinates          *Coordinates           `json:"coordinates"`
-	CreatedAt            string                 `json:"created_at"`
+	CreatedAt            Time                   `json:"created_at"`
 	CurrentUserRetweet   *TweetIdentifier       `json:"current_user_retweet"`
 	Entities             *Entities              `json:"entities"`
 	FavoriteCount        int                    `json:"favorite_count"`
@@ -48,11 +51,15 @@ type Tweet struct {
 	QuotedStatusID       int64                  `json:"quoted_status_id"`
 	QuotedStatusIDStr    string                 `json:"quoted_status_id_str"`
 	QuotedStatus         *Tweet                 `json:"quoted_status"`
+	// original JSON, if retained (see WithRawJSON)
+	Raw json.RawMessage `json:"-"`
 }
 
 // CreatedAtTime returns the time a tweet was created.
+//
+// Deprecated: Use CreatedAt.Time.
 func (t Tweet) CreatedAtTime() (time.Time, error) {
-	return time.Parse(time.RubyDate, t.CreatedAt)
+	return t.CreatedAt.parse()
 }
 
 // ExtendedTweet represents fields embedded in extended Tweets when served in
@@ -174,15 +181,29 @@ type StatusUpdateParams struct {
 	CardURI                   string   `url:"card_uri,omitempty"`
 	// Deprecated
 	TweetMode string `url:"tweet_mode,omitempty"`
+	// send the status without checking it with twittertext.Validate first
+	SkipValidation bool `url:"-"`
 }
 
-// Update updates the user's status, also known as Tweeting.
+// Update updates the user's status, also known as Tweeting. Status text
+// which is too long or has invalid characters (see twittertext.Validate)
+// returns an error without making a request, unless SkipValidation is set.
+// With AutoPopulateReplyMetadata, leading @mentions are not counted, since
+// the API moves them into the reply metadata.
 // Requires a user auth context.
 // https://developer.twitter.com/en/docs/twitter-api/v1/tweets/post-and-engage/api-reference/post-statuses-update
 func (s *StatusService) Update(status string, params *StatusUpdateParams) (*Tweet, *http.Response, error) {
 	if params == nil {
 		params = &StatusUpdateParams{}
 	}
+	counted := status
+	if params.AutoPopulateReplyMetadata != nil && *params.AutoPopulateReplyMetadata {
+		counted = trimLeadingMentions(status)
+	}
+	// empty text is allowed with media or attachments
+	if err := twittertext.Validate(counted); err != nil && err != twittertext.ErrEmpty && !params.SkipValidation {
+		return nil, nil, err
+	}
 	params.Status = status
 	tweet := new(Tweet)
 	apiError := new(APIError)
@@ -190,6 +211,26 @@ func (s *StatusService) Update(status string, params *StatusUpdateParams) (*Twee
 	return tweet, resp, relevantError(err, *apiError)
 }
 
+// trimLeadingMentions returns the text without its leading @mentions and
+// the whitespace around them.
+func trimLeadingMentions(text string) string {
+	runes := []rune(text)
+	skipSpace := func(i int) int {
+		for i < len(runes) && unicode.IsSpace(runes[i]) {
+			i++
+		}
+		return i
+	}
+	start := skipSpace(0)
+	for _, mention := range twittertext.ExtractMentions(text) {
+		if mention.Indices[0] != start {
+			break
+		}
+		start = skipSpace(mention.Indices[1])
+	}
+	return string(runes[start:])
+}
+
 // StatusRetweetParams are the parameters for StatusService.Retweet
 type StatusRetweetParams struct {
 	ID        int64  `url:"id,omitempty"`
@@ -278,7 +319,7 @@ type RetweeterIDs struct {
 func (s *StatusService) Retweeters(params *StatusRetweeterParams) (*RetweeterIDs, *http.Response, error) {
 	retweeters := new(RetweeterIDs)
 	apiError := new(APIError)
-	resp, err := s.sling.Get("retweeters/ids.json").QueryStruct(params).Receive(retweeters, apiError)
+	resp, err := s.sling.New().Get("retweeters/ids.json").QueryStruct(params).Receive(retweeters, apiError)
 	return retweeters, resp, relevantError(err, *apiError)
 }
 
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/dghubble/go-twitter/twitter/twittertext"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -101,6 +102,77 @@ func TestStatusService_UpdateHandlesNilParams(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestStatusService_UpdateValidatesStatus(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+	var requests int
+	mux.HandleFunc("/1.1/statuses/update.json", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	})
+	client := NewClient(httpClient)
+	_, resp, err := client.Statuses.Update(strings.Repeat("日本", 71), nil)
+	assert.Nil(t, resp)
+	assert.Equal(t, twittertext.ErrTooLong, err)
+	_, _, err = client.Statuses.Update("bad \uFEFF", nil)
+	assert.Equal(t, twittertext.ErrInvalidCharacter, err)
+	assert.Equal(t, 0, requests)
+
+	// media Tweets may have empty text
+	_, _, err = client.Statuses.Update("", &StatusUpdateParams{MediaIds: []int64{123456789}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, requests)
+
+	// the API decides when validation is skipped
+	_, _, err = client.Statuses.Update(strings.Repeat("日本", 71), &StatusUpdateParams{SkipValidation: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, requests)
+
+	// scheme-less country code domains without a path aren't URLs
+	_, _, err = client.Statuses.Update("ok.so what "+strings.Repeat("a", 269), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, requests)
+}
+
+func TestStatusService_UpdateReplyMetadata(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+	// leading mentions move to the reply metadata and are not counted
+	mentions := strings.Repeat("@"+strings.Repeat("g", 19)+" ", 10)
+	status := mentions + strings.Repeat("a", 200)
+	var requests int
+	mux.HandleFunc("/1.1/statuses/update.json", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assertPostForm(t, map[string]string{"status": status, "auto_populate_reply_metadata": "true", "in_reply_to_status_id": "20"}, r)
+	})
+	client := NewClient(httpClient)
+	_, _, err := client.Statuses.Update(status, &StatusUpdateParams{InReplyToStatusID: 20, AutoPopulateReplyMetadata: Bool(true)})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, requests)
+
+	_, _, err = client.Statuses.Update(status, &StatusUpdateParams{InReplyToStatusID: 20})
+	assert.Equal(t, twittertext.ErrTooLong, err)
+	_, _, err = client.Statuses.Update(status+strings.Repeat("a", 81), &StatusUpdateParams{InReplyToStatusID: 20, AutoPopulateReplyMetadata: Bool(true)})
+	assert.Equal(t, twittertext.ErrTooLong, err)
+	assert.Equal(t, 1, requests)
+}
+
+func TestTrimLeadingMentions(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected string
+	}{
+		{"@alice @bob hi @carol", "hi @carol"},
+		{"  @alice\n@bob  hi", "hi"},
+		{"hi @alice", "hi @alice"},
+		{"@alice @bob", ""},
+		{"@alice/list hi", "@alice/list hi"},
+		{"🐹 @alice", "🐹 @alice"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, trimLeadingMentions(c.text))
+	}
+}
+
 func TestStatusService_APIError(t *testing.T) {
 	httpClient, mux, server := testServer()
 	defer server.Close()
@@ -252,6 +324,29 @@ func TestStatusService_Retweeters(t *testing.T) {
 	assert.Equal(t, expected, tweets)
 }
 
+func TestStatusService_RetweetersThenShow(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+	mux.HandleFunc("/1.1/statuses/retweeters/ids.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"ids": [316736642]}`)
+	})
+	mux.HandleFunc("/1.1/statuses/show.json", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, "GET", r)
+		assertQuery(t, map[string]string{"id": "589488862814076930"}, r)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id": 589488862814076930}`)
+	})
+
+	// Retweeters must not change the path of later requests
+	client := NewClient(httpClient)
+	_, _, err := client.Statuses.Retweeters(&StatusRetweeterParams{ID: 1554515292390408192})
+	assert.Nil(t, err)
+	tweet, _, err := client.Statuses.Show(589488862814076930, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(589488862814076930), tweet.ID)
+}
+
 func TestStatusService_Destroy(t *testing.T) {
 	httpClient, mux, server := testServer()
 	defer server.Close()
@@ -0,0 +1,274 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dghubble/sling"
+)
+
+const (
+	// maximum length of a search query
+	searchMaxQuery = 500
+	// maximum number of Tweets per search and user timeline page
+	searchMaxCount   = 100
+	timelineMaxCount = 200
+	// default number of pages requested per backfill query
+	backfillMaxPages = 5
+)
+
+var errBackfillPhrase = errors.New("twitter: backfill Track phrases must fit in a search query")
+
+// StreamBackfillParams are the parameters for backfilling Tweets a Filter
+// Stream may have missed while reconnecting. Track predicates are backfilled
+// with SearchService.Tweets and Follow predicates with
+// TimelineService.UserTimeline. Each Follow user takes its own requests, so
+// set MaxUsers to backfill only the first MaxUsers Follow users and stay
+// within rate limits. Locations predicates are not backfilled.
+type StreamBackfillParams struct {
+	// maximum number of pages requested per query (default 5)
+	MaxPages int
+	// maximum number of Follow users backfilled (default all)
+	MaxUsers  int
+	TweetMode string
+}
+
+// BackfilledTweet is a Tweet which was fetched from the REST API after a
+// Stream reconnected, because it may have been missed while disconnected.
+type BackfilledTweet struct {
+	*Tweet
+}
+
+// backfiller fetches Tweets matching the predicates of a Filter stream which
+// are newer than a given Tweet ID.
+type backfiller struct {
+	sling     *sling.Sling
+	doer      sling.Doer
+	queries   []string
+	follow    []int64
+	languages map[string]bool
+	maxPages  int
+	tweetMode string
+}
+
+// newBackfiller returns a backfiller for the predicates of the given params.
+// Track phrases too long for a search query return an error.
+func newBackfiller(srv *StreamService, params *StreamFilterParams) (*backfiller, error) {
+	queries, err := searchQueries(params.Track)
+	if err != nil {
+		return nil, err
+	}
+	b := &backfiller{
+		sling:     srv.rest,
+		doer:      srv.doer,
+		queries:   queries,
+		maxPages:  params.Backfill.MaxPages,
+		tweetMode: params.Backfill.TweetMode,
+	}
+	if b.maxPages <= 0 {
+		b.maxPages = backfillMaxPages
+	}
+	maxUsers := params.Backfill.MaxUsers
+	for _, id := range params.Follow {
+		if maxUsers > 0 && len(b.follow) == maxUsers {
+			break
+		}
+		if userID, err := strconv.ParseInt(id, 10, 64); err == nil {
+			b.follow = append(b.follow, userID)
+		}
+	}
+	if len(params.Language) > 0 {
+		b.languages = make(map[string]bool)
+		for _, lang := range params.Language {
+			b.languages[lang] = true
+		}
+	}
+	return b, nil
+}
+
+// fetch returns Tweets newer than sinceID matching the stream predicates,
+// sorted from oldest to newest. Tweets fetched before an error occurred are
+// returned along with the first error. Closing the done channel cancels
+// pending requests and stops the fetch.
+func (b *backfiller) fetch(sinceID int64, done <-chan struct{}) ([]Tweet, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	base := b.sling.New().Doer(contextDoer{doer: b.doer, ctx: ctx})
+	search := newSearchService(base.New())
+	timelines := newTimelineService(base.New())
+
+	found := make(map[int64]Tweet)
+	var firstErr error
+	for _, query := range b.queries {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err := b.searchTweets(search, query, sinceID, found); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	for _, userID := range b.follow {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err := b.userTweets(timelines, userID, sinceID, found); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	tweets := make([]Tweet, 0, len(found))
+	for _, tweet := range found {
+		if b.languages == nil || b.languages[tweet.Lang] {
+			tweets = append(tweets, tweet)
+		}
+	}
+	sort.Slice(tweets, func(i, j int) bool {
+		return tweets[i].ID < tweets[j].ID
+	})
+	return tweets, firstErr
+}
+
+// searchTweets adds recent Tweets matching the query to found.
+func (b *backfiller) searchTweets(search *SearchService, query string, sinceID int64, found map[int64]Tweet) error {
+	params := &SearchTweetParams{
+		Query:      query,
+		ResultType: "recent",
+		Count:      searchMaxCount,
+		SinceID:    sinceID,
+		TweetMode:  b.tweetMode,
+	}
+	for page := 0; page < b.maxPages; page++ {
+		result, _, err := search.Tweets(params)
+		if err != nil {
+			return err
+		}
+		params.MaxID = addTweets(result.Statuses, found)
+		if len(result.Statuses) < searchMaxCount {
+			return nil
+		}
+	}
+	return nil
+}
+
+// userTweets adds recent Tweets and retweets by the user to found.
+func (b *backfiller) userTweets(timelines *TimelineService, userID, sinceID int64, found map[int64]Tweet) error {
+	params := &UserTimelineParams{
+		UserID:          userID,
+		Count:           timelineMaxCount,
+		SinceID:         sinceID,
+		IncludeRetweets: Bool(true),
+		TweetMode:       b.tweetMode,
+	}
+	for page := 0; page < b.maxPages; page++ {
+		tweets, _, err := timelines.UserTimeline(params)
+		if err != nil {
+			return err
+		}
+		params.MaxID = addTweets(tweets, found)
+		if len(tweets) < timelineMaxCount {
+			return nil
+		}
+	}
+	return nil
+}
+
+// contextDoer is a sling.Doer which sends requests with the wrapped Doer
+// under a Context, so they can be cancelled.
+type contextDoer struct {
+	doer sling.Doer
+	ctx  context.Context
+}
+
+// Do sends the request with the doer's Context.
+func (d contextDoer) Do(req *http.Request) (*http.Response, error) {
+	return d.doer.Do(req.WithContext(d.ctx))
+}
+
+// addTweets adds Tweets to found and returns the max_id for requesting the
+// next (older) page of results.
+func addTweets(tweets []Tweet, found map[int64]Tweet) int64 {
+	var minID int64
+	for _, tweet := range tweets {
+		found[tweet.ID] = tweet
+		if minID == 0 || tweet.ID < minID {
+			minID = tweet.ID
+		}
+	}
+	return minID - 1
+}
+
+// searchQueries combines track phrases into as few OR search queries as fit
+// within the maximum query length. Like track phrases, space separated terms
+// in a search query must all match, so phrases of several terms are grouped
+// in parentheses. A phrase longer than the maximum query length returns an
+// error.
+func searchQueries(track []string) ([]string, error) {
+	var queries []string
+	var query string
+	for _, phrase := range track {
+		phrase = strings.Join(strings.Fields(phrase), " ")
+		if phrase == "" {
+			continue
+		}
+		if strings.Contains(phrase, " ") {
+			phrase = "(" + phrase + ")"
+		}
+		if len(phrase) > searchMaxQuery {
+			return nil, errBackfillPhrase
+		}
+		if query != "" && len(query)+len(" OR ")+len(phrase) > searchMaxQuery {
+			queries = append(queries, query)
+			query = ""
+		}
+		if query != "" {
+			query += " OR "
+		}
+		query += phrase
+	}
+	if query != "" {
+		queries = append(queries, query)
+	}
+	return queries, nil
+}
+
+// backfillSince sends Tweets matching the stream predicates which are newer
+// than the last Tweet received, if the stream was created with backfill and
+// has previously received Tweets. Backfill errors are sent as messages.
+func (s *Stream) backfillSince() {
+	if s.backfill == nil {
+		return
+	}
+	sinceID, _ := s.LastTweet()
+	if sinceID == 0 {
+		return
+	}
+	tweets, err := s.backfill.fetch(sinceID, s.done)
+	if stopped(s.done) {
+		return
+	}
+	if err != nil {
+		select {
+		case s.Messages <- err:
+		case <-s.done:
+			return
+		}
+	}
+	for i := range tweets {
+		s.seen(tweets[i].ID)
+		select {
+		case s.Messages <- &BackfilledTweet{Tweet: &tweets[i]}:
+		case <-s.done:
+			return
+		}
+	}
+}
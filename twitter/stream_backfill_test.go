@@ -0,0 +1,212 @@
+package twitter
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchQueries(t *testing.T) {
+	long := strings.Repeat("a", 300)
+	cases := []struct {
+		track    []string
+		expected []string
+	}{
+		{nil, nil},
+		{[]string{"gophercon", "golang talks"}, []string{"gophercon OR (golang talks)"}},
+		{[]string{" a  b ", "", "c"}, []string{"(a b) OR c"}},
+		{[]string{long, long, "b"}, []string{long, long + " OR b"}},
+		// parentheses count towards the query length
+		{[]string{long, long[:193] + " c"}, []string{long, "(" + long[:193] + " c)"}},
+		{[]string{long, long[:192] + " c"}, []string{long + " OR (" + long[:192] + " c)"}},
+		{[]string{strings.Repeat("a", searchMaxQuery)}, []string{strings.Repeat("a", searchMaxQuery)}},
+	}
+	for _, c := range cases {
+		queries, err := searchQueries(c.track)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, queries)
+	}
+}
+
+func TestSearchQueries_phraseTooLong(t *testing.T) {
+	long := strings.Repeat("a", searchMaxQuery)
+	for _, track := range [][]string{{"golang", long + "a"}, {long[:250] + " " + long[:248]}} {
+		queries, err := searchQueries(track)
+		assert.Equal(t, errBackfillPhrase, err)
+		assert.Nil(t, queries)
+	}
+}
+
+func TestNewBackfiller_maxUsers(t *testing.T) {
+	client := NewClient(http.DefaultClient)
+	follow := make([]string, 20)
+	for i := range follow {
+		follow[i] = fmt.Sprint(i + 1)
+	}
+	params := &StreamFilterParams{Follow: follow, Backfill: &StreamBackfillParams{}}
+	// all Follow users are backfilled by default
+	backfill, err := newBackfiller(client.Streams, params)
+	assert.NoError(t, err)
+	assert.Len(t, backfill.follow, 20)
+	params.Backfill.MaxUsers = 2
+	backfill, err = newBackfiller(client.Streams, params)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, backfill.follow)
+}
+
+func TestStreamService_FilterBackfillPhraseTooLong(t *testing.T) {
+	client := NewClient(http.DefaultClient)
+	stream, err := client.Streams.Filter(&StreamFilterParams{
+		Track:    []string{strings.Repeat("a", searchMaxQuery+1)},
+		Backfill: &StreamBackfillParams{},
+	})
+	assert.Equal(t, errBackfillPhrase, err)
+	assert.Nil(t, stream)
+}
+
+func TestAddTweets(t *testing.T) {
+	found := make(map[int64]Tweet)
+	maxID := addTweets([]Tweet{{ID: 30}, {ID: 20}}, found)
+	assert.Equal(t, int64(19), maxID)
+	assert.Len(t, found, 2)
+}
+
+func TestStream_FilterBackfill(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+
+	reqCount := 0
+	mux.HandleFunc("/1.1/statuses/filter.json", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, "POST", r)
+		assertQuery(t, map[string]string{"track": "gophercon,golang", "follow": "12"}, r)
+		switch reqCount {
+		case 0, 1:
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Transfer-Encoding", "chunked")
+			fmt.Fprintf(w, `{"id": %d, "retweet_count": 0}`+"\r\n", 10+reqCount*30)
+		default:
+			// Only allow first two requests
+			http.Error(w, "Stream API not available!", 500)
+		}
+		reqCount++
+	})
+	mux.HandleFunc("/1.1/search/tweets.json", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, "GET", r)
+		assertQuery(t, map[string]string{"q": "gophercon OR golang", "result_type": "recent", "count": "100", "since_id": "10", "tweet_mode": "extended"}, r)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"statuses": [{"id": 30}, {"id": 20}]}`)
+	})
+	mux.HandleFunc("/1.1/statuses/user_timeline.json", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, "GET", r)
+		assertQuery(t, map[string]string{"user_id": "12", "count": "200", "since_id": "10", "include_rts": "true", "tweet_mode": "extended"}, r)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `[{"id": 25}, {"id": 20}]`)
+	})
+
+	client := NewClient(httpClient)
+	stream, err := client.Streams.Filter(&StreamFilterParams{
+		Track:    []string{"gophercon", "golang"},
+		Follow:   []string{"12"},
+		Backfill: &StreamBackfillParams{TweetMode: "extended"},
+	})
+	assert.NoError(t, err)
+	defer stream.Stop()
+
+	var ids []int64
+	var backfilled []int64
+	for message := range stream.Messages {
+		switch msg := message.(type) {
+		case *Tweet:
+			ids = append(ids, msg.ID)
+		case *BackfilledTweet:
+			backfilled = append(backfilled, msg.ID)
+		default:
+			t.Errorf("unexpected message %v", message)
+		}
+	}
+	assert.Equal(t, []int64{10, 40}, ids)
+	assert.Equal(t, []int64{20, 25, 30}, backfilled)
+	lastID, lastAt := stream.LastTweet()
+	assert.Equal(t, int64(40), lastID)
+	assert.False(t, lastAt.IsZero())
+}
+
+func TestStream_FilterBackfillError(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+
+	reqCount := 0
+	mux.HandleFunc("/1.1/statuses/filter.json", func(w http.ResponseWriter, r *http.Request) {
+		switch reqCount {
+		case 0, 1:
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Transfer-Encoding", "chunked")
+			fmt.Fprintf(w, `{"id": %d, "retweet_count": 0}`+"\r\n", 10+reqCount)
+		default:
+			http.Error(w, "Stream API not available!", 500)
+		}
+		reqCount++
+	})
+	mux.HandleFunc("/1.1/search/tweets.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(429)
+		fmt.Fprintf(w, `{"errors": [{"message": "Rate limit exceeded", "code": 88}]}`)
+	})
+
+	client := NewClient(httpClient)
+	stream, err := client.Streams.Filter(&StreamFilterParams{
+		Track:    []string{"golang"},
+		Backfill: &StreamBackfillParams{},
+	})
+	assert.NoError(t, err)
+	defer stream.Stop()
+
+	var messages []interface{}
+	for message := range stream.Messages {
+		messages = append(messages, message)
+	}
+	if assert.Len(t, messages, 3) {
+		assert.Equal(t, APIError{Errors: []ErrorDetail{{Message: "Rate limit exceeded", Code: 88}}}, messages[1])
+	}
+}
+
+func TestStream_StopCancelsBackfill(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+
+	mux.HandleFunc("/1.1/statuses/filter.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Transfer-Encoding", "chunked")
+		fmt.Fprintf(w, `{"id": 10, "retweet_count": 0}`+"\r\n")
+	})
+	searching := make(chan struct{})
+	mux.HandleFunc("/1.1/search/tweets.json", func(w http.ResponseWriter, r *http.Request) {
+		close(searching)
+		// block until the request is cancelled
+		<-r.Context().Done()
+	})
+
+	client := NewClient(httpClient)
+	stream, err := client.Streams.Filter(&StreamFilterParams{
+		Track:    []string{"golang"},
+		Backfill: &StreamBackfillParams{},
+	})
+	assert.NoError(t, err)
+	<-stream.Messages
+	<-searching
+
+	stopped := make(chan struct{})
+	go func() {
+		stream.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop blocked on a backfill request")
+	}
+}
@@ -0,0 +1,331 @@
+package twitter
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var errFilterCoordinates = errors.New("twitter: filter Locations coordinates must be numbers")
+
+// filter_level values in increasing order of strictness
+var filterLevels = map[string]int{
+	"":       0,
+	"none":   0,
+	"low":    1,
+	"medium": 2,
+}
+
+// FilterMatcher reports whether Tweets match StreamFilterParams using the
+// matching rules of the Filter stream, so recorded Tweets or Tweets from the
+// Sample stream can be checked against a set of predicates.
+//
+// A Tweet matches if it matches any of the Track, Follow or Locations
+// predicates, and also any Language and FilterLevel restrictions.
+// https://developer.twitter.com/en/docs/twitter-api/v1/tweets/filter-realtime/guides/basic-stream-parameters
+type FilterMatcher struct {
+	track       [][]string
+	follow      map[int64]bool
+	locations   []locationBox
+	languages   map[string]bool
+	filterLevel int
+}
+
+// locationBox is a bounding box given by its south-west and north-east
+// corners.
+type locationBox struct {
+	west, south, east, north float64
+}
+
+// NewFilterMatcher returns a FilterMatcher for the given params.
+func NewFilterMatcher(params *StreamFilterParams) (*FilterMatcher, error) {
+	if params == nil {
+		params = &StreamFilterParams{}
+	}
+	if len(params.Locations)%4 != 0 {
+		return nil, errFilterLocations
+	}
+	m := &FilterMatcher{
+		follow:      make(map[int64]bool),
+		filterLevel: filterLevels[params.FilterLevel],
+	}
+	for _, phrase := range params.Track {
+		terms := strings.Fields(strings.ToLower(phrase))
+		if len(terms) > 0 {
+			m.track = append(m.track, terms)
+		}
+	}
+	for _, id := range params.Follow {
+		userID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		m.follow[userID] = true
+	}
+	for i := 0; i < len(params.Locations); i += 4 {
+		var coords [4]float64
+		for j := range coords {
+			value, err := strconv.ParseFloat(strings.TrimSpace(params.Locations[i+j]), 64)
+			if err != nil {
+				return nil, errFilterCoordinates
+			}
+			coords[j] = value
+		}
+		m.locations = append(m.locations, locationBox{west: coords[0], south: coords[1], east: coords[2], north: coords[3]})
+	}
+	if len(params.Language) > 0 {
+		m.languages = make(map[string]bool)
+		for _, lang := range params.Language {
+			m.languages[lang] = true
+		}
+	}
+	return m, nil
+}
+
+// Match returns true if the Tweet matches the predicates. Without any Track,
+// Follow or Locations predicates, no Tweets match.
+func (m *FilterMatcher) Match(tweet *Tweet) bool {
+	if tweet == nil {
+		return false
+	}
+	if m.languages != nil && !m.languages[tweet.Lang] {
+		return false
+	}
+	if filterLevels[tweet.FilterLevel] < m.filterLevel {
+		return false
+	}
+	return m.matchFollow(tweet) || m.matchLocations(tweet) || m.matchTrack(tweet)
+}
+
+// matchTrack returns true if every term of any track phrase matches the
+// Tweet text, URLs, or mentioned screen names, including those of a
+// retweeted or quoted Tweet.
+func (m *FilterMatcher) matchTrack(tweet *Tweet) bool {
+	if len(m.track) == 0 {
+		return false
+	}
+	words := newTrackWords()
+	words.addTweet(tweet)
+	if tweet.RetweetedStatus != nil {
+		words.addTweet(tweet.RetweetedStatus)
+	}
+	if tweet.QuotedStatus != nil {
+		words.addTweet(tweet.QuotedStatus)
+	}
+	for _, terms := range m.track {
+		matched := true
+		for _, term := range terms {
+			if !words.match(term) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// matchFollow returns true if the Tweet was created or retweeted by a
+// followed user, is a retweet of or a reply to one of their Tweets, or is a
+// manual reply starting with a mention of them. Tweets which only mention a
+// followed user elsewhere in the text do not match.
+func (m *FilterMatcher) matchFollow(tweet *Tweet) bool {
+	if len(m.follow) == 0 {
+		return false
+	}
+	if tweet.User != nil && m.follow[tweet.User.ID] {
+		return true
+	}
+	if m.follow[tweet.InReplyToUserID] {
+		return true
+	}
+	if tweet.RetweetedStatus != nil && tweet.RetweetedStatus.User != nil && m.follow[tweet.RetweetedStatus.User.ID] {
+		return true
+	}
+	entities := tweet.Entities
+	if tweet.ExtendedTweet != nil && tweet.ExtendedTweet.Entities != nil {
+		entities = tweet.ExtendedTweet.Entities
+	}
+	if entities != nil {
+		for _, mention := range entities.UserMentions {
+			if mention.Indices.Start() == 0 && m.follow[mention.ID] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchLocations returns true if the Tweet's exact coordinates are within a
+// location box, or if it has no coordinates and its Place bounding box
+// overlaps a location box.
+func (m *FilterMatcher) matchLocations(tweet *Tweet) bool {
+	if len(m.locations) == 0 {
+		return false
+	}
+	if tweet.Coordinates != nil {
+		lon, lat := tweet.Coordinates.Coordinates[0], tweet.Coordinates.Coordinates[1]
+		for _, box := range m.locations {
+			if box.contains(lon, lat) {
+				return true
+			}
+		}
+		return false
+	}
+	if tweet.Place == nil || tweet.Place.BoundingBox == nil {
+		return false
+	}
+	place, ok := boundingBoxBounds(tweet.Place.BoundingBox)
+	if !ok {
+		return false
+	}
+	for _, box := range m.locations {
+		if box.intersects(place) {
+			return true
+		}
+	}
+	return false
+}
+
+// contains returns true if the point is within the box, inclusive.
+func (b locationBox) contains(lon, lat float64) bool {
+	return lon >= b.west && lon <= b.east && lat >= b.south && lat <= b.north
+}
+
+// intersects returns true if the boxes overlap.
+func (b locationBox) intersects(o locationBox) bool {
+	return b.west <= o.east && o.west <= b.east && b.south <= o.north && o.south <= b.north
+}
+
+// boundingBoxBounds returns the box which bounds all coordinates of the
+// BoundingBox.
+func boundingBoxBounds(bbox *BoundingBox) (locationBox, bool) {
+	var box locationBox
+	found := false
+	for _, ring := range bbox.Coordinates {
+		for _, point := range ring {
+			lon, lat := point[0], point[1]
+			if !found {
+				box = locationBox{west: lon, south: lat, east: lon, north: lat}
+				found = true
+				continue
+			}
+			if lon < box.west {
+				box.west = lon
+			}
+			if lon > box.east {
+				box.east = lon
+			}
+			if lat < box.south {
+				box.south = lat
+			}
+			if lat > box.north {
+				box.north = lat
+			}
+		}
+	}
+	return box, found
+}
+
+// trackWords indexes the words of Tweet text for track term matching.
+type trackWords struct {
+	// whitespace separated words, trimmed of surrounding punctuation
+	words map[string]bool
+	// runs of letters and digits, also with any leading '#' or '@'
+	tokens map[string]bool
+}
+
+func newTrackWords() *trackWords {
+	return &trackWords{
+		words:  make(map[string]bool),
+		tokens: make(map[string]bool),
+	}
+}
+
+// addTweet indexes the Tweet's full text, the expanded and display forms of
+// its URLs, and the screen names it mentions.
+func (w *trackWords) addTweet(tweet *Tweet) {
+	text, _, entities, extended := tweet.content()
+	w.addText(text)
+	if entities != nil {
+		for _, url := range entities.Urls {
+			w.addURL(url)
+		}
+		for _, media := range entities.Media {
+			w.addURL(media.URLEntity)
+		}
+		for _, mention := range entities.UserMentions {
+			w.addText("@" + mention.ScreenName)
+		}
+	}
+	if extended != nil {
+		for _, media := range extended.Media {
+			w.addURL(media.URLEntity)
+		}
+	}
+}
+
+// addURL indexes the expanded and display forms of a URL. URLs are also
+// indexed without their scheme and "www." prefix.
+func (w *trackWords) addURL(url URLEntity) {
+	for _, value := range []string{url.ExpandedURL, url.DisplayURL} {
+		w.addText(value)
+		value = strings.TrimPrefix(strings.TrimPrefix(value, "https://"), "http://")
+		w.addText(strings.TrimPrefix(value, "www."))
+	}
+}
+
+// addText indexes the words and tokens of the text.
+func (w *trackWords) addText(text string) {
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		w.words[strings.TrimFunc(word, isTrimmable)] = true
+		var token []rune
+		var prefix rune
+		flush := func() {
+			if len(token) > 0 {
+				w.tokens[string(token)] = true
+				if prefix != 0 {
+					w.tokens[string(prefix)+string(token)] = true
+				}
+			}
+			token, prefix = token[:0], 0
+		}
+		for _, r := range word {
+			if isWordRune(r) {
+				token = append(token, r)
+				continue
+			}
+			flush()
+			if r == '#' || r == '@' || r == '$' {
+				prefix = r
+			}
+		}
+		flush()
+	}
+}
+
+// match returns true if the lower case track term matches. Terms made of
+// letters and digits (optionally prefixed by '#', '@' or '$') match tokens
+// regardless of surrounding punctuation, while terms containing other
+// punctuation must match a whole word.
+func (w *trackWords) match(term string) bool {
+	rest := strings.TrimLeft(term, "#@$")
+	if len(term)-len(rest) <= 1 && rest != "" && strings.IndexFunc(rest, func(r rune) bool { return !isWordRune(r) }) == -1 {
+		return w.tokens[term]
+	}
+	return w.words[term]
+}
+
+// isWordRune returns true for runes which form words in track matching.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '_'
+}
+
+// isTrimmable returns true for punctuation which surrounds words, but is not
+// part of them (such as sentence punctuation or quotes).
+func isTrimmable(r rune) bool {
+	return strings.ContainsRune(`.,!?;:"'()[]{}<>`, r) || unicode.In(r, unicode.Pi, unicode.Pf)
+}
@@ -0,0 +1,132 @@
+package twitter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterMatcher_Track(t *testing.T) {
+	cases := []struct {
+		track   string
+		text    string
+		matched bool
+	}{
+		{"Twitter", "TWITTER", true},
+		{"Twitter", `"Twitter"`, true},
+		{"Twitter", "twitter.", true},
+		{"Twitter", "#twitter", true},
+		{"Twitter", "@twitter", true},
+		{"Twitter", "http://twitter.com", true},
+		{"Twitter", "twitterific", false},
+		{"Twitter's", "I like Twitter's new design", true},
+		{"Twitter's", "Someday I'd like to visit @Twitter's office", false},
+		{"twitter api,twitter streaming", "The Twitter API is awesome", true},
+		{"twitter api,twitter streaming", "The twitter streaming service is fast", true},
+		{"twitter api,twitter streaming", "Twitter has a streaming API", true},
+		{"twitter api,twitter streaming", "I'm new to Twitter", false},
+		{"example.com", "Someday I will visit example.com", true},
+		{"example.com", "There is no example.com/foo", false},
+		{"example com", "There is no example.com/foo", true},
+		{"#twitter", "#twitter", true},
+		{"#twitter", "twitter", false},
+		{"@twitterapi", "@twitterapi", true},
+		{"@twitterapi", "twitterapi", false},
+		{"$twtr", "$TWTR is up", true},
+	}
+	for _, c := range cases {
+		matcher, err := NewFilterMatcher(&StreamFilterParams{Track: strings.Split(c.track, ",")})
+		assert.NoError(t, err)
+		tweet := &Tweet{Text: c.text}
+		assert.Equal(t, c.matched, matcher.Match(tweet), "track %q text %q", c.track, c.text)
+	}
+}
+
+func TestFilterMatcher_TrackSources(t *testing.T) {
+	matcher, err := NewFilterMatcher(&StreamFilterParams{Track: []string{"gophercon", "example com", "@golang"}})
+	assert.NoError(t, err)
+	// extended Tweet full text
+	assert.True(t, matcher.Match(&Tweet{
+		Text:          "Going to...",
+		ExtendedTweet: &ExtendedTweet{FullText: "Going to Gophercon"},
+	}))
+	// REST extended mode full text
+	assert.True(t, matcher.Match(&Tweet{FullText: "Going to Gophercon"}))
+	// expanded URLs
+	assert.True(t, matcher.Match(&Tweet{
+		Text:     "look https://t.co/abc",
+		Entities: &Entities{Urls: []URLEntity{{URL: "https://t.co/abc", ExpandedURL: "https://www.example.com/foo"}}},
+	}))
+	// mentioned screen names
+	assert.True(t, matcher.Match(&Tweet{
+		Text:     "hi",
+		Entities: &Entities{UserMentions: []MentionEntity{{ScreenName: "golang"}}},
+	}))
+	// retweeted and quoted Tweets
+	assert.True(t, matcher.Match(&Tweet{Text: "RT", RetweetedStatus: &Tweet{Text: "gophercon!"}}))
+	assert.True(t, matcher.Match(&Tweet{Text: "so true", QuotedStatus: &Tweet{Text: "gophercon!"}}))
+	assert.False(t, matcher.Match(&Tweet{Text: "nothing to see"}))
+}
+
+func TestFilterMatcher_Follow(t *testing.T) {
+	matcher, err := NewFilterMatcher(&StreamFilterParams{Follow: []string{"12"}})
+	assert.NoError(t, err)
+	followed := &User{ID: 12}
+	other := &User{ID: 13}
+	// created by the user (or retweeted by the user)
+	assert.True(t, matcher.Match(&Tweet{User: followed}))
+	// retweets of the user's Tweets
+	assert.True(t, matcher.Match(&Tweet{User: other, RetweetedStatus: &Tweet{User: followed}}))
+	// replies to the user's Tweets
+	assert.True(t, matcher.Match(&Tweet{User: other, InReplyToUserID: 12}))
+	// manual replies
+	assert.True(t, matcher.Match(&Tweet{User: other, Entities: &Entities{
+		UserMentions: []MentionEntity{{ID: 12, Indices: Indices{0, 5}}},
+	}}))
+	// mentions elsewhere do not match
+	assert.False(t, matcher.Match(&Tweet{User: other, Entities: &Entities{
+		UserMentions: []MentionEntity{{ID: 12, Indices: Indices{3, 8}}},
+	}}))
+}
+
+func TestFilterMatcher_Locations(t *testing.T) {
+	// San Francisco
+	matcher, err := NewFilterMatcher(&StreamFilterParams{Locations: []string{"-122.75", "36.8", "-121.75", "37.8"}})
+	assert.NoError(t, err)
+	assert.True(t, matcher.Match(&Tweet{Coordinates: &Coordinates{Coordinates: [2]float64{-122.4, 37.7}}}))
+	assert.False(t, matcher.Match(&Tweet{Coordinates: &Coordinates{Coordinates: [2]float64{-74.0, 40.7}}}))
+	// coordinates take precedence over the place
+	assert.False(t, matcher.Match(&Tweet{
+		Coordinates: &Coordinates{Coordinates: [2]float64{-74.0, 40.7}},
+		Place:       &Place{BoundingBox: &BoundingBox{Coordinates: [][][2]float64{{{-122.5, 37.7}, {-122.3, 37.7}, {-122.3, 37.8}, {-122.5, 37.8}}}}},
+	}))
+	// place bounding boxes which overlap match
+	assert.True(t, matcher.Match(&Tweet{
+		Place: &Place{BoundingBox: &BoundingBox{Coordinates: [][][2]float64{{{-123, 37.5}, {-122.5, 37.5}, {-122.5, 38}, {-123, 38}}}}},
+	}))
+	assert.False(t, matcher.Match(&Tweet{
+		Place: &Place{BoundingBox: &BoundingBox{Coordinates: [][][2]float64{{{-75, 40}, {-73, 40}, {-73, 41}, {-75, 41}}}}},
+	}))
+	assert.False(t, matcher.Match(&Tweet{Text: "no location"}))
+}
+
+func TestFilterMatcher_Restrictions(t *testing.T) {
+	matcher, err := NewFilterMatcher(&StreamFilterParams{Track: []string{"golang"}, Language: []string{"en"}, FilterLevel: "low"})
+	assert.NoError(t, err)
+	assert.True(t, matcher.Match(&Tweet{Text: "golang", Lang: "en", FilterLevel: "medium"}))
+	assert.False(t, matcher.Match(&Tweet{Text: "golang", Lang: "fr", FilterLevel: "medium"}))
+	assert.False(t, matcher.Match(&Tweet{Text: "golang", Lang: "en", FilterLevel: "none"}))
+}
+
+func TestNewFilterMatcher_Errors(t *testing.T) {
+	_, err := NewFilterMatcher(&StreamFilterParams{Locations: []string{"1", "2"}})
+	assert.Equal(t, errFilterLocations, err)
+	_, err = NewFilterMatcher(&StreamFilterParams{Locations: []string{"a", "2", "3", "4"}})
+	assert.Equal(t, errFilterCoordinates, err)
+	_, err = NewFilterMatcher(&StreamFilterParams{Follow: []string{"dghubble"}})
+	assert.Error(t, err)
+	matcher, err := NewFilterMatcher(nil)
+	assert.NoError(t, err)
+	assert.False(t, matcher.Match(&Tweet{Text: "anything"}))
+}
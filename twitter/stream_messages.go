@@ -1,5 +1,10 @@
 package twitter
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // StatusDeletion indicates that a given Tweet has been deleted.
 // https://dev.twitter.com/streaming/overview/messages-types#status_deletion_notices_delete
 type StatusDeletion struct {
@@ -98,13 +103,137 @@ type directMessageNotice struct {
 	DirectMessage *DirectMessage `json:"direct_message"`
 }
 
+// EventKind is the kind of an Event.
+type EventKind string
+
+// Event kinds
+// https://developer.twitter.com/en/docs/twitter-api/v1/tweets/filter-realtime/guides/streaming-message-types
+const (
+	EventAccessRevoked        EventKind = "access_revoked"
+	EventBlock                EventKind = "block"
+	EventUnblock              EventKind = "unblock"
+	EventFavorite             EventKind = "favorite"
+	EventUnfavorite           EventKind = "unfavorite"
+	EventFollow               EventKind = "follow"
+	EventUnfollow             EventKind = "unfollow"
+	EventMute                 EventKind = "mute"
+	EventUnmute               EventKind = "unmute"
+	EventListCreated          EventKind = "list_created"
+	EventListDestroyed        EventKind = "list_destroyed"
+	EventListUpdated          EventKind = "list_updated"
+	EventListMemberAdded      EventKind = "list_member_added"
+	EventListMemberRemoved    EventKind = "list_member_removed"
+	EventListUserSubscribed   EventKind = "list_user_subscribed"
+	EventListUserUnsubscribed EventKind = "list_user_unsubscribed"
+	EventQuotedTweet          EventKind = "quoted_tweet"
+	EventFavoritedRetweet     EventKind = "favorited_retweet"
+	EventRetweetedRetweet     EventKind = "retweeted_retweet"
+	EventUserUpdate           EventKind = "user_update"
+)
+
+// isList returns true for kinds of Events whose target object is a List.
+func (k EventKind) isList() bool {
+	switch k {
+	case EventListCreated, EventListDestroyed, EventListUpdated, EventListMemberAdded,
+		EventListMemberRemoved, EventListUserSubscribed, EventListUserUnsubscribed:
+		return true
+	}
+	return false
+}
+
 // Event is a non-Tweet notification message (e.g. like, retweet, follow).
 // https://dev.twitter.com/streaming/overview/messages-types#Events_event
 type Event struct {
-	Event     string `json:"event"`
-	CreatedAt string `json:"created_at"`
-	Target    *User  `json:"target"`
-	Source    *User  `json:"source"`
-	// TODO: add List or deprecate it
+	Event     EventKind `json:"event"`
+	CreatedAt Time      `json:"created_at"`
+	Target    *User     `json:"target"`
+	Source    *User     `json:"source"`
+	// TargetObject is the target Tweet of Tweet Events (e.g. favorite) and of
+	// unknown Event kinds, or nil. See TargetList for List Events.
 	TargetObject *Tweet `json:"target_object"`
+	// RawTargetObject is the original target_object JSON, if any
+	RawTargetObject json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the target_object as a Tweet, except for List
+// Events. The target_object of every Event is kept in RawTargetObject.
+func (e *Event) UnmarshalJSON(data []byte) error {
+	type event Event
+	aux := struct {
+		*event
+		TargetObject json.RawMessage `json:"target_object"`
+	}{event: (*event)(e)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	e.TargetObject, e.RawTargetObject = nil, nil
+	if len(aux.TargetObject) == 0 || string(aux.TargetObject) == "null" {
+		return nil
+	}
+	e.RawTargetObject = aux.TargetObject
+	switch e.Event {
+	case EventFavorite, EventUnfavorite, EventQuotedTweet, EventFavoritedRetweet, EventRetweetedRetweet:
+		tweet := new(Tweet)
+		if err := json.Unmarshal(aux.TargetObject, tweet); err != nil {
+			return err
+		}
+		e.TargetObject = tweet
+	case EventListCreated, EventListDestroyed, EventListUpdated, EventListMemberAdded,
+		EventListMemberRemoved, EventListUserSubscribed, EventListUserUnsubscribed:
+		// Lists are decoded by TargetList
+	default:
+		// target objects of unknown kinds are usually Tweets, and are kept in
+		// RawTargetObject if not
+		tweet := new(Tweet)
+		if json.Unmarshal(aux.TargetObject, tweet) == nil {
+			e.TargetObject = tweet
+		}
+	}
+	return nil
+}
+
+// TargetTweet returns the target Tweet, or nil. It is the same as
+// TargetObject.
+func (e Event) TargetTweet() *Tweet {
+	return e.TargetObject
+}
+
+// TargetList returns the target List of List Events, decoded from
+// RawTargetObject, or nil.
+func (e Event) TargetList() *List {
+	list := new(List)
+	if !e.Event.isList() || len(e.RawTargetObject) == 0 || json.Unmarshal(e.RawTargetObject, list) != nil {
+		return nil
+	}
+	return list
+}
+
+// CreatedAtTime returns the time the Event occurred.
+//
+// Deprecated: Use CreatedAt.Time.
+func (e Event) CreatedAtTime() (time.Time, error) {
+	return e.CreatedAt.parse()
+}
+
+// SiteStreamMessage is a Site Stream message for a particular user. The
+// wrapped Message is decoded like other stream messages.
+// https://developer.twitter.com/en/docs/twitter-api/v1/tweets/filter-realtime/guides/streaming-message-types
+type SiteStreamMessage struct {
+	ForUser int64       `json:"for_user"`
+	Message interface{} `json:"message"`
+}
+
+type siteStreamEnvelope struct {
+	ForUser int64           `json:"for_user"`
+	Message json.RawMessage `json:"message"`
+}
+
+// StreamControl is sent when a Site Stream connects and gives the URI used
+// to control the stream (see StreamService.SiteAddUsers).
+type StreamControl struct {
+	ControlURI string `json:"control_uri"`
+}
+
+type streamControlNotice struct {
+	Control *StreamControl `json:"control"`
 }
@@ -0,0 +1,194 @@
+package twitter
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// default maximum size of a recording file before rotating
+const recordMaxBytes = 100 << 20
+
+// layout of the time suffix of recording file names
+const recordFileTime = "20060102T150405.000000000"
+
+var errRecorderClosed = errors.New("twitter: FileRecorder is closed")
+
+// A StreamRecorder records the raw messages received by a Stream. Record
+// must not retain data after returning. Record errors do not interrupt the
+// Stream.
+type StreamRecorder interface {
+	Record(data []byte, receivedAt time.Time) error
+}
+
+// recording is a line of a recording file.
+type recording struct {
+	ReceivedAt time.Time       `json:"received_at"`
+	Message    json.RawMessage `json:"message"`
+}
+
+// FileRecorderParams are the parameters for NewFileRecorder.
+type FileRecorderParams struct {
+	// path of recording files, which get a time suffix before the extension
+	// (e.g. "stream.jsonl" records to "stream-20221104T014254.657000000.jsonl")
+	Path string
+	// uncompressed size at which to rotate to a new file (default 100MB)
+	MaxBytes int64
+	// gzip recording files (adds a ".gz" extension)
+	Gzip bool
+}
+
+// FileRecorder is a StreamRecorder which writes messages and the times they
+// were received as lines of JSON to a series of files, rotating to a new
+// file once the current file reaches a maximum size. Recordings can be
+// replayed with ReplayStream.
+//
+// The client must Close() the FileRecorder when finished.
+type FileRecorder struct {
+	path     string
+	maxBytes int64
+	gzip     bool
+	mu       sync.Mutex
+	file     *os.File
+	writer   io.Writer
+	gzipper  *gzip.Writer
+	written  int64
+	closed   bool
+	err      error
+	paths    []string
+	now      func() time.Time
+}
+
+// NewFileRecorder returns a new FileRecorder. Files are created as messages
+// are recorded.
+func NewFileRecorder(params *FileRecorderParams) *FileRecorder {
+	if params == nil {
+		params = &FileRecorderParams{}
+	}
+	maxBytes := params.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = recordMaxBytes
+	}
+	path := params.Path
+	if path == "" {
+		path = "stream.jsonl"
+	}
+	return &FileRecorder{
+		path:     path,
+		maxBytes: maxBytes,
+		gzip:     params.Gzip,
+		now:      time.Now,
+	}
+}
+
+// Record writes the message and the time it was received to the current
+// file, rotating first if the file is full.
+func (r *FileRecorder) Record(data []byte, receivedAt time.Time) error {
+	line, err := json.Marshal(recording{ReceivedAt: receivedAt, Message: recordedMessage(data)})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return errRecorderClosed
+	}
+	if r.file == nil || r.written+int64(len(line)) > r.maxBytes && r.written > 0 {
+		if err := r.rotate(); err != nil {
+			return r.fail(err)
+		}
+	}
+	n, err := r.writer.Write(line)
+	r.written += int64(n)
+	if err != nil {
+		return r.fail(err)
+	}
+	return nil
+}
+
+// Paths returns the paths of the files recorded to so far, in order.
+func (r *FileRecorder) Paths() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.paths...)
+}
+
+// Close closes the current file. Returns the first error encountered while
+// recording, if any.
+func (r *FileRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return r.err
+	}
+	r.closed = true
+	if err := r.closeFile(); err != nil {
+		r.fail(err)
+	}
+	return r.err
+}
+
+// rotate closes the current file and creates the next one.
+func (r *FileRecorder) rotate() error {
+	if err := r.closeFile(); err != nil {
+		return err
+	}
+	ext := filepath.Ext(r.path)
+	path := strings.TrimSuffix(r.path, ext) + "-" + r.now().UTC().Format(recordFileTime) + ext
+	if r.gzip {
+		path += ".gz"
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	r.file, r.writer, r.written = file, file, 0
+	if r.gzip {
+		r.gzipper = gzip.NewWriter(file)
+		r.writer = r.gzipper
+	}
+	r.paths = append(r.paths, path)
+	return nil
+}
+
+// closeFile flushes and closes the current file, if any.
+func (r *FileRecorder) closeFile() error {
+	if r.file == nil {
+		return nil
+	}
+	var err error
+	if r.gzipper != nil {
+		err = r.gzipper.Close()
+	}
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	r.file, r.writer, r.gzipper = nil, nil, nil
+	return err
+}
+
+// fail records the first error and returns the given error.
+func (r *FileRecorder) fail(err error) error {
+	if r.err == nil {
+		r.err = err
+	}
+	return err
+}
+
+// recordedMessage returns a copy of the message JSON, or the message as a
+// JSON string if it is not valid JSON.
+func recordedMessage(data []byte) json.RawMessage {
+	if json.Valid(data) {
+		return append(json.RawMessage(nil), data...)
+	}
+	quoted, _ := json.Marshal(string(data))
+	return quoted
+}
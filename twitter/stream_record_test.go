@@ -0,0 +1,122 @@
+package twitter
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// readLines returns the lines of a recording file.
+func readLines(t *testing.T, path string, gzipped bool) []string {
+	file, err := os.Open(path)
+	if !assert.Nil(t, err) {
+		return nil
+	}
+	defer file.Close()
+	var scanner *bufio.Scanner
+	if gzipped {
+		reader, err := gzip.NewReader(file)
+		if !assert.Nil(t, err) {
+			return nil
+		}
+		scanner = bufio.NewScanner(reader)
+	} else {
+		scanner = bufio.NewScanner(file)
+	}
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+func TestFileRecorder(t *testing.T) {
+	recorder := NewFileRecorder(&FileRecorderParams{Path: filepath.Join(t.TempDir(), "stream.jsonl")})
+	at := time.Date(2022, time.November, 4, 1, 42, 54, 0, time.UTC)
+	assert.Nil(t, recorder.Record([]byte(`{"id": 1, "text": "gophers"}`), at))
+	assert.Nil(t, recorder.Record([]byte(`not json`), at.Add(time.Second)))
+	assert.Nil(t, recorder.Close())
+	assert.Equal(t, errRecorderClosed, recorder.Record([]byte(`{}`), at))
+
+	paths := recorder.Paths()
+	if assert.Len(t, paths, 1) {
+		assert.True(t, strings.HasSuffix(paths[0], ".jsonl"))
+		expected := []string{
+			`{"received_at":"2022-11-04T01:42:54Z","message":{"id":1,"text":"gophers"}}`,
+			`{"received_at":"2022-11-04T01:42:55Z","message":"not json"}`,
+		}
+		assert.Equal(t, expected, readLines(t, paths[0], false))
+	}
+}
+
+func TestFileRecorder_RotateGzip(t *testing.T) {
+	recorder := NewFileRecorder(&FileRecorderParams{
+		Path:     filepath.Join(t.TempDir(), "stream.jsonl"),
+		MaxBytes: 150,
+		Gzip:     true,
+	})
+	tick := time.Date(2022, time.November, 4, 1, 42, 54, 0, time.UTC)
+	recorder.now = func() time.Time {
+		tick = tick.Add(time.Second)
+		return tick
+	}
+	for i := 1; i <= 5; i++ {
+		assert.Nil(t, recorder.Record([]byte(fmt.Sprintf(`{"id": %d, "text": "gophers"}`, i)), tick))
+	}
+	assert.Nil(t, recorder.Close())
+
+	paths := recorder.Paths()
+	assert.Len(t, paths, 3)
+	var lines []string
+	for _, path := range paths {
+		assert.True(t, strings.HasSuffix(path, ".jsonl.gz"))
+		lines = append(lines, readLines(t, path, true)...)
+	}
+	assert.Len(t, lines, 5)
+}
+
+func TestStream_Recorder(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+
+	mux.HandleFunc("/1.1/statuses/sample.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Transfer-Encoding", "chunked")
+		fmt.Fprint(w, `{"id": 1, "retweet_count": 0}`+"\r\n"+"\r\n"+`{"warning": {"code": "FALLING_BEHIND"}}`+"\r\n")
+	})
+
+	var recorded []string
+	recorder := recorderFunc(func(data []byte, receivedAt time.Time) error {
+		recorded = append(recorded, string(data))
+		assert.False(t, receivedAt.IsZero())
+		return nil
+	})
+	client := NewClient(httpClient, WithStreamRecorder(recorder))
+	stream, err := client.Streams.Sample(nil)
+	assert.Nil(t, err)
+	<-stream.Messages
+	<-stream.Messages
+	stream.Stop()
+	assert.Equal(t, []string{`{"id": 1, "retweet_count": 0}`, `{"warning": {"code": "FALLING_BEHIND"}}`}, recorded)
+}
+
+// recorderFunc is a StreamRecorder function for tests.
+type recorderFunc func(data []byte, receivedAt time.Time) error
+
+func (f recorderFunc) Record(data []byte, receivedAt time.Time) error {
+	return f(data, receivedAt)
+}
+
+func TestRecordedMessage(t *testing.T) {
+	assert.Equal(t, json.RawMessage(`{"id":1}`), recordedMessage([]byte(`{"id":1}`)))
+	assert.Equal(t, json.RawMessage(`"{\"id\":"`), recordedMessage([]byte(`{"id":`)))
+}
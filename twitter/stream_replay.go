@@ -0,0 +1,140 @@
+package twitter
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+var errReplayPaths = errors.New("twitter: ReplayStream requires recording paths")
+
+// ReplayParams are the parameters for ReplayStream.
+type ReplayParams struct {
+	// replay messages with their original timing, rather than as fast as
+	// possible
+	Paced bool
+	// multiplies the pace of paced replays, e.g. 2 replays twice as fast
+	// (default 1)
+	Speed float64
+	// retain the original JSON of messages (see WithRawJSON)
+	RawJSON bool
+}
+
+// ReplayStream returns a Stream which sends the messages recorded in the
+// given files (see FileRecorder), in order, then closes its Messages channel.
+// Gzipped files are detected automatically. Messages are decoded as they
+// would be from a live Stream. Errors reading the files are sent on the
+// Messages channel and end the replay.
+func ReplayStream(paths []string, params *ReplayParams) (*Stream, error) {
+	if len(paths) == 0 {
+		return nil, errReplayPaths
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			return nil, err
+		}
+	}
+	if params == nil {
+		params = &ReplayParams{}
+	}
+	speed := params.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	s := &Stream{
+		Messages: make(chan interface{}),
+		done:     make(chan struct{}),
+		group:    &sync.WaitGroup{},
+		rawJSON:  params.RawJSON,
+	}
+	s.group.Add(1)
+	go s.replay(paths, params.Paced, speed)
+	return s, nil
+}
+
+// replay sends the recorded messages from each file until the files are
+// exhausted, an error occurs, or the Stream is stopped.
+func (s *Stream) replay(paths []string, paced bool, speed float64) {
+	defer close(s.Messages)
+	defer s.group.Done()
+	var previous time.Time
+	for _, path := range paths {
+		err := readRecording(path, func(rec *recording) bool {
+			if paced && !previous.IsZero() {
+				if wait := rec.ReceivedAt.Sub(previous); wait > 0 {
+					sleepOrDone(time.Duration(float64(wait)/speed), s.done)
+				}
+			}
+			previous = rec.ReceivedAt
+			return s.send(s.decode(recordingData(rec.Message)))
+		})
+		if err != nil {
+			s.send(err)
+			return
+		}
+		if stopped(s.done) {
+			return
+		}
+	}
+}
+
+// send sends the message on the Messages channel unless the Stream is
+// stopped. Returns false if the Stream was stopped.
+func (s *Stream) send(message interface{}) bool {
+	select {
+	case s.Messages <- message:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// readRecording calls fn with each recording in the file until fn returns
+// false.
+func readRecording(path string, fn func(rec *recording) bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	var source io.Reader = reader
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		source = gzipReader
+	}
+	decoder := json.NewDecoder(source)
+	for {
+		rec := new(recording)
+		if err := decoder.Decode(rec); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if !fn(rec) {
+			return nil
+		}
+	}
+}
+
+// recordingData returns the recorded message data, unquoting messages which
+// were recorded as strings because they were not valid JSON.
+func recordingData(message json.RawMessage) []byte {
+	if bytes.HasPrefix(message, []byte(`"`)) {
+		var data string
+		if json.Unmarshal(message, &data) == nil {
+			return []byte(data)
+		}
+	}
+	return message
+}
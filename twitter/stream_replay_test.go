@@ -0,0 +1,96 @@
+package twitter
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeRecording records the messages 10ms apart and returns the paths of
+// the recording files.
+func writeRecording(t *testing.T, gzipped bool, messages ...string) []string {
+	recorder := NewFileRecorder(&FileRecorderParams{
+		Path:     filepath.Join(t.TempDir(), "stream.jsonl"),
+		MaxBytes: 100,
+		Gzip:     gzipped,
+	})
+	at := time.Date(2022, time.November, 4, 1, 42, 54, 0, time.UTC)
+	for i, message := range messages {
+		assert.Nil(t, recorder.Record([]byte(message), at.Add(time.Duration(i)*10*time.Millisecond)))
+	}
+	assert.Nil(t, recorder.Close())
+	return recorder.Paths()
+}
+
+func TestReplayStream(t *testing.T) {
+	for _, gzipped := range []bool{false, true} {
+		paths := writeRecording(t, gzipped,
+			`{"id": 1, "text": "a", "retweet_count": 0}`,
+			`{"warning": {"code": "FALLING_BEHIND", "percent_full": 60}}`,
+			`{"id": 2, "text": "b", "retweet_count": 0, "new_field": true}`,
+			`{"id": `,
+		)
+		assert.True(t, len(paths) > 1)
+
+		stream, err := ReplayStream(paths, &ReplayParams{RawJSON: true})
+		assert.Nil(t, err)
+		var messages []interface{}
+		for message := range stream.Messages {
+			messages = append(messages, message)
+		}
+		stream.Stop()
+		if assert.Len(t, messages, 4) {
+			assert.Equal(t, int64(1), messages[0].(*Tweet).ID)
+			assert.Equal(t, &StallWarning{Code: "FALLING_BEHIND", PercentFull: 60}, messages[1])
+			assert.Equal(t, `{"id":2,"text":"b","retweet_count":0,"new_field":true}`, string(messages[2].(*Tweet).Raw))
+			_, isErr := messages[3].(error)
+			assert.True(t, isErr)
+		}
+	}
+}
+
+func TestReplayStream_Paced(t *testing.T) {
+	paths := writeRecording(t, false, `{"id": 1, "retweet_count": 0}`, `{"id": 2, "retweet_count": 0}`, `{"id": 3, "retweet_count": 0}`)
+	stream, err := ReplayStream(paths, &ReplayParams{Paced: true, Speed: 0.5})
+	assert.Nil(t, err)
+	start := time.Now()
+	count := 0
+	for range stream.Messages {
+		count++
+	}
+	stream.Stop()
+	assert.Equal(t, 3, count)
+	// 20ms of recording at half speed
+	assert.True(t, time.Since(start) >= 40*time.Millisecond)
+}
+
+func TestReplayStream_Stop(t *testing.T) {
+	paths := writeRecording(t, false, `{"id": 1, "retweet_count": 0}`, `{"id": 2, "retweet_count": 0}`)
+	stream, err := ReplayStream(paths, nil)
+	assert.Nil(t, err)
+	<-stream.Messages
+	stream.Stop()
+	_, ok := <-stream.Messages
+	assert.False(t, ok)
+}
+
+func TestReplayStream_Errors(t *testing.T) {
+	_, err := ReplayStream(nil, nil)
+	assert.Equal(t, errReplayPaths, err)
+	_, err = ReplayStream([]string{filepath.Join(t.TempDir(), "missing.jsonl")}, nil)
+	assert.True(t, os.IsNotExist(err))
+
+	path := filepath.Join(t.TempDir(), "corrupt.jsonl")
+	assert.Nil(t, os.WriteFile(path, []byte(`{"received_at": "2022-11-04T01:42:54Z", "message": {"id": 1, "retweet_count": 0}}`+"\n"+`{"received_at`), 0644))
+	stream, err := ReplayStream([]string{path}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), (<-stream.Messages).(*Tweet).ID)
+	assert.Equal(t, io.ErrUnexpectedEOF, <-stream.Messages)
+	_, ok := <-stream.Messages
+	assert.False(t, ok)
+	stream.Stop()
+}
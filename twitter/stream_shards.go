@@ -0,0 +1,335 @@
+package twitter
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// Maximum number of predicates of each kind Twitter accepts on a single
+// Filter connection.
+// https://developer.twitter.com/en/docs/twitter-api/v1/tweets/filter-realtime/guides/basic-stream-parameters
+const (
+	filterMaxTrack     = 400
+	filterMaxFollow    = 5000
+	filterMaxLocations = 25
+)
+
+var (
+	errShardedStreamStopped = errors.New("twitter: sharded stream is stopped")
+	errFilterLocations      = errors.New("twitter: filter Locations must be groups of 4 coordinates")
+)
+
+// ShardedStream partitions a set of filter predicates which exceeds the
+// per-connection limits across multiple Filter streams, and sends the
+// messages received from all of them on a single Messages channel. Tweets
+// matched by more than one shard are sent only once.
+//
+// Unlike a Stream, the Messages channel is only closed once the client calls
+// Stop(), since shards may be replaced when predicates are updated.
+type ShardedStream struct {
+	Messages chan interface{}
+	services []*StreamService
+	params   StreamFilterParams
+	shards   []*filterShard
+	deduper  *Deduper
+	mu       sync.Mutex
+	done     chan struct{}
+	group    *sync.WaitGroup
+}
+
+// filterShard is the subset of predicates assigned to a single Filter
+// stream connection.
+type filterShard struct {
+	service   *StreamService
+	track     []string
+	follow    []string
+	locations []string
+	stream    *Stream
+}
+
+// ShardedFilter returns a ShardedStream which partitions the predicates in
+// the given params into as many Filter streams as needed to stay within the
+// Track, Follow and Locations limits of a single connection. Shards are
+// assigned round-robin to the receiver and any extra StreamServices (e.g.
+// from Clients authorized with other credentials).
+// https://dev.twitter.com/streaming/reference/post/statuses/filter
+func (srv *StreamService) ShardedFilter(params *StreamFilterParams, extra ...*StreamService) (*ShardedStream, error) {
+	if params == nil {
+		params = &StreamFilterParams{}
+	}
+	if len(params.Locations)%4 != 0 {
+		return nil, errFilterLocations
+	}
+	s := &ShardedStream{
+		Messages: make(chan interface{}),
+		services: append([]*StreamService{srv}, extra...),
+		params:   *params,
+		deduper:  NewDeduper(nil),
+		done:     make(chan struct{}),
+		group:    &sync.WaitGroup{},
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.assign(params)
+	for _, shard := range s.shards {
+		if err := s.start(shard); err != nil {
+			s.stopShards()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Update replaces the filter predicates and rebalances them across shards.
+// Existing predicate assignments are kept where possible so that only shards
+// whose predicates changed are reconnected. If a shard fails to connect, the
+// other shards are still started and the first error is returned. Shards
+// which failed are retried by the next Update.
+func (s *ShardedStream) Update(params *StreamFilterParams) error {
+	if params == nil {
+		params = &StreamFilterParams{}
+	}
+	if len(params.Locations)%4 != 0 {
+		return errFilterLocations
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stopped(s.done) {
+		return errShardedStreamStopped
+	}
+	// changes to non-predicate parameters apply to every shard
+	restartAll := !sameFilterOptions(&s.params, params)
+	s.params = *params
+	changed := s.assign(params)
+	var shards []*filterShard
+	for _, shard := range s.shards {
+		if shard.empty() {
+			if shard.stream != nil {
+				shard.stream.Stop()
+			}
+			continue
+		}
+		shards = append(shards, shard)
+	}
+	s.shards = shards
+	var firstErr error
+	for _, shard := range s.shards {
+		if !restartAll && !changed[shard] && shard.stream != nil {
+			continue
+		}
+		if shard.stream != nil {
+			shard.stream.Stop()
+			shard.stream = nil
+		}
+		if err := s.start(shard); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Shards returns the number of Filter connections currently in use.
+func (s *ShardedStream) Shards() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.shards)
+}
+
+// Stop stops every shard stream, closes the Messages channel, and blocks
+// until done.
+func (s *ShardedStream) Stop() {
+	s.mu.Lock()
+	close(s.done)
+	s.stopShards()
+	s.mu.Unlock()
+	// block until all forwarding goroutines stop
+	s.group.Wait()
+	close(s.Messages)
+}
+
+// stopShards stops the streams of all shards. Callers must hold the lock.
+func (s *ShardedStream) stopShards() {
+	for _, shard := range s.shards {
+		if shard.stream != nil {
+			shard.stream.Stop()
+		}
+	}
+}
+
+// start connects a Filter stream for the shard's predicates and forwards its
+// messages. Callers must hold the lock.
+func (s *ShardedStream) start(shard *filterShard) error {
+	params := s.params
+	params.Track = shard.track
+	params.Follow = shard.follow
+	params.Locations = shard.locations
+	stream, err := shard.service.Filter(&params)
+	if err != nil {
+		return err
+	}
+	shard.stream = stream
+	s.group.Add(1)
+	go s.forward(stream)
+	return nil
+}
+
+// forward sends messages from a shard stream to the Messages channel,
+// dropping Tweets which were already sent by another shard. Forwarding
+// continues until the shard stream's Messages channel is closed.
+func (s *ShardedStream) forward(stream *Stream) {
+	defer s.group.Done()
+	for message := range stream.Messages {
+		if s.deduper.Duplicate(message) {
+			continue
+		}
+		select {
+		case s.Messages <- message:
+		case <-s.done:
+			// keep draining so the shard stream can stop
+		}
+	}
+}
+
+// assign removes predicates which are no longer present from their shards
+// and assigns new predicates to shards with spare capacity, adding shards as
+// needed. Returns the set of shards whose predicates changed. Callers must
+// hold the lock.
+func (s *ShardedStream) assign(params *StreamFilterParams) map[*filterShard]bool {
+	changed := make(map[*filterShard]bool)
+	track := newStringSet(params.Track)
+	follow := newStringSet(params.Follow)
+	boxes := newStringSet(locationBoxes(params.Locations))
+
+	// remove predicates which are gone, and note which remain assigned
+	for _, shard := range s.shards {
+		var removed bool
+		shard.track, removed = retain(shard.track, track)
+		changed[shard] = changed[shard] || removed
+		shard.follow, removed = retain(shard.follow, follow)
+		changed[shard] = changed[shard] || removed
+		var kept []string
+		kept, removed = retain(locationBoxes(shard.locations), boxes)
+		shard.locations = joinLocationBoxes(kept)
+		changed[shard] = changed[shard] || removed
+	}
+
+	// add new predicates to the first shard with room for them
+	for _, term := range track.remaining() {
+		shard := s.shardWithRoom(func(f *filterShard) bool { return len(f.track) < filterMaxTrack })
+		shard.track = append(shard.track, term)
+		changed[shard] = true
+	}
+	for _, id := range follow.remaining() {
+		shard := s.shardWithRoom(func(f *filterShard) bool { return len(f.follow) < filterMaxFollow })
+		shard.follow = append(shard.follow, id)
+		changed[shard] = true
+	}
+	for _, box := range boxes.remaining() {
+		shard := s.shardWithRoom(func(f *filterShard) bool { return len(f.locations)/4 < filterMaxLocations })
+		shard.locations = append(shard.locations, strings.Split(box, ",")...)
+		changed[shard] = true
+	}
+	return changed
+}
+
+// shardWithRoom returns the first shard satisfying hasRoom, or appends a new
+// shard assigned to the next StreamService round-robin.
+func (s *ShardedStream) shardWithRoom(hasRoom func(*filterShard) bool) *filterShard {
+	for _, shard := range s.shards {
+		if hasRoom(shard) {
+			return shard
+		}
+	}
+	shard := &filterShard{service: s.services[len(s.shards)%len(s.services)]}
+	s.shards = append(s.shards, shard)
+	return shard
+}
+
+// empty returns true if the shard has no predicates left.
+func (f *filterShard) empty() bool {
+	return len(f.track) == 0 && len(f.follow) == 0 && len(f.locations) == 0
+}
+
+// sameFilterOptions returns true if the non-predicate parameters are equal.
+func sameFilterOptions(a, b *StreamFilterParams) bool {
+	if a.FilterLevel != b.FilterLevel || strings.Join(a.Language, ",") != strings.Join(b.Language, ",") {
+		return false
+	}
+	if (a.StallWarnings == nil) != (b.StallWarnings == nil) {
+		return false
+	}
+	return a.StallWarnings == nil || *a.StallWarnings == *b.StallWarnings
+}
+
+// locationBoxes groups a flat list of Locations coordinates into comma
+// separated bounding boxes of 4 coordinates each.
+func locationBoxes(locations []string) []string {
+	var boxes []string
+	for i := 0; i+4 <= len(locations); i += 4 {
+		boxes = append(boxes, strings.Join(locations[i:i+4], ","))
+	}
+	return boxes
+}
+
+// joinLocationBoxes flattens comma separated bounding boxes into a list of
+// Locations coordinates.
+func joinLocationBoxes(boxes []string) []string {
+	var locations []string
+	for _, box := range boxes {
+		locations = append(locations, strings.Split(box, ",")...)
+	}
+	return locations
+}
+
+// retain returns the values which are members of the set, marking them as
+// assigned, and whether any values were removed.
+func retain(values []string, set *stringSet) ([]string, bool) {
+	var kept []string
+	for _, value := range values {
+		if set.claim(value) {
+			kept = append(kept, value)
+		}
+	}
+	return kept, len(kept) != len(values)
+}
+
+// stringSet is an ordered set of strings which tracks which members have
+// been claimed by a shard.
+type stringSet struct {
+	values  []string
+	claimed map[string]bool
+}
+
+func newStringSet(values []string) *stringSet {
+	set := &stringSet{claimed: make(map[string]bool)}
+	for _, value := range values {
+		if _, ok := set.claimed[value]; !ok {
+			set.claimed[value] = false
+			set.values = append(set.values, value)
+		}
+	}
+	return set
+}
+
+// claim marks a member as claimed. Returns false if the value is not a
+// member or was already claimed.
+func (s *stringSet) claim(value string) bool {
+	claimed, ok := s.claimed[value]
+	if !ok || claimed {
+		return false
+	}
+	s.claimed[value] = true
+	return true
+}
+
+// remaining returns the unclaimed members in insertion order.
+func (s *stringSet) remaining() []string {
+	var values []string
+	for _, value := range s.values {
+		if !s.claimed[value] {
+			values = append(values, value)
+		}
+	}
+	return values
+}
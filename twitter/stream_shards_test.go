@@ -0,0 +1,169 @@
+package twitter
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dghubble/sling"
+	"github.com/stretchr/testify/assert"
+)
+
+func testTrackTerms(n int) []string {
+	terms := make([]string, n)
+	for i := range terms {
+		terms[i] = fmt.Sprintf("term%d", i)
+	}
+	return terms
+}
+
+func TestShardedStream_assign(t *testing.T) {
+	serviceA, serviceB := &StreamService{}, &StreamService{}
+	s := &ShardedStream{services: []*StreamService{serviceA, serviceB}}
+	params := &StreamFilterParams{
+		Track:     testTrackTerms(900),
+		Follow:    []string{"1", "2"},
+		Locations: []string{"-122.75", "36.8", "-121.75", "37.8"},
+	}
+	s.assign(params)
+	if assert.Len(t, s.shards, 3) {
+		assert.Len(t, s.shards[0].track, 400)
+		assert.Len(t, s.shards[1].track, 400)
+		assert.Len(t, s.shards[2].track, 100)
+		assert.Equal(t, []string{"1", "2"}, s.shards[0].follow)
+		assert.Equal(t, params.Locations, s.shards[0].locations)
+		// shards are assigned to services round-robin
+		assert.Equal(t, serviceA, s.shards[0].service)
+		assert.Equal(t, serviceB, s.shards[1].service)
+		assert.Equal(t, serviceA, s.shards[2].service)
+	}
+}
+
+func TestShardedStream_assignRebalance(t *testing.T) {
+	s := &ShardedStream{services: []*StreamService{{}}}
+	s.assign(&StreamFilterParams{Track: testTrackTerms(500)})
+	first, second := s.shards[0], s.shards[1]
+
+	// remove a term from the first shard and add a new term, which fills
+	// the freed capacity without touching the second shard
+	track := testTrackTerms(500)[1:]
+	track = append(track, "gopher")
+	changed := s.assign(&StreamFilterParams{Track: track})
+	assert.True(t, changed[first])
+	assert.False(t, changed[second])
+	assert.Len(t, first.track, 400)
+	assert.Equal(t, "gopher", first.track[399])
+	assert.Len(t, second.track, 100)
+
+	// remove every term of the second shard
+	changed = s.assign(&StreamFilterParams{Track: append([]string{}, first.track...)})
+	assert.False(t, changed[first])
+	assert.True(t, changed[second])
+	assert.True(t, second.empty())
+}
+
+func TestSameFilterOptions(t *testing.T) {
+	a := &StreamFilterParams{Language: []string{"en"}, Track: []string{"a"}}
+	b := &StreamFilterParams{Language: []string{"en"}, Track: []string{"b"}}
+	assert.True(t, sameFilterOptions(a, b))
+	b.StallWarnings = Bool(true)
+	assert.False(t, sameFilterOptions(a, b))
+	a.StallWarnings = Bool(true)
+	assert.True(t, sameFilterOptions(a, b))
+	b.FilterLevel = "low"
+	assert.False(t, sameFilterOptions(a, b))
+}
+
+func TestStream_ShardedFilter(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+
+	var mu sync.Mutex
+	connected := make(map[string]bool)
+	mux.HandleFunc("/1.1/statuses/filter.json", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, "POST", r)
+		track := r.URL.Query().Get("track")
+		mu.Lock()
+		defer mu.Unlock()
+		if connected[track] {
+			// Only allow first request of each shard
+			http.Error(w, "Stream API not available!", 500)
+			return
+		}
+		connected[track] = true
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Transfer-Encoding", "chunked")
+		// both shards match Tweet 2
+		if strings.HasPrefix(track, "term0,") {
+			fmt.Fprintf(w, `{"id": 1, "retweet_count": 0}`+"\r\n"+`{"id": 2, "retweet_count": 0}`+"\r\n")
+		} else {
+			fmt.Fprintf(w, `{"id": 2, "retweet_count": 0}`+"\r\n"+`{"id": 3, "retweet_count": 0}`+"\r\n")
+		}
+	})
+
+	client := NewClient(httpClient)
+	stream, err := client.Streams.ShardedFilter(&StreamFilterParams{
+		Track: testTrackTerms(401),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stream.Shards())
+
+	ids := make(map[int64]int)
+	for len(ids) < 3 {
+		select {
+		case message := <-stream.Messages:
+			ids[message.(*Tweet).ID]++
+		case <-time.After(defaultTestTimeout):
+			t.Fatalf("expected 3 distinct Tweets, got %v", ids)
+		}
+	}
+	stream.Stop()
+	for message := range stream.Messages {
+		ids[message.(*Tweet).ID]++
+	}
+	assert.Equal(t, map[int64]int{1: 1, 2: 1, 3: 1}, ids)
+}
+
+func TestStream_ShardedFilterLocations(t *testing.T) {
+	client := NewClient(http.DefaultClient)
+	_, err := client.Streams.ShardedFilter(&StreamFilterParams{Locations: []string{"1", "2"}})
+	assert.Equal(t, errFilterLocations, err)
+}
+
+func TestShardedStream_UpdateStartError(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+	mux.HandleFunc("/1.1/statuses/filter.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Transfer-Encoding", "chunked")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+
+	client := NewClient(httpClient)
+	// the second shard's service can't build requests
+	broken := *client.Streams
+	broken.public = sling.New().Base("%zz")
+	stream, err := client.Streams.ShardedFilter(&StreamFilterParams{Track: testTrackTerms(10)}, &broken, client.Streams)
+	assert.NoError(t, err)
+	defer stream.Stop()
+
+	params := &StreamFilterParams{Track: testTrackTerms(3 * filterMaxTrack)}
+	err = stream.Update(params)
+	assert.Error(t, err)
+	assert.Equal(t, 3, stream.Shards())
+	// shards after the failed shard are still started
+	assert.NotNil(t, stream.shards[0].stream)
+	assert.Nil(t, stream.shards[1].stream)
+	assert.NotNil(t, stream.shards[2].stream)
+
+	// the failed shard is retried by the next Update
+	started := stream.shards[2].stream
+	broken.public = client.Streams.public
+	assert.NoError(t, stream.Update(params))
+	assert.NotNil(t, stream.shards[1].stream)
+	assert.Equal(t, started, stream.shards[2].stream)
+}
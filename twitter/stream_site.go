@@ -0,0 +1,96 @@
+package twitter
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// maximum number of users per Site Stream control request
+const siteControlMaxUsers = 100
+
+var errSiteControlURI = errors.New("twitter: Site Stream control URI is required")
+
+// SiteStreamInfo describes a Site Stream connection.
+type SiteStreamInfo struct {
+	Users                     []SiteStreamUser `json:"users"`
+	Delimited                 string           `json:"delimited"`
+	IncludeFollowingsActivity bool             `json:"include_followings_activity"`
+	IncludeUserChanges        bool             `json:"include_user_changes"`
+	Replies                   string           `json:"replies"`
+	With                      string           `json:"with"`
+}
+
+// SiteStreamUser is a user followed by a Site Stream.
+type SiteStreamUser struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	DM   bool   `json:"dm"`
+}
+
+type siteStreamInfoNotice struct {
+	Info *SiteStreamInfo `json:"info"`
+}
+
+type siteControlParams struct {
+	UserID []int64 `url:"user_id,comma"`
+}
+
+// SiteAddUsers adds users to the Site Stream with the given control URI (see
+// StreamControl). Users are added with requests of at most 100 users each.
+// Requires special permission to access.
+// https://developer.twitter.com/en/docs/twitter-api/v1/tweets/filter-realtime/guides/site-streams
+func (srv *StreamService) SiteAddUsers(controlURI string, userIDs []int64) (*http.Response, error) {
+	return srv.siteControlUsers(controlURI, "add_user.json", userIDs)
+}
+
+// SiteRemoveUsers removes users from the Site Stream with the given control
+// URI (see StreamControl). Users are removed with requests of at most 100
+// users each.
+// Requires special permission to access.
+// https://developer.twitter.com/en/docs/twitter-api/v1/tweets/filter-realtime/guides/site-streams
+func (srv *StreamService) SiteRemoveUsers(controlURI string, userIDs []int64) (*http.Response, error) {
+	return srv.siteControlUsers(controlURI, "remove_user.json", userIDs)
+}
+
+// SiteInfo returns information about the Site Stream with the given control
+// URI (see StreamControl), such as the users it follows.
+// Requires special permission to access.
+// https://developer.twitter.com/en/docs/twitter-api/v1/tweets/filter-realtime/guides/site-streams
+func (srv *StreamService) SiteInfo(controlURI string) (*SiteStreamInfo, *http.Response, error) {
+	if controlURI == "" {
+		return nil, nil, errSiteControlURI
+	}
+	notice := new(siteStreamInfoNotice)
+	apiError := new(APIError)
+	resp, err := srv.site.New().Get(siteControlPath(controlURI, "info.json")).Receive(notice, apiError)
+	return notice.Info, resp, relevantError(err, *apiError)
+}
+
+// siteControlUsers sends control requests for the given users in batches.
+func (srv *StreamService) siteControlUsers(controlURI, endpoint string, userIDs []int64) (*http.Response, error) {
+	if controlURI == "" {
+		return nil, errSiteControlURI
+	}
+	var resp *http.Response
+	for start := 0; start < len(userIDs); start += siteControlMaxUsers {
+		end := start + siteControlMaxUsers
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+		params := &siteControlParams{UserID: userIDs[start:end]}
+		apiError := new(APIError)
+		var err error
+		resp, err = srv.site.New().Post(siteControlPath(controlURI, endpoint)).QueryStruct(params).Receive(nil, apiError)
+		if err := relevantError(err, *apiError); err != nil {
+			return resp, err
+		}
+	}
+	return resp, nil
+}
+
+// siteControlPath returns the path of a control endpoint of the Site Stream
+// with the given control URI (e.g. "/1.1/site/c/01_225167_334389048B872A533002B34D73F8C29FD09EFC50").
+func siteControlPath(controlURI, endpoint string) string {
+	return strings.TrimSuffix(controlURI, "/") + "/" + endpoint
+}
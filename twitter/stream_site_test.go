@@ -0,0 +1,98 @@
+package twitter
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testControlURI = "/1.1/site/c/01_225167_334389048B872A533002B34D73F8C29FD09EFC50"
+
+func TestStreamService_SiteAddUsers(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+
+	var batches []string
+	mux.HandleFunc(testControlURI+"/add_user.json", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, "POST", r)
+		batches = append(batches, r.URL.Query().Get("user_id"))
+	})
+
+	userIDs := make([]int64, 150)
+	for i := range userIDs {
+		userIDs[i] = int64(i + 1)
+	}
+	client := NewClient(httpClient)
+	resp, err := client.Streams.SiteAddUsers(testControlURI, userIDs)
+	assert.Nil(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	if assert.Len(t, batches, 2) {
+		assert.Len(t, strings.Split(batches[0], ","), 100)
+		assert.True(t, strings.HasPrefix(batches[0], "1,2,3,"))
+		assert.Len(t, strings.Split(batches[1], ","), 50)
+		assert.True(t, strings.HasSuffix(batches[1], ",150"))
+	}
+}
+
+func TestStreamService_SiteRemoveUsers(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+
+	mux.HandleFunc(testControlURI+"/remove_user.json", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, "POST", r)
+		assertQuery(t, map[string]string{"user_id": "1888,2000"}, r)
+	})
+
+	client := NewClient(httpClient)
+	_, err := client.Streams.SiteRemoveUsers(testControlURI, []int64{1888, 2000})
+	assert.Nil(t, err)
+}
+
+func TestStreamService_SiteRemoveUsers_APIError(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+
+	mux.HandleFunc(testControlURI+"/remove_user.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(404)
+		fmt.Fprintf(w, `{"errors": [{"message": "Sorry, that page does not exist", "code": 34}]}`)
+	})
+
+	client := NewClient(httpClient)
+	_, err := client.Streams.SiteRemoveUsers(testControlURI, []int64{1888})
+	assert.Equal(t, APIError{Errors: []ErrorDetail{{Message: "Sorry, that page does not exist", Code: 34}}}, err)
+}
+
+func TestStreamService_SiteInfo(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+
+	mux.HandleFunc(testControlURI+"/info.json", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, "GET", r)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"info": {"users": [{"id": 1888, "name": "dghubble", "dm": true}], "delimited": "none", "include_followings_activity": false, "include_user_changes": true, "replies": "none", "with": "user"}}`)
+	})
+
+	client := NewClient(httpClient)
+	info, _, err := client.Streams.SiteInfo(testControlURI)
+	expected := &SiteStreamInfo{
+		Users:              []SiteStreamUser{{ID: 1888, Name: "dghubble", DM: true}},
+		Delimited:          "none",
+		IncludeUserChanges: true,
+		Replies:            "none",
+		With:               "user",
+	}
+	assert.Nil(t, err)
+	assert.Equal(t, expected, info)
+}
+
+func TestStreamService_SiteControlURIRequired(t *testing.T) {
+	client := NewClient(http.DefaultClient)
+	_, err := client.Streams.SiteAddUsers("", []int64{1})
+	assert.Equal(t, errSiteControlURI, err)
+	_, _, err = client.Streams.SiteInfo("")
+	assert.Equal(t, errSiteControlURI, err)
+}
@@ -20,20 +20,27 @@ const (
 
 // StreamService provides methods for accessing the Twitter Streaming API.
 type StreamService struct {
-	client *http.Client
-	public *sling.Sling
-	user   *sling.Sling
-	site   *sling.Sling
+	client  *http.Client
+	public  *sling.Sling
+	user    *sling.Sling
+	site    *sling.Sling
+	rest    *sling.Sling
+	doer    sling.Doer
+	options *clientOptions
 }
 
-// newStreamService returns a new StreamService.
-func newStreamService(client *http.Client, sling *sling.Sling) *StreamService {
+// newStreamService returns a new StreamService. REST requests, such as
+// backfills, are sent with the given Doer.
+func newStreamService(client *http.Client, doer sling.Doer, sling *sling.Sling, options *clientOptions) *StreamService {
 	sling.Set("User-Agent", userAgent)
 	return &StreamService{
-		client: client,
-		public: sling.New().Base(publicStream).Path("statuses/"),
-		user:   sling.New().Base(userStream),
-		site:   sling.New().Base(siteStream),
+		client:  client,
+		options: options,
+		public:  sling.New().Base(publicStream).Path("statuses/"),
+		user:    sling.New().Base(userStream),
+		site:    sling.New().Base(siteStream),
+		rest:    sling.New(),
+		doer:    doer,
 	}
 }
 
@@ -45,6 +52,8 @@ type StreamFilterParams struct {
 	Locations     []string `url:"locations,omitempty,comma"`
 	StallWarnings *bool    `url:"stall_warnings,omitempty"`
 	Track         []string `url:"track,omitempty,comma"`
+	// Backfill Tweets missed while reconnecting (optional)
+	Backfill *StreamBackfillParams `url:"-"`
 }
 
 // Filter returns messages that match one or more filter predicates.
@@ -54,7 +63,13 @@ func (srv *StreamService) Filter(params *StreamFilterParams) (*Stream, error) {
 	if err != nil {
 		return nil, err
 	}
-	return newStream(srv.client, req), nil
+	var backfill *backfiller
+	if params != nil && params.Backfill != nil {
+		if backfill, err = newBackfiller(srv, params); err != nil {
+			return nil, err
+		}
+	}
+	return newStream(srv.client, req, backfill, srv.options), nil
 }
 
 // StreamSampleParams are the parameters for StreamService.Sample.
@@ -70,7 +85,7 @@ func (srv *StreamService) Sample(params *StreamSampleParams) (*Stream, error) {
 	if err != nil {
 		return nil, err
 	}
-	return newStream(srv.client, req), nil
+	return newStream(srv.client, req, nil, srv.options), nil
 }
 
 // StreamUserParams are the parameters for StreamService.User.
@@ -91,7 +106,7 @@ func (srv *StreamService) User(params *StreamUserParams) (*Stream, error) {
 	if err != nil {
 		return nil, err
 	}
-	return newStream(srv.client, req), nil
+	return newStream(srv.client, req, nil, srv.options), nil
 }
 
 // StreamSiteParams are the parameters for StreamService.Site.
@@ -112,7 +127,7 @@ func (srv *StreamService) Site(params *StreamSiteParams) (*Stream, error) {
 	if err != nil {
 		return nil, err
 	}
-	return newStream(srv.client, req), nil
+	return newStream(srv.client, req, nil, srv.options), nil
 }
 
 // StreamFirehoseParams are the parameters for StreamService.Firehose.
@@ -131,7 +146,7 @@ func (srv *StreamService) Firehose(params *StreamFirehoseParams) (*Stream, error
 	if err != nil {
 		return nil, err
 	}
-	return newStream(srv.client, req), nil
+	return newStream(srv.client, req, nil, srv.options), nil
 }
 
 // Stream maintains a connection to the Twitter Streaming API, receives
@@ -146,21 +161,33 @@ type Stream struct {
 	Messages chan interface{}
 	done     chan struct{}
 	group    *sync.WaitGroup
+	backfill *backfiller
+	rawJSON  bool
+	recorder StreamRecorder
+	mu       sync.Mutex
 	body     io.Closer
+	lastID   int64
+	lastAt   time.Time
 }
 
 // newStream creates a Stream and starts a goroutine to retry connecting and
 // receive from a stream response. The goroutine may stop due to retry errors
-// or be stopped by calling Stop() on the stream.
-func newStream(client *http.Client, req *http.Request) *Stream {
+// or be stopped by calling Stop() on the stream. If backfill is non-nil,
+// Tweets missed while reconnecting are fetched and sent as BackfilledTweets.
+// Client options may retain raw JSON, record messages, or set backoff policies.
+func newStream(client *http.Client, req *http.Request, backfill *backfiller, options *clientOptions) *Stream {
 	s := &Stream{
 		client:   client,
+		rawJSON:  options.rawJSON,
+		recorder: options.recorder,
 		Messages: make(chan interface{}),
 		done:     make(chan struct{}),
 		group:    &sync.WaitGroup{},
+		backfill: backfill,
 	}
+	expBackOff, aggExpBackOff := options.streamBackOffs()
 	s.group.Add(1)
-	go s.retry(req, newExponentialBackOff(), newAggressiveExponentialBackOff())
+	go s.retry(req, expBackOff, aggExpBackOff)
 	return s
 }
 
@@ -171,9 +198,11 @@ func (s *Stream) Stop() {
 	// Scanner does not have a Stop() or take a done channel, so for low volume
 	// streams Scan() blocks until the next keep-alive. Close the resp.Body to
 	// escape and stop the stream in a timely fashion.
+	s.mu.Lock()
 	if s.body != nil {
 		s.body.Close()
 	}
+	s.mu.Unlock()
 	// block until the retry goroutine stops
 	s.group.Wait()
 }
@@ -197,9 +226,13 @@ func (s *Stream) retry(req *http.Request, expBackOff backoff.BackOff, aggExpBack
 		}
 		// when err is nil, resp contains a non-nil Body which must be closed
 		defer resp.Body.Close()
+		s.mu.Lock()
 		s.body = resp.Body
+		s.mu.Unlock()
 		switch resp.StatusCode {
 		case 200:
+			// fetch Tweets missed since the previous connection
+			s.backfillSince()
 			// receive stream response Body, handles closing
 			s.receive(resp.Body)
 			expBackOff.Reset()
@@ -238,9 +271,17 @@ func (s *Stream) receive(body io.Reader) {
 			// empty keep-alive
 			continue
 		}
+		if s.recorder != nil {
+			// recording errors do not interrupt the stream
+			s.recorder.Record(data, time.Now())
+		}
+		message := s.decode(data)
+		if tweet, ok := message.(*Tweet); ok {
+			s.seen(tweet.ID)
+		}
 		select {
 		// send messages, data, or errors
-		case s.Messages <- getMessage(data):
+		case s.Messages <- message:
 			continue
 		// allow client to Stop(), even if not receiving
 		case <-s.done:
@@ -249,9 +290,38 @@ func (s *Stream) receive(body io.Reader) {
 	}
 }
 
+// decode returns the message decoded from the data.
+func (s *Stream) decode(data []byte) interface{} {
+	message := getMessage(data)
+	if s.rawJSON {
+		// data is reused by the reader, so retain a copy
+		attachMessageRawJSON(message, append([]byte(nil), data...))
+	}
+	return message
+}
+
+// LastTweet returns the ID of the most recent Tweet received by the stream
+// and the time it was received. Returns a zero ID if no Tweets have been
+// received.
+func (s *Stream) LastTweet() (int64, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastID, s.lastAt
+}
+
+// seen records the given Tweet ID as received, if it is the most recent.
+func (s *Stream) seen(id int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id > s.lastID {
+		s.lastID = id
+		s.lastAt = time.Now()
+	}
+}
+
 // getMessage unmarshals the token and returns a message struct, if the type
-// can be determined. Otherwise, returns the token unmarshalled into a data
-// map[string]interface{} or the unmarshal error.
+// can be determined. Otherwise, returns an UnknownMessage with a copy of the
+// token or the unmarshal error.
 func getMessage(token []byte) interface{} {
 	var data map[string]interface{}
 	// unmarshal JSON encoded token into a map for
@@ -264,10 +334,18 @@ func getMessage(token []byte) interface{} {
 
 // decodeMessage determines the message type from known data keys, allocates
 // at most one message struct, and JSON decodes the token into the message.
-// Returns the message struct or the data map if the message type could not be
-// determined.
+// Returns the message struct or an UnknownMessage if the message type could
+// not be determined.
 func decodeMessage(token []byte, data map[string]interface{}) interface{} {
-	if hasPath(data, "retweet_count") {
+	if hasPath(data, "for_user") {
+		envelope := new(siteStreamEnvelope)
+		json.Unmarshal(token, envelope)
+		return &SiteStreamMessage{ForUser: envelope.ForUser, Message: getMessage(envelope.Message)}
+	} else if hasPath(data, "control") {
+		notice := new(streamControlNotice)
+		json.Unmarshal(token, notice)
+		return notice.Control
+	} else if hasPath(data, "retweet_count") {
 		tweet := new(Tweet)
 		json.Unmarshal(token, tweet)
 		return tweet
@@ -312,8 +390,8 @@ func decodeMessage(token []byte, data map[string]interface{}) interface{} {
 		json.Unmarshal(token, event)
 		return event
 	}
-	// message type unknown, return the data map[string]interface{}
-	return data
+	// message type unknown, return the raw message
+	return &UnknownMessage{Raw: append(json.RawMessage(nil), token...)}
 }
 
 // hasPath returns true if the map contains the given key, false otherwise.
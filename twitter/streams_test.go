@@ -1,11 +1,13 @@
 package twitter
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -80,12 +82,69 @@ func TestStream_Event(t *testing.T) {
 	msgJSON := []byte(`{"event": "block", "target": {"name": "XKCD Comic", "favourites_count": 2}, "source": {"name": "XKCD Comic2", "favourites_count": 3}, "created_at": "Sat Sep 4 16:10:54 +0000 2010"}`)
 	msg := getMessage(msgJSON)
 	assert.IsType(t, &Event{}, msg)
+	event := msg.(*Event)
+	assert.Equal(t, EventBlock, event.Event)
+	assert.Nil(t, event.TargetObject)
+}
+
+func TestStream_EventTargetObject(t *testing.T) {
+	msgJSON := []byte(`{"event": "favorite", "created_at": "Sat Sep 04 16:10:54 +0000 2010", "source": {"id": 2}, "target": {"id": 3}, "target_object": {"id": 4, "text": "gophers", "retweet_count": 0}}`)
+	event := getMessage(msgJSON).(*Event)
+	assert.Equal(t, EventFavorite, event.Event)
+	assert.Equal(t, &Tweet{ID: 4, Text: "gophers"}, event.TargetTweet())
+	assert.Nil(t, event.TargetList())
+	createdAt, err := event.CreatedAtTime()
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2010, time.September, 4, 16, 10, 54, 0, time.UTC), createdAt.UTC())
+
+	msgJSON = []byte(`{"event": "list_member_added", "source": {"id": 2}, "target": {"id": 3}, "target_object": {"id": 5, "slug": "gophers", "name": "Gophers"}}`)
+	event = getMessage(msgJSON).(*Event)
+	assert.Equal(t, EventListMemberAdded, event.Event)
+	assert.Equal(t, &List{ID: 5, Slug: "gophers", Name: "Gophers"}, event.TargetList())
+	assert.Nil(t, event.TargetTweet())
+	assert.Nil(t, event.TargetObject)
+	assert.JSONEq(t, `{"id": 5, "slug": "gophers", "name": "Gophers"}`, string(event.RawTargetObject))
+}
+
+func TestStream_EventUnknownTargetObject(t *testing.T) {
+	// unknown kinds keep their target object
+	msgJSON := []byte(`{"event": "tweet_pinned", "source": {"id": 2}, "target_object": {"id": 4, "text": "gophers"}}`)
+	event := getMessage(msgJSON).(*Event)
+	assert.Equal(t, EventKind("tweet_pinned"), event.Event)
+	var tweet *Tweet = event.TargetObject
+	assert.Equal(t, &Tweet{ID: 4, Text: "gophers"}, tweet)
+	assert.Nil(t, event.TargetList())
+	assert.JSONEq(t, `{"id": 4, "text": "gophers"}`, string(event.RawTargetObject))
+
+	// including target objects which aren't Tweets
+	msgJSON = []byte(`{"event": "space_started", "target_object": {"id": "1", "state": "live"}}`)
+	event = getMessage(msgJSON).(*Event)
+	assert.Nil(t, event.TargetObject)
+	assert.JSONEq(t, `{"id": "1", "state": "live"}`, string(event.RawTargetObject))
+}
+
+func TestStream_SiteStreamMessage(t *testing.T) {
+	msgJSON := []byte(`{"for_user": 1888, "message": {"id": 4, "text": "gophers", "retweet_count": 0}}`)
+	msg := getMessage(msgJSON)
+	expected := &SiteStreamMessage{ForUser: 1888, Message: &Tweet{ID: 4, Text: "gophers"}}
+	assert.Equal(t, expected, msg)
+
+	msgJSON = []byte(`{"for_user": 1888, "message": {"friends": [1, 2]}}`)
+	msg = getMessage(msgJSON)
+	expected = &SiteStreamMessage{ForUser: 1888, Message: &FriendsList{Friends: []int64{1, 2}}}
+	assert.Equal(t, expected, msg)
+}
+
+func TestStream_StreamControl(t *testing.T) {
+	msgJSON := []byte(`{"control": {"control_uri": "/1.1/site/c/01_225167_334389048B872A533002B34D73F8C29FD09EFC50"}}`)
+	msg := getMessage(msgJSON)
+	assert.Equal(t, &StreamControl{ControlURI: "/1.1/site/c/01_225167_334389048B872A533002B34D73F8C29FD09EFC50"}, msg)
 }
 
 func TestStream_Unknown(t *testing.T) {
 	msgJSON := []byte(`{"unknown_data": {"new_twitter_type":"unexpected"}}`)
 	msg := getMessage(msgJSON)
-	assert.IsType(t, map[string]interface{}{}, msg)
+	assert.Equal(t, &UnknownMessage{Raw: json.RawMessage(msgJSON)}, msg)
 }
 
 func TestStream_Filter(t *testing.T) {
@@ -0,0 +1,213 @@
+package twitter
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/dghubble/go-twitter/twitter/twittertext"
+)
+
+// ThreadParams are the parameters for StatusService.Thread.
+type ThreadParams struct {
+	// append " i/n" to each part
+	Numbered bool
+	// media IDs to attach to each part, by part index
+	MediaIds [][]int64
+	// Tweet the first part replies to, if any
+	InReplyToStatusID int64
+}
+
+// ThreadError is returned when posting a thread fails part way. Posted
+// holds the Tweets posted before the failure, so the thread can be resumed
+// (see StatusService.ResumeThread) or rolled back (see
+// StatusService.RollbackThread).
+type ThreadError struct {
+	// parts of the thread
+	Parts []string
+	// Tweets of the parts which were posted, in order
+	Posted []Tweet
+	// error posting the next part
+	Err    error
+	params ThreadParams
+}
+
+func (e *ThreadError) Error() string {
+	return fmt.Sprintf("twitter: thread posted %d of %d parts: %v", len(e.Posted), len(e.Parts), e.Err)
+}
+
+// Unwrap returns the error posting the next part.
+func (e *ThreadError) Unwrap() error {
+	return e.Err
+}
+
+// Remaining returns the parts which were not posted.
+func (e *ThreadError) Remaining() []string {
+	return e.Parts[len(e.Posted):]
+}
+
+// SplitThread splits text into parts which each fit in a Tweet, breaking
+// at paragraph, sentence, or word boundaries where possible, never inside
+// emoji sequences, and never inside URLs unless a URL fills a part.
+// Numbered parts end with " i/n", within the limit.
+func SplitThread(text string, numbered bool) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	if !numbered {
+		return splitText(text, 0)
+	}
+	// reserve room for numbers as wide as the number of parts
+	for digits := 1; ; digits++ {
+		widest := strings.Repeat("9", digits)
+		parts := splitText(text, twittertext.WeightedLength(" "+widest+"/"+widest))
+		if len(strconv.Itoa(len(parts))) <= digits {
+			for i := range parts {
+				parts[i] += fmt.Sprintf(" %d/%d", i+1, len(parts))
+			}
+			return parts
+		}
+	}
+}
+
+// splitText splits the text into parts whose weighted length leaves room
+// for the reserved length.
+func splitText(text string, reserved int) []string {
+	limit := twittertext.MaxWeightedLength - reserved
+	var parts []string
+	runes := []rune(text)
+	for len(runes) > 0 {
+		if twittertext.WeightedLength(string(runes)) <= limit {
+			parts = append(parts, string(runes))
+			break
+		}
+		cut := splitPoint(runes, fitting(runes, limit))
+		if part := strings.TrimRightFunc(string(runes[:cut]), unicode.IsSpace); part != "" {
+			parts = append(parts, part)
+		}
+		runes = []rune(strings.TrimLeftFunc(string(runes[cut:]), unicode.IsSpace))
+	}
+	return parts
+}
+
+// fitting returns the number of leading runes whose weighted length is
+// within the limit, at least 1. The count never ends inside a URL or emoji
+// sequence, since it's the valid range of the text after padding which
+// weighs as much as the reserved length. The valid range also ends before
+// invalid characters, which can't be posted anyway.
+func fitting(runes []rune, limit int) int {
+	padding := twittertext.MaxWeightedLength - limit
+	result := twittertext.Parse(strings.Repeat(" ", padding) + string(runes))
+	if n := result.ValidRange[1] - padding; n > 0 {
+		return n
+	}
+	return 1
+}
+
+// splitPoint returns where to split the runes at or before index n, which
+// fitting keeps outside URLs and emoji sequences: after the last paragraph
+// in the second half, else after the last line or sentence in the second
+// half, else at the last space, else at n.
+func splitPoint(runes []rune, n int) int {
+	boundaries := []func(i int) bool{
+		// paragraphs
+		func(i int) bool {
+			return runes[i] == '\n' && runes[i-1] == '\n'
+		},
+		// lines and sentences
+		func(i int) bool {
+			return runes[i] == '\n' ||
+				(unicode.IsSpace(runes[i]) && strings.ContainsRune(".!?", runes[i-1])) ||
+				strings.ContainsRune("。！？", runes[i-1])
+		},
+	}
+	for _, boundary := range boundaries {
+		for i := n; i > n/2; i-- {
+			if boundary(i) {
+				return i
+			}
+		}
+	}
+	for i := n; i > 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			return i
+		}
+	}
+	return n
+}
+
+// Thread posts the text as a thread, split into parts with SplitThread.
+// Each part replies to the previous one. If posting a part fails, the
+// error is a *ThreadError with the Tweets posted so far. Returns the posted
+// Tweets and the response of the last request. Empty text returns
+// twittertext.ErrEmpty without making a request.
+// Requires a user auth context.
+func (s *StatusService) Thread(text string, params *ThreadParams) ([]Tweet, *http.Response, error) {
+	if params == nil {
+		params = &ThreadParams{}
+	}
+	parts := SplitThread(text, params.Numbered)
+	if len(parts) == 0 {
+		return nil, nil, twittertext.ErrEmpty
+	}
+	return s.postThread(&ThreadError{
+		Parts:  parts,
+		params: *params,
+	})
+}
+
+// ResumeThread posts the remaining parts of a thread which failed part way,
+// replying to the last posted Tweet. Returns all of the thread's Tweets.
+// Requires a user auth context.
+func (s *StatusService) ResumeThread(threadErr *ThreadError) ([]Tweet, *http.Response, error) {
+	return s.postThread(&ThreadError{
+		Parts:  threadErr.Parts,
+		Posted: append([]Tweet(nil), threadErr.Posted...),
+		params: threadErr.params,
+	})
+}
+
+// RollbackThread deletes the posted Tweets of a thread which failed part
+// way, newest first. Deleted Tweets are removed from the ThreadError's
+// Posted, so a failed rollback can be retried.
+// Requires a user auth context.
+func (s *StatusService) RollbackThread(threadErr *ThreadError) (*http.Response, error) {
+	var resp *http.Response
+	for len(threadErr.Posted) > 0 {
+		last := threadErr.Posted[len(threadErr.Posted)-1]
+		var err error
+		_, resp, err = s.Destroy(last.ID, nil)
+		if err != nil {
+			return resp, err
+		}
+		threadErr.Posted = threadErr.Posted[:len(threadErr.Posted)-1]
+	}
+	return resp, nil
+}
+
+// postThread posts the parts after the posted Tweets, each replying to the
+// previous Tweet.
+func (s *StatusService) postThread(thread *ThreadError) ([]Tweet, *http.Response, error) {
+	var resp *http.Response
+	for i := len(thread.Posted); i < len(thread.Parts); i++ {
+		params := &StatusUpdateParams{InReplyToStatusID: thread.params.InReplyToStatusID}
+		if i > 0 {
+			params.InReplyToStatusID = thread.Posted[i-1].ID
+		}
+		if i < len(thread.params.MediaIds) {
+			params.MediaIds = thread.params.MediaIds[i]
+		}
+		var tweet *Tweet
+		var err error
+		tweet, resp, err = s.Update(thread.Parts[i], params)
+		if err != nil {
+			thread.Err = err
+			return thread.Posted, resp, thread
+		}
+		thread.Posted = append(thread.Posted, *tweet)
+	}
+	return thread.Posted, resp, nil
+}
@@ -0,0 +1,235 @@
+package twitter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dghubble/go-twitter/twitter/twittertext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitThread(t *testing.T) {
+	sentence := "Gophers dig tunnels. "
+	long := strings.TrimSpace(strings.Repeat(sentence, 20))
+	url := "https://example.com/" + strings.Repeat("a", 300)
+	cases := []struct {
+		text     string
+		numbered bool
+		expected []string
+	}{
+		{"", false, nil},
+		{"  \n ", true, nil},
+		{" just setting up my twttr ", false, []string{"just setting up my twttr"}},
+		{"just setting up my twttr", true, []string{"just setting up my twttr 1/1"}},
+		// break after the last sentence which fits
+		{long, false, []string{
+			strings.TrimSpace(strings.Repeat(sentence, 13)),
+			strings.TrimSpace(strings.Repeat(sentence, 7)),
+		}},
+		{long, true, []string{
+			strings.TrimSpace(strings.Repeat(sentence, 13)) + " 1/2",
+			strings.TrimSpace(strings.Repeat(sentence, 7)) + " 2/2",
+		}},
+		// break at the last word which fits, without sentences
+		{strings.Repeat("gopher ", 50), false, []string{
+			strings.TrimSpace(strings.Repeat("gopher ", 40)),
+			strings.TrimSpace(strings.Repeat("gopher ", 10)),
+		}},
+		// break before URLs, which count as 23 characters
+		{strings.Repeat("a", 270) + " " + url, false, []string{strings.Repeat("a", 270), url}},
+		// break words which fill a part
+		{strings.Repeat("a", 300), false, []string{strings.Repeat("a", 280), strings.Repeat("a", 20)}},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, SplitThread(c.text, c.numbered))
+	}
+}
+
+func TestSplitThread_paragraphs(t *testing.T) {
+	first := strings.Repeat("Gophers dig tunnels. ", 8) + "Gophers dig tunnels."
+	second := strings.Repeat("Gophers eat roots. ", 8)
+	parts := SplitThread(first+"\n\n"+second, false)
+	assert.Equal(t, []string{first, strings.TrimSpace(second)}, parts)
+}
+
+func TestSplitThread_emoji(t *testing.T) {
+	// sequences starting with a light character fit in part, but are not
+	// split
+	for _, emoji := range []string{"1️⃣", "#️⃣", "©️", "®️"} {
+		fill := strings.Repeat("a", twittertext.MaxWeightedLength-twittertext.WeightedLength(string([]rune(emoji)[0])))
+		parts := SplitThread(fill+emoji+"b", false)
+		assert.Equal(t, []string{fill, emoji + "b"}, parts, emoji)
+	}
+}
+
+func TestSplitThread_shortDomains(t *testing.T) {
+	// scheme-less country code domains without a path weigh as written,
+	// not as 23 character URLs
+	text := strings.Repeat("ok.so what. ", 30)
+	parts := SplitThread(text, false)
+	assert.Equal(t, []string{
+		strings.TrimSpace(strings.Repeat("ok.so what. ", 23)),
+		strings.TrimSpace(strings.Repeat("ok.so what. ", 7)),
+	}, parts)
+}
+
+func TestSplitThread_weightedLength(t *testing.T) {
+	// CJK characters weigh 2, so 140 fit in a part
+	text := strings.Repeat("猫は寝る。", 60)
+	parts := SplitThread(text, true)
+	if assert.Len(t, parts, 3) {
+		assert.Equal(t, strings.Repeat("猫は寝る。", 27)+" 1/3", parts[0])
+		assert.Equal(t, strings.Repeat("猫は寝る。", 6)+" 3/3", parts[2])
+	}
+	for _, part := range parts {
+		assert.True(t, twittertext.WeightedLength(part) <= twittertext.MaxWeightedLength)
+	}
+}
+
+func TestSplitThread_numberWidth(t *testing.T) {
+	// numbers reserve room for as many digits as the number of parts
+	parts := SplitThread(strings.Repeat("gopher ", 500), true)
+	assert.Len(t, parts, 13)
+	for i, part := range parts {
+		assert.True(t, strings.HasSuffix(part, fmt.Sprintf(" %d/13", i+1)))
+		assert.True(t, twittertext.WeightedLength(part) <= twittertext.MaxWeightedLength)
+	}
+}
+
+// threadServer serves statuses/update.json, replying with Tweets numbered
+// from 1 and failing the update of the failing Tweet ID, if any. Updates
+// and destroyed Tweet IDs are recorded.
+type threadServer struct {
+	nextID    int64
+	failing   int64
+	updates   []map[string]string
+	destroyed []int64
+}
+
+func (s *threadServer) handle(t *testing.T, mux *http.ServeMux) {
+	mux.HandleFunc("/1.1/statuses/update.json", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, "POST", r)
+		assert.Nil(t, r.ParseForm())
+		w.Header().Set("Content-Type", "application/json")
+		if s.nextID+1 == s.failing {
+			s.failing = 0
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, `{"errors": [{"code": 130, "message": "Over capacity"}]}`)
+			return
+		}
+		s.nextID++
+		update := map[string]string{}
+		for key := range r.PostForm {
+			update[key] = r.PostForm.Get(key)
+		}
+		s.updates = append(s.updates, update)
+		fmt.Fprintf(w, `{"id": %d, "text": %q}`, s.nextID, r.PostForm.Get("status"))
+	})
+	mux.HandleFunc("/1.1/statuses/destroy/", func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, "POST", r)
+		id, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/1.1/statuses/destroy/"), ".json"), 10, 64)
+		assert.Nil(t, err)
+		s.destroyed = append(s.destroyed, id)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id": %d}`, id)
+	})
+}
+
+func TestStatusService_Thread(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+	threads := &threadServer{}
+	threads.handle(t, mux)
+
+	client := NewClient(httpClient)
+	text := strings.Repeat("Gophers dig tunnels. ", 30)
+	params := &ThreadParams{
+		Numbered:          true,
+		MediaIds:          [][]int64{{123456789, 987654321}, nil, {42}},
+		InReplyToStatusID: 99,
+	}
+	tweets, resp, err := client.Statuses.Thread(text, params)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	parts := SplitThread(text, true)
+	if assert.Len(t, tweets, 3) {
+		for i, tweet := range tweets {
+			assert.Equal(t, int64(i+1), tweet.ID)
+			assert.Equal(t, parts[i], tweet.Text)
+		}
+	}
+	expected := []map[string]string{
+		{"status": parts[0], "in_reply_to_status_id": "99", "media_ids": "123456789,987654321"},
+		{"status": parts[1], "in_reply_to_status_id": "1"},
+		{"status": parts[2], "in_reply_to_status_id": "2", "media_ids": "42"},
+	}
+	assert.Equal(t, expected, threads.updates)
+}
+
+func TestStatusService_ThreadResume(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+	threads := &threadServer{failing: 2}
+	threads.handle(t, mux)
+
+	client := NewClient(httpClient)
+	text := strings.Repeat("Gophers dig tunnels. ", 30)
+	params := &ThreadParams{MediaIds: [][]int64{nil, nil, {42}}}
+	tweets, resp, err := client.Statuses.Thread(text, params)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Len(t, tweets, 1)
+
+	var threadErr *ThreadError
+	if assert.True(t, errors.As(err, &threadErr)) {
+		assert.Equal(t, "twitter: thread posted 1 of 3 parts: twitter: 130 Over capacity", threadErr.Error())
+		assert.Equal(t, tweets, threadErr.Posted)
+		assert.Equal(t, threadErr.Parts[1:], threadErr.Remaining())
+		assert.Equal(t, APIError{Errors: []ErrorDetail{{Code: 130, Message: "Over capacity"}}}, errors.Unwrap(err))
+	}
+
+	tweets, _, err = client.Statuses.ResumeThread(threadErr)
+	assert.Nil(t, err)
+	assert.Len(t, tweets, 3)
+	// the error still records the first attempt
+	assert.Len(t, threadErr.Posted, 1)
+	expected := []map[string]string{
+		{"status": threadErr.Parts[0]},
+		{"status": threadErr.Parts[1], "in_reply_to_status_id": "1"},
+		{"status": threadErr.Parts[2], "in_reply_to_status_id": "2", "media_ids": "42"},
+	}
+	assert.Equal(t, expected, threads.updates)
+}
+
+func TestStatusService_ThreadRollback(t *testing.T) {
+	httpClient, mux, server := testServer()
+	defer server.Close()
+	threads := &threadServer{failing: 3}
+	threads.handle(t, mux)
+
+	client := NewClient(httpClient)
+	_, _, err := client.Statuses.Thread(strings.Repeat("Gophers dig tunnels. ", 30), nil)
+	threadErr, ok := err.(*ThreadError)
+	if assert.True(t, ok) {
+		assert.Len(t, threadErr.Posted, 2)
+		resp, err := client.Statuses.RollbackThread(threadErr)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, threadErr.Posted)
+		assert.Equal(t, []int64{2, 1}, threads.destroyed)
+	}
+}
+
+func TestStatusService_ThreadEmpty(t *testing.T) {
+	httpClient, _, server := testServer()
+	defer server.Close()
+
+	client := NewClient(httpClient)
+	tweets, resp, err := client.Statuses.Thread(" ", nil)
+	assert.Equal(t, twittertext.ErrEmpty, err)
+	assert.Nil(t, resp)
+	assert.Empty(t, tweets)
+}
@@ -0,0 +1,125 @@
+package twitter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeFormat is a format of times in the Twitter API.
+type TimeFormat int
+
+// Time formats.
+const (
+	// DefaultTimeFormat is the format of the field holding the Time:
+	// EpochMillisFormat for Direct Message events, ISO8601Format for trends,
+	// and RubyDateFormat otherwise.
+	DefaultTimeFormat TimeFormat = iota
+	// RubyDateFormat is e.g. "Wed Aug 27 13:08:45 +0000 2008".
+	RubyDateFormat
+	// EpochMillisFormat is a string of Unix milliseconds, e.g.
+	// "1542410751275".
+	EpochMillisFormat
+	// ISO8601Format is e.g. "2017-02-08T16:18:18Z".
+	ISO8601Format
+)
+
+// Time is a time decoded from one of the formats used by the Twitter API:
+// Ruby dates (e.g. "Wed Aug 27 13:08:45 +0000 2008") used by most objects,
+// epoch milliseconds used by Direct Message events, and ISO 8601 used by
+// trends. Decoded Times keep the original JSON, so they encode to the same
+// value they were decoded from. Values in other formats decode to the zero
+// Time, rather than failing to decode the whole response, and are available
+// from Raw. Other Times encode in their format (see NewTime).
+type Time struct {
+	time.Time
+	format TimeFormat
+	raw    string
+}
+
+// NewTime returns a Time which encodes in the given format.
+func NewTime(t time.Time, format TimeFormat) Time {
+	return Time{Time: t, format: format}
+}
+
+// UnmarshalJSON decodes a time string or epoch milliseconds number. Null,
+// empty strings, and unrecognized values decode to the zero Time.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	*t = Time{}
+	if string(data) == "null" {
+		return nil
+	}
+	t.raw = string(data)
+	if value := t.Raw(); value != "" {
+		t.Time, _ = parseTime(value)
+	}
+	return nil
+}
+
+// Raw returns the time value as received, without quotes (e.g. "Wed Aug 27
+// 13:08:45 +0000 2008" or "1542410751275"), or "" if the Time wasn't
+// decoded.
+func (t Time) Raw() string {
+	value := t.raw
+	if strings.HasPrefix(value, `"`) {
+		var unquoted string
+		if json.Unmarshal([]byte(value), &unquoted) == nil {
+			return unquoted
+		}
+	}
+	return value
+}
+
+// parse returns the Time, or an error if its raw value wasn't recognized.
+func (t Time) parse() (time.Time, error) {
+	if value := t.Raw(); t.IsZero() && value != "" {
+		return parseTime(value)
+	}
+	return t.Time, nil
+}
+
+// MarshalJSON encodes the original JSON the Time was decoded from, if any.
+// Otherwise, non-zero Times are encoded as strings in the Time's format
+// (Ruby dates by default) and zero Times as empty strings.
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.raw != "" {
+		return []byte(t.raw), nil
+	}
+	if t.IsZero() {
+		return []byte(`""`), nil
+	}
+	switch t.format {
+	case EpochMillisFormat:
+		return json.Marshal(strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10))
+	case ISO8601Format:
+		return json.Marshal(t.UTC().Format(time.RFC3339))
+	}
+	return json.Marshal(t.UTC().Format(time.RubyDate))
+}
+
+// withFormat returns the Time with the format, unless it has a format or
+// was decoded.
+func (t Time) withFormat(format TimeFormat) Time {
+	if t.format == DefaultTimeFormat && t.raw == "" {
+		t.format = format
+	}
+	return t
+}
+
+// Ruby date layout which also accepts days without a leading zero
+const rubyDateLayout = "Mon Jan _2 15:04:05 -0700 2006"
+
+// parseTime parses a time in any of the formats used by the Twitter API.
+func parseTime(value string) (time.Time, error) {
+	if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(0, millis*int64(time.Millisecond)).UTC(), nil
+	}
+	for _, layout := range []string{rubyDateLayout, time.RFC3339} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("twitter: unrecognized time %q", value)
+}
@@ -0,0 +1,160 @@
+package twitter
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testTime returns the Time decoded from the given JSON string value.
+func testTime(value string) Time {
+	var t Time
+	data, _ := json.Marshal(value)
+	if err := t.UnmarshalJSON(data); err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestTime_UnmarshalJSON(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected time.Time
+	}{
+		{`"Wed Aug 27 13:08:45 +0000 2008"`, time.Date(2008, time.August, 27, 13, 8, 45, 0, time.UTC)},
+		{`"Sat Sep 4 16:10:54 +0000 2010"`, time.Date(2010, time.September, 4, 16, 10, 54, 0, time.UTC)},
+		{`"Wed Aug 27 13:08:45 -0700 2008"`, time.Date(2008, time.August, 27, 20, 8, 45, 0, time.UTC)},
+		{`"1542410751275"`, time.Date(2018, time.November, 16, 23, 25, 51, 275000000, time.UTC)},
+		{`1542410751275`, time.Date(2018, time.November, 16, 23, 25, 51, 275000000, time.UTC)},
+		{`"2017-02-08T16:18:18Z"`, time.Date(2017, time.February, 8, 16, 18, 18, 0, time.UTC)},
+		{`""`, time.Time{}},
+		{`null`, time.Time{}},
+	}
+	for _, c := range cases {
+		var decoded Time
+		err := json.Unmarshal([]byte(c.input), &decoded)
+		assert.Nil(t, err, c.input)
+		assert.True(t, c.expected.Equal(decoded.Time), c.input)
+	}
+}
+
+func TestTime_UnmarshalJSONUnrecognized(t *testing.T) {
+	// unrecognized values decode to the zero Time and keep the raw value
+	for _, input := range []string{`"yesterday"`, `true`} {
+		var decoded Time
+		assert.Nil(t, json.Unmarshal([]byte(input), &decoded))
+		assert.True(t, decoded.IsZero())
+		data, err := json.Marshal(decoded)
+		assert.Nil(t, err)
+		assert.Equal(t, input, string(data))
+	}
+
+	var tweet Tweet
+	err := json.Unmarshal([]byte(`{"id": 1, "text": "hi", "created_at": "yesterday"}`), &tweet)
+	assert.Nil(t, err)
+	assert.Equal(t, "hi", tweet.Text)
+	assert.True(t, tweet.CreatedAt.IsZero())
+	assert.Equal(t, "yesterday", tweet.CreatedAt.Raw())
+	_, err = tweet.CreatedAtTime()
+	assert.EqualError(t, err, `twitter: unrecognized time "yesterday"`)
+}
+
+func TestTime_Raw(t *testing.T) {
+	cases := map[string]string{
+		`"Sat Sep 4 16:10:54 +0000 2010"`: "Sat Sep 4 16:10:54 +0000 2010",
+		`1542410751275`:                   "1542410751275",
+		`""`:                              "",
+		`null`:                            "",
+	}
+	for input, expected := range cases {
+		var decoded Time
+		assert.Nil(t, json.Unmarshal([]byte(input), &decoded))
+		assert.Equal(t, expected, decoded.Raw())
+	}
+	assert.Equal(t, "", Time{}.Raw())
+}
+
+func TestTime_MarshalJSON(t *testing.T) {
+	// decoded Times encode the original value
+	for _, input := range []string{`"Sat Sep 4 16:10:54 +0000 2010"`, `"1542410751275"`, `1542410751275`, `"2017-02-08T16:18:18Z"`} {
+		var decoded Time
+		assert.Nil(t, json.Unmarshal([]byte(input), &decoded))
+		data, err := json.Marshal(decoded)
+		assert.Nil(t, err)
+		assert.Equal(t, input, string(data))
+	}
+
+	data, err := json.Marshal(Time{Time: time.Date(2008, time.August, 27, 13, 8, 45, 0, time.UTC)})
+	assert.Nil(t, err)
+	assert.Equal(t, `"Wed Aug 27 13:08:45 +0000 2008"`, string(data))
+
+	data, err = json.Marshal(Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, `""`, string(data))
+}
+
+func TestNewTime(t *testing.T) {
+	date := time.Date(2018, time.November, 16, 23, 25, 51, 275000000, time.UTC)
+	cases := []struct {
+		format   TimeFormat
+		expected string
+	}{
+		{DefaultTimeFormat, `"Fri Nov 16 23:25:51 +0000 2018"`},
+		{RubyDateFormat, `"Fri Nov 16 23:25:51 +0000 2018"`},
+		{EpochMillisFormat, `"1542410751275"`},
+		{ISO8601Format, `"2018-11-16T23:25:51Z"`},
+	}
+	for _, c := range cases {
+		data, err := json.Marshal(NewTime(date, c.format))
+		assert.Nil(t, err)
+		assert.Equal(t, c.expected, string(data))
+	}
+}
+
+func TestTime_comparable(t *testing.T) {
+	// structs with Time fields can be compared and used as map keys
+	lists := map[List]bool{{CreatedAt: testTime("Wed Aug 27 13:08:45 +0000 2008")}: true}
+	assert.True(t, lists[List{CreatedAt: testTime("Wed Aug 27 13:08:45 +0000 2008")}])
+	event := DirectMessageEvent{CreatedAt: testTime("1542410751275")}
+	assert.True(t, event == DirectMessageEvent{CreatedAt: testTime("1542410751275")})
+}
+
+func TestTweet_CreatedAt(t *testing.T) {
+	var tweet Tweet
+	err := json.Unmarshal([]byte(`{"id": 1, "created_at": "Wed Aug 27 13:08:45 +0000 2008", "user": {"created_at": "Mon Apr 13 19:08:25 +0000 2015"}}`), &tweet)
+	assert.Nil(t, err)
+	assert.Equal(t, 2008, tweet.CreatedAt.Year())
+	assert.Equal(t, 2015, tweet.User.CreatedAt.Year())
+	createdAt, err := tweet.CreatedAtTime()
+	assert.Nil(t, err)
+	assert.Equal(t, tweet.CreatedAt.Time, createdAt)
+
+	data, err := json.Marshal(tweet)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"created_at":"Wed Aug 27 13:08:45 +0000 2008"`)
+}
+
+func TestDirectMessageEvent_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(DirectMessageEvent{Type: "message_create"})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"type":"message_create","message_create":null}`, string(data))
+
+	data, err = json.Marshal(DirectMessageEvent{CreatedAt: testTime("1542410751275"), Type: "message_create"})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"type":"message_create","message_create":null,"created_timestamp":"1542410751275"}`, string(data))
+
+	// Times built in code encode as epoch milliseconds
+	createdAt := Time{Time: time.Date(2018, time.November, 16, 23, 25, 51, 275000000, time.UTC)}
+	data, err = json.Marshal(DirectMessageEvent{CreatedAt: createdAt, Type: "message_create"})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"type":"message_create","message_create":null,"created_timestamp":"1542410751275"}`, string(data))
+}
+
+func TestTrendsList_MarshalJSON(t *testing.T) {
+	asOf := Time{Time: time.Date(2017, time.February, 8, 16, 18, 18, 0, time.UTC)}
+	data, err := json.Marshal(TrendsList{AsOf: asOf, CreatedAt: testTime("2017-02-08T16:11:54Z")})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"trends":null,"as_of":"2017-02-08T16:18:18Z","created_at":"2017-02-08T16:11:54Z","locations":null}`, string(data))
+}
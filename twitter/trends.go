@@ -1,6 +1,7 @@
 package twitter
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/dghubble/sling"
@@ -56,11 +57,20 @@ type Trend struct {
 // TrendsList represents a list of twitter trends.
 type TrendsList struct {
 	Trends    []Trend          `json:"trends"`
-	AsOf      string           `json:"as_of"`
-	CreatedAt string           `json:"created_at"`
+	AsOf      Time             `json:"as_of"`
+	CreatedAt Time             `json:"created_at"`
 	Locations []TrendsLocation `json:"locations"`
 }
 
+// MarshalJSON encodes the trends list, with times in ISO 8601 by default.
+func (l TrendsList) MarshalJSON() ([]byte, error) {
+	type trendsList TrendsList
+	aux := trendsList(l)
+	aux.AsOf = l.AsOf.withFormat(ISO8601Format)
+	aux.CreatedAt = l.CreatedAt.withFormat(ISO8601Format)
+	return json.Marshal(aux)
+}
+
 // TrendsLocation represents a twitter trend location.
 type TrendsLocation struct {
 	Name  string `json:"name"`
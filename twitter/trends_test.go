@@ -50,8 +50,8 @@ func TestTrendsService_Place(t *testing.T) {
 			Trends: []Trend{
 				{Name: "#gotwitter"},
 			},
-			AsOf:      "2017-02-08T16:18:18Z",
-			CreatedAt: "2017-02-08T16:10:33Z",
+			AsOf:      testTime("2017-02-08T16:18:18Z"),
+			CreatedAt: testTime("2017-02-08T16:10:33Z"),
 			Locations: []TrendsLocation{
 				{Name: "Worldwide", WOEID: 1},
 			},
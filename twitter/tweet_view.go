@@ -0,0 +1,98 @@
+package twitter
+
+import "unicode/utf8"
+
+// TweetView is a normalized view of a Tweet's content. It resolves the
+// full text and entities of extended Tweets (in compatibility or extended
+// mode) and the original content of retweets, so Tweets from REST and
+// streaming APIs are read the same way.
+type TweetView struct {
+	// Tweet which is viewed
+	Tweet *Tweet
+	// Tweet with the content: the retweeted Tweet for retweets, otherwise
+	// the viewed Tweet
+	Original *Tweet
+	// author of the content, the original author for retweets
+	Author *User
+	// user who retweeted the Original, for retweets
+	RetweetedBy *User
+	// untruncated text, including any leading reply mentions and trailing
+	// media or quote URLs outside the DisplayTextRange
+	FullText string
+	// range of FullText which is displayed, in code points
+	DisplayTextRange Indices
+	// entities of the FullText
+	Entities         *Entities
+	ExtendedEntities *ExtendedEntity
+	// view of the quoted Tweet, if any
+	Quoted *TweetView
+}
+
+// View returns a TweetView of the Tweet, or nil for a nil Tweet.
+func (t *Tweet) View() *TweetView {
+	if t == nil {
+		return nil
+	}
+	view := &TweetView{Tweet: t, Original: t}
+	if t.RetweetedStatus != nil {
+		view.Original = t.RetweetedStatus
+		view.RetweetedBy = t.User
+	}
+	original := view.Original
+	view.Author = original.User
+	view.FullText, view.DisplayTextRange, view.Entities, view.ExtendedEntities = original.content()
+	if view.DisplayTextRange == (Indices{}) {
+		view.DisplayTextRange = Indices{0, utf8.RuneCountInString(view.FullText)}
+	}
+	quoted := original.QuotedStatus
+	if quoted == nil {
+		quoted = t.QuotedStatus
+	}
+	view.Quoted = quoted.View()
+	return view
+}
+
+// content returns the Tweet's full text, display range, and entities, from
+// the extended_tweet field of compatibility mode Tweets if present.
+func (t *Tweet) content() (string, Indices, *Entities, *ExtendedEntity) {
+	if t.ExtendedTweet != nil {
+		extended := t.ExtendedTweet
+		return extended.FullText, extended.DisplayTextRange, extended.Entities, extended.ExtendedEntities
+	}
+	text := t.Text
+	if t.FullText != "" {
+		text = t.FullText
+	}
+	return text, t.DisplayTextRange, t.Entities, t.ExtendedEntities
+}
+
+// IsRetweet returns true if the viewed Tweet is a retweet.
+func (v *TweetView) IsRetweet() bool {
+	return v.Original != v.Tweet
+}
+
+// DisplayText returns the part of the FullText which is displayed, without
+// leading reply mentions or trailing media and quote URLs.
+func (v *TweetView) DisplayText() string {
+	runes := []rune(v.FullText)
+	start, end := v.DisplayTextRange.Start(), v.DisplayTextRange.End()
+	if end > len(runes) {
+		end = len(runes)
+	}
+	if start < 0 || start > end {
+		return ""
+	}
+	return string(runes[start:end])
+}
+
+// Media returns the Tweet's media, from the ExtendedEntities (which include
+// every photo) if present, otherwise from the Entities.
+func (v *TweetView) Media() []MediaEntity {
+	if v.ExtendedEntities != nil && len(v.ExtendedEntities.Media) > 0 {
+		return v.ExtendedEntities.Media
+	}
+	if v.Entities != nil {
+		return v.Entities.Media
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package twitter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// compatibility mode Tweet, as received from streams
+const testCompatTweetJSON = `{
+	"id": 2,
+	"text": "@gopher A long Tweet which is truncated in compatibility mode … https://t.co/abc",
+	"truncated": true,
+	"user": {"id": 20, "screen_name": "alice"},
+	"entities": {"urls": [{"url": "https://t.co/abc", "indices": [66, 82]}]},
+	"extended_tweet": {
+		"full_text": "@gopher A long Tweet which is truncated in compatibility mode, but not in full 🐹 https://t.co/pic",
+		"display_text_range": [8, 80],
+		"entities": {"user_mentions": [{"id": 10, "screen_name": "gopher", "indices": [0, 7]}]},
+		"extended_entities": {"media": [{"id": 5, "type": "photo", "url": "https://t.co/pic", "indices": [81, 97]}]}
+	}
+}`
+
+func TestTweet_View_compatibility(t *testing.T) {
+	tweet := new(Tweet)
+	assert.Nil(t, json.Unmarshal([]byte(testCompatTweetJSON), tweet))
+	view := tweet.View()
+	assert.False(t, view.IsRetweet())
+	assert.Equal(t, tweet, view.Original)
+	assert.Equal(t, "alice", view.Author.ScreenName)
+	assert.Nil(t, view.RetweetedBy)
+	assert.Equal(t, tweet.ExtendedTweet.FullText, view.FullText)
+	assert.Equal(t, Indices{8, 80}, view.DisplayTextRange)
+	assert.Equal(t, "A long Tweet which is truncated in compatibility mode, but not in full \U0001F439", view.DisplayText())
+	assert.Equal(t, "gopher", view.Entities.UserMentions[0].ScreenName)
+	assert.Equal(t, int64(5), view.Media()[0].ID)
+	assert.Nil(t, view.Quoted)
+}
+
+func TestTweet_View_extendedMode(t *testing.T) {
+	tweet := &Tweet{
+		ID:               1,
+		FullText:         "@gopher hi https://t.co/pic",
+		DisplayTextRange: Indices{8, 10},
+		User:             &User{ScreenName: "alice"},
+		Entities:         &Entities{Media: []MediaEntity{{ID: 5}}},
+	}
+	view := tweet.View()
+	assert.Equal(t, "@gopher hi https://t.co/pic", view.FullText)
+	assert.Equal(t, "hi", view.DisplayText())
+	assert.Equal(t, tweet.Entities, view.Entities)
+	// media from Entities without ExtendedEntities
+	assert.Equal(t, int64(5), view.Media()[0].ID)
+}
+
+func TestTweet_View_text(t *testing.T) {
+	view := (&Tweet{Text: "short \U0001F439"}).View()
+	assert.Equal(t, "short \U0001F439", view.FullText)
+	assert.Equal(t, Indices{0, 7}, view.DisplayTextRange)
+	assert.Equal(t, "short \U0001F439", view.DisplayText())
+	assert.Nil(t, view.Media())
+}
+
+func TestTweet_View_retweet(t *testing.T) {
+	original := new(Tweet)
+	assert.Nil(t, json.Unmarshal([]byte(testCompatTweetJSON), original))
+	quoted := &Tweet{ID: 1, Text: "quoted", User: &User{ScreenName: "carol"}}
+	original.QuotedStatus = quoted
+	retweet := &Tweet{
+		ID:              3,
+		Text:            "RT @alice: @gopher A long Tweet which is truncated in compatibility…",
+		Truncated:       true,
+		User:            &User{ScreenName: "bob"},
+		RetweetedStatus: original,
+	}
+	view := retweet.View()
+	assert.True(t, view.IsRetweet())
+	assert.Equal(t, retweet, view.Tweet)
+	assert.Equal(t, original, view.Original)
+	assert.Equal(t, "alice", view.Author.ScreenName)
+	assert.Equal(t, "bob", view.RetweetedBy.ScreenName)
+	assert.Equal(t, original.ExtendedTweet.FullText, view.FullText)
+	if assert.NotNil(t, view.Quoted) {
+		assert.Equal(t, quoted, view.Quoted.Tweet)
+		assert.Equal(t, "carol", view.Quoted.Author.ScreenName)
+		assert.Equal(t, "quoted", view.Quoted.DisplayText())
+	}
+
+	// streamed retweets of quote Tweets may only carry the quoted Tweet on
+	// the retweet
+	original.QuotedStatus = nil
+	retweet.QuotedStatus = quoted
+	assert.Equal(t, quoted, retweet.View().Quoted.Tweet)
+}
+
+func TestTweet_View_nil(t *testing.T) {
+	var tweet *Tweet
+	assert.Nil(t, tweet.View())
+}
+
+func TestTweetView_DisplayText_invalidRange(t *testing.T) {
+	view := &TweetView{FullText: "abc", DisplayTextRange: Indices{1, 10}}
+	assert.Equal(t, "bc", view.DisplayText())
+	view.DisplayTextRange = Indices{5, 2}
+	assert.Equal(t, "", view.DisplayText())
+}
@@ -3,6 +3,7 @@ package twitter
 import (
 	"net/http"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/dghubble/sling"
 )
 
@@ -30,9 +31,64 @@ type Client struct {
 	Users          *UserService
 }
 
+// ClientOption configures a Client.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	rawJSON           bool
+	recorder          StreamRecorder
+	backOff           func() backoff.BackOff
+	aggressiveBackOff func() backoff.BackOff
+	defaultParams     *DefaultParams
+}
+
+// WithRawJSON retains the original JSON of decoded Tweets, Users, and Direct
+// Messages in their Raw fields, so they re-marshal to the JSON received,
+// including fields the structs do not know about.
+func WithRawJSON() ClientOption {
+	return func(o *clientOptions) {
+		o.rawJSON = true
+	}
+}
+
+// WithStreamRecorder records the raw messages received by every Stream (see
+// FileRecorder and ReplayStream).
+func WithStreamRecorder(recorder StreamRecorder) ClientOption {
+	return func(o *clientOptions) {
+		o.recorder = recorder
+	}
+}
+
+// WithStreamBackOff sets the policies Streams use to wait between reconnects
+// after 503 responses (exponential) and after 420 or 429 responses
+// (aggressive), e.g. to shorten waits in tests. Each Stream calls the
+// functions to get its own BackOffs. Nil functions keep the default policies
+// recommended by Twitter.
+func WithStreamBackOff(exponential, aggressive func() backoff.BackOff) ClientOption {
+	return func(o *clientOptions) {
+		o.backOff = exponential
+		o.aggressiveBackOff = aggressive
+	}
+}
+
 // NewClient returns a new Client.
-func NewClient(httpClient *http.Client) *Client {
+func NewClient(httpClient *http.Client, opts ...ClientOption) *Client {
+	options := &clientOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
 	base := sling.New().Client(httpClient).Base(twitterAPI)
+	if options.rawJSON {
+		base.ResponseDecoder(rawJSONDecoder{})
+	}
+	var doer sling.Doer = http.DefaultClient
+	if httpClient != nil {
+		doer = httpClient
+	}
+	if options.defaultParams != nil {
+		doer = newDefaultParamsDoer(httpClient, twitterAPI, options.defaultParams)
+		base.Doer(doer)
+	}
 	return &Client{
 		sling:          base,
 		Accounts:       newAccountService(base.New()),
@@ -47,7 +103,7 @@ func NewClient(httpClient *http.Client) *Client {
 		Search:         newSearchService(base.New()),
 		PremiumSearch:  newPremiumSearchService(base.New()),
 		Statuses:       newStatusService(base.New()),
-		Streams:        newStreamService(httpClient, base.New()),
+		Streams:        newStreamService(httpClient, doer, base.New(), options),
 		Timelines:      newTimelineService(base.New()),
 		Trends:         newTrendsService(base.New()),
 		Users:          newUserService(base.New()),
@@ -0,0 +1,434 @@
+package twittertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// value which replaces redacted secrets
+const redacted = "REDACTED"
+
+// ErrUnmatchedRequest is returned (wrapped) by a replaying Cassette for
+// requests which match none of its interactions.
+var ErrUnmatchedRequest = errors.New("twittertest: request does not match the cassette")
+
+// headers which are always redacted
+var redactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// query, form, and JSON body parameters which are always redacted
+var redactedParams = []string{
+	"oauth_consumer_key",
+	"oauth_token",
+	"oauth_token_secret",
+	"oauth_signature",
+	"oauth_verifier",
+	"access_token",
+	"access_token_secret",
+	"consumer_key",
+	"consumer_secret",
+}
+
+// CassetteMode is whether a Cassette records or replays.
+type CassetteMode int
+
+// Cassette modes.
+const (
+	// Replay responds to requests with recorded responses.
+	Replay CassetteMode = iota
+	// Record sends requests and records the responses.
+	Record
+)
+
+// CassetteMatch is how a replaying Cassette matches requests to recorded
+// interactions.
+type CassetteMatch int
+
+// Cassette matching modes.
+const (
+	// MatchStrict matches requests with the same method, path, query, and
+	// body. Each interaction is replayed once.
+	MatchStrict CassetteMatch = iota
+	// MatchLenient matches requests with the same method and path which
+	// include the recorded query and body parameters. Interactions are
+	// replayed in order and the last matching interaction repeats.
+	MatchLenient
+)
+
+// CassetteParams are the parameters for NewCassette.
+type CassetteParams struct {
+	// path of the cassette file
+	Path string
+	// record or replay (default Replay)
+	Mode CassetteMode
+	// how replayed requests are matched (default MatchStrict)
+	Match CassetteMatch
+	// transport which sends recorded requests (default http.DefaultTransport)
+	Transport http.RoundTripper
+	// names of additional headers and parameters to redact
+	Redact []string
+}
+
+// Interaction is a recorded request and response. Secrets are redacted.
+type Interaction struct {
+	Request  InteractionRequest  `json:"request"`
+	Response InteractionResponse `json:"response"`
+}
+
+// InteractionRequest is a recorded request.
+type InteractionRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// InteractionResponse is a recorded response.
+type InteractionResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Cassette is an http.RoundTripper which records REST API interactions to a
+// file or replays them, for deterministic tests built from real API
+// responses. OAuth Authorization headers and token parameters are redacted
+// from recordings. Its methods are safe for concurrent use.
+//
+// To record, use a Cassette as the transport beneath the OAuth1 or OAuth2
+// transport and Save it when finished:
+//
+//	cassette, err := twittertest.NewCassette(&twittertest.CassetteParams{Path: "testdata/timeline.json", Mode: twittertest.Record})
+//	ctx := context.WithValue(oauth1.NoContext, oauth1.HTTPClient, cassette.Client())
+//	client := twitter.NewClient(config.Client(ctx, token))
+//	...
+//	err = cassette.Save()
+//
+// To replay, pass its Client to twitter.NewClient.
+type Cassette struct {
+	path      string
+	mode      CassetteMode
+	match     CassetteMatch
+	transport http.RoundTripper
+	redact    []string
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+	last         map[string]int
+}
+
+// NewCassette returns a new Cassette. Replaying Cassettes read their file.
+func NewCassette(params *CassetteParams) (*Cassette, error) {
+	if params == nil {
+		params = &CassetteParams{}
+	}
+	transport := params.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	c := &Cassette{
+		path:      params.Path,
+		mode:      params.Mode,
+		match:     params.Match,
+		transport: transport,
+		redact:    append(append([]string(nil), redactedParams...), params.Redact...),
+		last:      make(map[string]int),
+	}
+	if c.mode == Replay {
+		data, err := os.ReadFile(c.path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &c.interactions); err != nil {
+			return nil, fmt.Errorf("twittertest: invalid cassette %s: %v", c.path, err)
+		}
+		c.used = make([]bool, len(c.interactions))
+	}
+	return c, nil
+}
+
+// Client returns an http.Client which uses the Cassette as its transport.
+func (c *Cassette) Client() *http.Client {
+	return &http.Client{Transport: c}
+}
+
+// Interactions returns the recorded interactions.
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Interaction(nil), c.interactions...)
+}
+
+// Save writes the recorded interactions to the cassette file.
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, append(data, '\n'), 0644)
+}
+
+// RoundTrip records the request and its response, or replays the recorded
+// response to the request.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if c.mode == Record {
+		return c.record(req, body)
+	}
+	return c.replay(req, body)
+}
+
+// record sends the request and records the interaction.
+func (c *Cassette) record(req *http.Request, body []byte) (*http.Response, error) {
+	sent := req.Clone(req.Context())
+	if req.Body != nil {
+		sent.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	resp, err := c.transport.RoundTrip(sent)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	recordedURL := *req.URL
+	recordedURL.RawQuery = c.redactValues(req.URL.Query()).Encode()
+	interaction := Interaction{
+		Request: InteractionRequest{
+			Method: req.Method,
+			URL:    recordedURL.String(),
+			Header: c.redactHeader(req.Header),
+			Body:   c.redactBody(req.Header.Get("Content-Type"), body),
+		},
+		Response: InteractionResponse{
+			Status: resp.StatusCode,
+			Header: c.redactHeader(resp.Header),
+			Body:   c.redactBody(resp.Header.Get("Content-Type"), respBody),
+		},
+	}
+	c.mu.Lock()
+	c.interactions = append(c.interactions, interaction)
+	c.mu.Unlock()
+	return resp, nil
+}
+
+// replay returns the recorded response of the interaction matching the
+// request.
+func (c *Cassette) replay(req *http.Request, body []byte) (*http.Response, error) {
+	query := c.matchValues(req.URL.Query())
+	normalized := c.normalizeBody(req.Header.Get("Content-Type"), body)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := req.Method + " " + req.URL.Path
+	match := -1
+	for i, interaction := range c.interactions {
+		recorded, err := url.Parse(interaction.Request.URL)
+		if err != nil || interaction.Request.Method != req.Method || recorded.Path != req.URL.Path {
+			continue
+		}
+		recordedBody := c.normalizeBody(interaction.Request.Header.Get("Content-Type"), []byte(interaction.Request.Body))
+		recordedQuery := c.matchValues(recorded.Query())
+		if c.match == MatchStrict {
+			if !c.used[i] && recordedQuery.Encode() == query.Encode() && recordedBody == normalized {
+				match = i
+				break
+			}
+			continue
+		}
+		if includes(query, recordedQuery) && includesBody(normalized, recordedBody) {
+			if !c.used[i] {
+				match = i
+				break
+			}
+			if last, ok := c.last[key]; ok && last == i {
+				match = i
+			}
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrUnmatchedRequest, req.Method, req.URL.RequestURI())
+	}
+	c.used[match] = true
+	c.last[key] = match
+	response := c.interactions[match].Response
+	header := response.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        strconv.Itoa(response.Status) + " " + http.StatusText(response.Status),
+		StatusCode:    response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(response.Body)),
+		ContentLength: int64(len(response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// redactHeader returns a copy of the header with secrets redacted.
+func (c *Cassette) redactHeader(header http.Header) http.Header {
+	redactedHeader := header.Clone()
+	for _, name := range append(redactedHeaders, c.redact...) {
+		if redactedHeader.Get(name) != "" {
+			redactedHeader.Set(name, redacted)
+		}
+	}
+	return redactedHeader
+}
+
+// redactValues returns a copy of the values with secrets redacted.
+func (c *Cassette) redactValues(values url.Values) url.Values {
+	redactedValues := url.Values{}
+	for name, vals := range values {
+		redactedValues[name] = vals
+		if c.redacts(name) {
+			redactedValues[name] = []string{redacted}
+		}
+	}
+	return redactedValues
+}
+
+// redactBody returns the body with secret form or JSON object parameters
+// redacted.
+func (c *Cassette) redactBody(contentType string, body []byte) string {
+	switch {
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		values, err := url.ParseQuery(string(body))
+		if err == nil {
+			return c.redactValues(values).Encode()
+		}
+	case strings.HasPrefix(contentType, "application/json"):
+		var object map[string]json.RawMessage
+		if json.Unmarshal(body, &object) == nil {
+			changed := false
+			for name := range object {
+				if c.redacts(name) {
+					object[name] = json.RawMessage(strconv.Quote(redacted))
+					changed = true
+				}
+			}
+			if changed {
+				data, _ := json.Marshal(object)
+				return string(data)
+			}
+		}
+	}
+	return string(body)
+}
+
+// matchValues returns a copy of the values without secrets, which differ
+// between recording and replay.
+func (c *Cassette) matchValues(values url.Values) url.Values {
+	matched := url.Values{}
+	for name, vals := range values {
+		if !c.redacts(name) {
+			matched[name] = vals
+		}
+	}
+	return matched
+}
+
+// normalizeBody returns the body without secrets in a canonical form, with
+// form parameters and JSON object keys sorted and JSON whitespace removed.
+func (c *Cassette) normalizeBody(contentType string, body []byte) string {
+	var value interface{}
+	if json.Unmarshal(body, &value) == nil {
+		if object, ok := value.(map[string]interface{}); ok {
+			for name := range object {
+				if c.redacts(name) {
+					delete(object, name)
+				}
+			}
+		}
+		data, _ := json.Marshal(value)
+		return string(data)
+	}
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		if values, err := url.ParseQuery(string(body)); err == nil {
+			return c.matchValues(values).Encode()
+		}
+	}
+	return string(body)
+}
+
+// redacts reports whether the header or parameter is redacted.
+func (c *Cassette) redacts(name string) bool {
+	for _, other := range c.redact {
+		if strings.EqualFold(name, other) {
+			return true
+		}
+	}
+	return false
+}
+
+// includes reports whether the values include all of the expected values.
+func includes(values, expected url.Values) bool {
+	for name, vals := range expected {
+		if strings.Join(values[name], ",") != strings.Join(vals, ",") {
+			return false
+		}
+	}
+	return true
+}
+
+// includesBody reports whether the normalized body includes the expected
+// normalized body: as parameters for form bodies, as object fields for JSON
+// bodies, or exactly otherwise.
+func includesBody(body, expected string) bool {
+	if body == expected || expected == "" {
+		return true
+	}
+	var object, expectedObject map[string]interface{}
+	if json.Unmarshal([]byte(body), &object) == nil && json.Unmarshal([]byte(expected), &expectedObject) == nil {
+		for name, value := range expectedObject {
+			data, _ := json.Marshal(value)
+			other, _ := json.Marshal(object[name])
+			if !bytes.Equal(data, other) {
+				return false
+			}
+		}
+		return true
+	}
+	values, err := url.ParseQuery(body)
+	expectedValues, expectedErr := url.ParseQuery(expected)
+	return err == nil && expectedErr == nil && includes(values, expectedValues)
+}
+
+// readBody reads the request body, from a copy if the request has one, and
+// closes the request body, as a RoundTripper must.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+	return io.ReadAll(req.Body)
+}
@@ -0,0 +1,175 @@
+package twittertest
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dghubble/go-twitter/twitter"
+	"github.com/stretchr/testify/assert"
+)
+
+// authTransport adds OAuth credentials to requests, like an OAuth1
+// transport would.
+type authTransport struct {
+	transport http.RoundTripper
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", `OAuth oauth_consumer_key="key", oauth_token="token", oauth_signature="sig"`)
+	query := req.URL.Query()
+	query.Set("access_token", "secret-token")
+	req.URL.RawQuery = query.Encode()
+	return t.transport.RoundTrip(req)
+}
+
+// recordCassette records interactions with a Server to a cassette file and
+// returns its path.
+func recordCassette(t *testing.T) string {
+	server, _, alice, _ := testClient()
+	defer server.Close()
+	server.AddTweet(alice.ID, "hello")
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	cassette, err := NewCassette(&CassetteParams{Path: path, Mode: Record, Transport: server.Client().Transport})
+	assert.Nil(t, err)
+	client := twitter.NewClient(&http.Client{Transport: &authTransport{cassette}})
+
+	tweets, _, err := client.Timelines.UserTimeline(&twitter.UserTimelineParams{ScreenName: "alice", Count: 5})
+	assert.Nil(t, err)
+	assert.Len(t, tweets, 1)
+	_, _, err = client.Statuses.Update("recorded", nil)
+	assert.Nil(t, err)
+	_, _, err = client.Statuses.Update("recorded", nil)
+	assert.NotNil(t, err)
+	assert.Len(t, cassette.Interactions(), 3)
+	assert.Nil(t, cassette.Save())
+	return path
+}
+
+func TestCassette_Redacts(t *testing.T) {
+	data, err := os.ReadFile(recordCassette(t))
+	assert.Nil(t, err)
+	cassette := string(data)
+	assert.True(t, strings.Contains(cassette, redacted))
+	for _, secret := range []string{"oauth_signature=", "sig", "secret-token"} {
+		assert.False(t, strings.Contains(cassette, secret), secret)
+	}
+}
+
+func TestCassette_ReplayStrict(t *testing.T) {
+	cassette, err := NewCassette(&CassetteParams{Path: recordCassette(t)})
+	assert.Nil(t, err)
+	client := twitter.NewClient(&http.Client{Transport: &authTransport{cassette}})
+
+	tweets, resp, err := client.Timelines.UserTimeline(&twitter.UserTimelineParams{Count: 5, ScreenName: "alice"})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	if assert.Len(t, tweets, 1) {
+		assert.Equal(t, "hello", tweets[0].Text)
+	}
+	tweet, _, err := client.Statuses.Update("recorded", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "recorded", tweet.Text)
+	// the duplicate update replays its recorded error
+	_, resp, err = client.Statuses.Update("recorded", nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	if apiErr, ok := err.(twitter.APIError); assert.True(t, ok) {
+		assert.Equal(t, 187, apiErr.Errors[0].Code)
+	}
+	// each interaction replays once
+	_, _, err = client.Statuses.Update("recorded", nil)
+	assert.True(t, errors.Is(err, ErrUnmatchedRequest))
+	_, _, err = client.Timelines.UserTimeline(&twitter.UserTimelineParams{Count: 6, ScreenName: "alice"})
+	assert.True(t, errors.Is(err, ErrUnmatchedRequest))
+}
+
+func TestCassette_ReplayLenient(t *testing.T) {
+	cassette, err := NewCassette(&CassetteParams{Path: recordCassette(t), Match: MatchLenient})
+	assert.Nil(t, err)
+	client := twitter.NewClient(cassette.Client())
+
+	// extra parameters are allowed and interactions repeat
+	for i := 0; i < 2; i++ {
+		tweets, _, err := client.Timelines.UserTimeline(&twitter.UserTimelineParams{Count: 5, ScreenName: "alice", TrimUser: twitter.Bool(true)})
+		assert.Nil(t, err)
+		assert.Len(t, tweets, 1)
+	}
+	// matching interactions replay in order, then the last repeats
+	for _, code := range []int{http.StatusOK, http.StatusForbidden, http.StatusForbidden} {
+		_, resp, _ := client.Statuses.Update("recorded", nil)
+		assert.Equal(t, code, resp.StatusCode)
+	}
+	// recorded parameters must match
+	_, _, err = client.Timelines.UserTimeline(&twitter.UserTimelineParams{Count: 5, ScreenName: "bob"})
+	assert.True(t, errors.Is(err, ErrUnmatchedRequest))
+	_, _, err = client.Timelines.HomeTimeline(nil)
+	assert.True(t, errors.Is(err, ErrUnmatchedRequest))
+}
+
+// closeCounter is a request body which counts calls to Close.
+type closeCounter struct {
+	io.Reader
+	closed int
+}
+
+func (c *closeCounter) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestCassette_ClosesRequestBody(t *testing.T) {
+	server, _, _, _ := testClient()
+	defer server.Close()
+	cassette, err := NewCassette(&CassetteParams{
+		Path:      filepath.Join(t.TempDir(), "cassette.json"),
+		Mode:      Record,
+		Transport: server.Client().Transport,
+	})
+	assert.Nil(t, err)
+
+	for _, getBody := range []bool{false, true} {
+		body := &closeCounter{Reader: strings.NewReader("status=closed")}
+		req, err := http.NewRequest("POST", "https://api.twitter.com/1.1/statuses/update.json", body)
+		assert.Nil(t, err)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if getBody {
+			req.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader("status=copied")), nil
+			}
+		}
+		resp, err := cassette.RoundTrip(req)
+		assert.Nil(t, err)
+		resp.Body.Close()
+		assert.Equal(t, 1, body.closed, "GetBody %v", getBody)
+	}
+}
+
+func TestNewCassette_errors(t *testing.T) {
+	_, err := NewCassette(&CassetteParams{Path: filepath.Join(t.TempDir(), "missing.json")})
+	assert.NotNil(t, err)
+	path := filepath.Join(t.TempDir(), "invalid.json")
+	assert.Nil(t, os.WriteFile(path, []byte("{"), 0644))
+	_, err = NewCassette(&CassetteParams{Path: path})
+	assert.NotNil(t, err)
+}
+
+func TestCassette_normalizeBody(t *testing.T) {
+	cassette, _ := NewCassette(&CassetteParams{Mode: Record})
+	cases := []struct {
+		contentType string
+		body        string
+		expected    string
+	}{
+		{"application/x-www-form-urlencoded", "b=2&a=1&oauth_token=t", "a=1&b=2"},
+		{"application/json", `{"b": 2, "access_token": "t", "a": {"y": 1, "x": 0}}`, `{"a":{"x":0,"y":1},"b":2}`},
+		{"text/plain", "b=2&a=1", "b=2&a=1"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, cassette.normalizeBody(c.contentType, []byte(c.body)))
+	}
+}
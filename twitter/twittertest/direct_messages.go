@@ -0,0 +1,115 @@
+package twittertest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/dghubble/go-twitter/twitter"
+)
+
+var (
+	errInvalidEvent  = &apiError{http.StatusBadRequest, 214, "Event parameter is missing or invalid."}
+	errNoRecipient   = &apiError{http.StatusNotFound, 108, "Cannot find specified user."}
+	errCannotMessage = &apiError{http.StatusForbidden, 349, "You cannot send messages to this user."}
+)
+
+// involves reports whether the user sent or received the event.
+func involves(event *twitter.DirectMessageEvent, userID int64) bool {
+	id := strconv.FormatInt(userID, 10)
+	return event.Message.SenderID == id || event.Message.Target.RecipientID == id
+}
+
+// findMessage returns the index of the event identified by the id parameter
+// if the authenticated user sent or received it.
+func (s *Server) findMessage(req *http.Request) (int, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return 0, err
+	}
+	id := req.Form.Get("id")
+	for i, event := range s.messages {
+		if event.ID == id && involves(event, userID) {
+			return i, nil
+		}
+	}
+	return 0, errNotFound
+}
+
+func (s *Server) directMessagesEventsNew(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	params := new(twitter.DirectMessageEventsNewParams)
+	if json.NewDecoder(req.Body).Decode(params) != nil || params.Event == nil || params.Event.Message == nil ||
+		params.Event.Message.Target == nil || params.Event.Message.Data == nil {
+		return nil, errInvalidEvent
+	}
+	recipientID, _ := strconv.ParseInt(params.Event.Message.Target.RecipientID, 10, 64)
+	if s.users[recipientID] == nil {
+		return nil, errNoRecipient
+	}
+	if hasID(s.blocking[recipientID], userID) {
+		return nil, errCannotMessage
+	}
+	data := *params.Event.Message.Data
+	data.Entities = s.entities(data.Text)
+	event := &twitter.DirectMessageEvent{
+		ID:        strconv.FormatInt(s.nextID(), 10),
+		Type:      "message_create",
+		CreatedAt: twitter.Time{Time: s.now().UTC()},
+		Message: &twitter.DirectMessageEventMessage{
+			SenderID: strconv.FormatInt(userID, 10),
+			Target:   &twitter.DirectMessageTarget{RecipientID: params.Event.Message.Target.RecipientID},
+			Data:     &data,
+		},
+	}
+	s.messages = append(s.messages, event)
+	return map[string]interface{}{"event": event}, nil
+}
+
+func (s *Server) directMessagesEventsShow(req *http.Request, id string) (interface{}, *apiError) {
+	i, err := s.findMessage(req)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"event": s.messages[i]}, nil
+}
+
+// directMessagesEventsList lists events the authenticated user sent or
+// received, newest first. Cursors are offsets into the events.
+func (s *Server) directMessagesEventsList(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	var events []twitter.DirectMessageEvent
+	for i := len(s.messages) - 1; i >= 0; i-- {
+		if involves(s.messages[i], userID) {
+			events = append(events, *s.messages[i])
+		}
+	}
+	start, _ := strconv.Atoi(req.Form.Get("cursor"))
+	if start < 0 || start > len(events) {
+		start = len(events)
+	}
+	end := start + countParam(req.Form, 20, 50)
+	page := &twitter.DirectMessageEvents{Events: []twitter.DirectMessageEvent{}}
+	if end < len(events) {
+		page.NextCursor = strconv.Itoa(end)
+	} else {
+		end = len(events)
+	}
+	page.Events = append(page.Events, events[start:end]...)
+	return page, nil
+}
+
+func (s *Server) directMessagesEventsDestroy(req *http.Request, id string) (interface{}, *apiError) {
+	i, err := s.findMessage(req)
+	if err != nil {
+		return nil, err
+	}
+	s.messages = append(s.messages[:i], s.messages[i+1:]...)
+	return nil, nil
+}
@@ -0,0 +1,90 @@
+package twittertest
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/dghubble/go-twitter/twitter"
+	"github.com/stretchr/testify/assert"
+)
+
+// newMessage returns the parameters to send a Direct Message.
+func newMessage(recipientID int64, text string) *twitter.DirectMessageEventsNewParams {
+	return &twitter.DirectMessageEventsNewParams{
+		Event: &twitter.DirectMessageEvent{
+			Type: "message_create",
+			Message: &twitter.DirectMessageEventMessage{
+				Target: &twitter.DirectMessageTarget{RecipientID: strconv.FormatInt(recipientID, 10)},
+				Data:   &twitter.DirectMessageData{Text: text},
+			},
+		},
+	}
+}
+
+func TestDirectMessages_Events(t *testing.T) {
+	server, client, alice, bob := testClient()
+	defer server.Close()
+
+	event, _, err := client.DirectMessages.EventsNew(newMessage(bob.ID, "hi @bob"))
+	assert.Nil(t, err)
+	assert.NotEmpty(t, event.ID)
+	assert.Equal(t, alice.IDStr, event.Message.SenderID)
+	assert.Equal(t, "hi @bob", event.Message.Data.Text)
+	assert.Len(t, event.Message.Data.Entities.UserMentions, 1)
+	assert.False(t, event.CreatedAt.IsZero())
+
+	server.SetAuthUser(bob.ID)
+	shown, _, err := client.DirectMessages.EventsShow(event.ID, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, event.ID, shown.ID)
+	assert.Equal(t, event.CreatedAt.Time, shown.CreatedAt.Time)
+	reply, _, err := client.DirectMessages.EventsNew(newMessage(alice.ID, "hello"))
+	assert.Nil(t, err)
+
+	// a third user cannot see the conversation
+	carol := server.AddUser(&twitter.User{ScreenName: "carol"})
+	server.SetAuthUser(carol.ID)
+	_, resp, _ := client.DirectMessages.EventsShow(event.ID, nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	events, _, err := client.DirectMessages.EventsList(nil)
+	assert.Nil(t, err)
+	assert.Empty(t, events.Events)
+
+	server.SetAuthUser(alice.ID)
+	events, _, err = client.DirectMessages.EventsList(&twitter.DirectMessageEventsListParams{Count: 1})
+	assert.Nil(t, err)
+	if assert.Len(t, events.Events, 1) {
+		assert.Equal(t, reply.ID, events.Events[0].ID)
+	}
+	events, _, err = client.DirectMessages.EventsList(&twitter.DirectMessageEventsListParams{Count: 1, Cursor: events.NextCursor})
+	assert.Nil(t, err)
+	if assert.Len(t, events.Events, 1) {
+		assert.Equal(t, event.ID, events.Events[0].ID)
+	}
+	assert.Empty(t, events.NextCursor)
+
+	resp, err = client.DirectMessages.EventsDestroy(event.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_, resp, _ = client.DirectMessages.EventsShow(event.ID, nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestDirectMessages_EventsNewErrors(t *testing.T) {
+	server, client, alice, bob := testClient()
+	defer server.Close()
+
+	_, resp, _ := client.DirectMessages.EventsNew(&twitter.DirectMessageEventsNewParams{})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_, resp, _ = client.DirectMessages.EventsNew(newMessage(1, "hi"))
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	server.SetAuthUser(bob.ID)
+	_, _, err := client.Blocks.Create(&twitter.BlockCreateParams{UserID: alice.ID})
+	assert.Nil(t, err)
+	server.SetAuthUser(alice.ID)
+	_, resp, err = client.DirectMessages.EventsNew(newMessage(bob.ID, "hi"))
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, 349, err.(twitter.APIError).Errors[0].Code)
+}
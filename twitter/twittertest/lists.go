@@ -0,0 +1,400 @@
+package twittertest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dghubble/go-twitter/twitter"
+)
+
+var (
+	errMissingListName = &apiError{http.StatusForbidden, 25, "Missing required parameter: name."}
+	errNotListMember   = &apiError{http.StatusNotFound, 109, "The specified user is not a member of this list."}
+	errNotSubscriber   = &apiError{http.StatusNotFound, 109, "The specified user is not a subscriber of this list."}
+)
+
+// list is a List in the Server's state.
+type list struct {
+	id          int64
+	ownerID     int64
+	name        string
+	slug        string
+	mode        string
+	description string
+	createdAt   time.Time
+	members     []int64
+	subscribers []int64
+}
+
+// renderList returns the List as the API would, relative to the
+// authenticated user.
+func (s *Server) renderList(l *list) twitter.List {
+	owner := s.renderUser(l.ownerID)
+	return twitter.List{
+		ID:              l.id,
+		IDStr:           strconv.FormatInt(l.id, 10),
+		Name:            l.name,
+		Slug:            l.slug,
+		Mode:            l.mode,
+		Description:     l.description,
+		CreatedAt:       twitter.Time{Time: l.createdAt},
+		URI:             "/" + owner.ScreenName + "/lists/" + l.slug,
+		FullName:        "@" + owner.ScreenName + "/" + l.slug,
+		MemberCount:     len(l.members),
+		SubscriberCount: len(l.subscribers),
+		Following:       hasID(l.subscribers, s.authUserID),
+		User:            owner,
+	}
+}
+
+// renderLists returns the Lists as the API would.
+func (s *Server) renderLists(lists []*list) []twitter.List {
+	rendered := make([]twitter.List, 0, len(lists))
+	for _, l := range lists {
+		rendered = append(rendered, s.renderList(l))
+	}
+	return rendered
+}
+
+// findList returns the List identified by the list_id parameter, or by the
+// slug and owner_id or owner_screen_name parameters.
+func (s *Server) findList(form url.Values) (*list, *apiError) {
+	if id := int64Param(form, "list_id"); id != 0 {
+		if l := s.lists[id]; l != nil {
+			return l, nil
+		}
+		return nil, errNotFound
+	}
+	slug := form.Get("slug")
+	ownerID := int64Param(form, "owner_id")
+	if ownerID == 0 {
+		ownerID = s.userIDs[strings.ToLower(form.Get("owner_screen_name"))]
+	}
+	for _, l := range s.ownLists {
+		if l.ownerID == ownerID && l.slug == slug {
+			return l, nil
+		}
+	}
+	return nil, errNotFound
+}
+
+// ownedList returns the List identified by the parameters if it is owned by
+// the authenticated user.
+func (s *Server) ownedList(form url.Values) (*list, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	l, err := s.findList(form)
+	if err != nil {
+		return nil, err
+	}
+	if l.ownerID != userID {
+		return nil, errForbidden
+	}
+	return l, nil
+}
+
+// listsOwnedBy returns the Lists owned by the user, newest first.
+func (s *Server) listsOwnedBy(userID int64) []*list {
+	var lists []*list
+	for i := len(s.ownLists) - 1; i >= 0; i-- {
+		if l := s.ownLists[i]; l.ownerID == userID {
+			lists = append(lists, l)
+		}
+	}
+	return lists
+}
+
+// listsWith returns the Lists whose member or subscriber IDs (as returned
+// by ids) include the user, newest first.
+func (s *Server) listsWith(userID int64, ids func(l *list) []int64) []*list {
+	var lists []*list
+	for i := len(s.ownLists) - 1; i >= 0; i-- {
+		if l := s.ownLists[i]; hasID(ids(l), userID) {
+			lists = append(lists, l)
+		}
+	}
+	return lists
+}
+
+// listsCreate creates a List, whose slug is derived from its name.
+func (s *Server) listsCreate(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.Form.Get("name"))
+	if name == "" {
+		return nil, errMissingListName
+	}
+	mode := req.Form.Get("mode")
+	if mode != "private" {
+		mode = "public"
+	}
+	l := &list{
+		id:          s.nextID(),
+		ownerID:     userID,
+		name:        name,
+		slug:        listSlug(name),
+		mode:        mode,
+		description: req.Form.Get("description"),
+		createdAt:   s.now().UTC(),
+	}
+	s.lists[l.id] = l
+	s.ownLists = append(s.ownLists, l)
+	return s.renderList(l), nil
+}
+
+// listsUpdate updates the List's name (and so its slug), mode, and
+// description, where given.
+func (s *Server) listsUpdate(req *http.Request, id string) (interface{}, *apiError) {
+	l, err := s.ownedList(req.Form)
+	if err != nil {
+		return nil, err
+	}
+	if name := strings.TrimSpace(req.Form.Get("name")); name != "" {
+		l.name = name
+		l.slug = listSlug(name)
+	}
+	if mode := req.Form.Get("mode"); mode == "public" || mode == "private" {
+		l.mode = mode
+	}
+	if _, ok := req.Form["description"]; ok {
+		l.description = req.Form.Get("description")
+	}
+	return s.renderList(l), nil
+}
+
+func (s *Server) listsDestroy(req *http.Request, id string) (interface{}, *apiError) {
+	l, err := s.ownedList(req.Form)
+	if err != nil {
+		return nil, err
+	}
+	delete(s.lists, l.id)
+	kept := s.ownLists[:0]
+	for _, other := range s.ownLists {
+		if other != l {
+			kept = append(kept, other)
+		}
+	}
+	s.ownLists = kept
+	return s.renderList(l), nil
+}
+
+func (s *Server) listsShow(req *http.Request, id string) (interface{}, *apiError) {
+	l, err := s.findList(req.Form)
+	if err != nil {
+		return nil, err
+	}
+	return s.renderList(l), nil
+}
+
+func (s *Server) listsList(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.findUser(req.Form, "user_id", "screen_name")
+	if err != nil {
+		return nil, err
+	}
+	return s.renderLists(s.listsOwnedBy(userID)), nil
+}
+
+func (s *Server) listsOwnerships(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.findUser(req.Form, "user_id", "screen_name")
+	if err != nil {
+		return nil, err
+	}
+	lists := s.listsOwnedBy(userID)
+	start, end, page := cursorPage(req.Form, len(lists), 20, 1000)
+	return &struct {
+		Lists []twitter.List `json:"lists"`
+		cursors
+	}{s.renderLists(lists[start:end]), page}, nil
+}
+
+// listsMemberships returns the Lists the user is a member of, or only those
+// owned by the authenticated user if filter_to_owned_lists is set.
+func (s *Server) listsMemberships(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.findUser(req.Form, "user_id", "screen_name")
+	if err != nil {
+		return nil, err
+	}
+	lists := s.listsWith(userID, func(l *list) []int64 { return l.members })
+	if boolParam(req.Form, "filter_to_owned_lists", false) {
+		owned := lists[:0:0]
+		for _, l := range lists {
+			if l.ownerID == s.authUserID {
+				owned = append(owned, l)
+			}
+		}
+		lists = owned
+	}
+	start, end, page := cursorPage(req.Form, len(lists), 20, 1000)
+	return &struct {
+		Lists []twitter.List `json:"lists"`
+		cursors
+	}{s.renderLists(lists[start:end]), page}, nil
+}
+
+func (s *Server) listsSubscriptions(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.findUser(req.Form, "user_id", "screen_name")
+	if err != nil {
+		return nil, err
+	}
+	lists := s.listsWith(userID, func(l *list) []int64 { return l.subscribers })
+	start, end, page := cursorPage(req.Form, len(lists), 20, 1000)
+	return &struct {
+		Lists []twitter.List `json:"lists"`
+		cursors
+	}{s.renderLists(lists[start:end]), page}, nil
+}
+
+func (s *Server) listsMembers(req *http.Request, id string) (interface{}, *apiError) {
+	l, err := s.findList(req.Form)
+	if err != nil {
+		return nil, err
+	}
+	ids := reversed(l.members)
+	start, end, page := cursorPage(req.Form, len(ids), 20, 5000)
+	return &struct {
+		Users []twitter.User `json:"users"`
+		cursors
+	}{s.renderUsers(ids[start:end]), page}, nil
+}
+
+func (s *Server) listsMembersShow(req *http.Request, id string) (interface{}, *apiError) {
+	l, err := s.findList(req.Form)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := s.findUser(req.Form, "user_id", "screen_name")
+	if err != nil {
+		return nil, err
+	}
+	if !hasID(l.members, userID) {
+		return nil, errNotListMember
+	}
+	return s.renderUser(userID), nil
+}
+
+func (s *Server) listsMembersCreate(req *http.Request, id string) (interface{}, *apiError) {
+	l, err := s.ownedList(req.Form)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := s.findUser(req.Form, "user_id", "screen_name")
+	if err != nil {
+		return nil, err
+	}
+	l.members = addID(l.members, userID)
+	return s.renderList(l), nil
+}
+
+func (s *Server) listsMembersDestroy(req *http.Request, id string) (interface{}, *apiError) {
+	l, err := s.ownedList(req.Form)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := s.findUser(req.Form, "user_id", "screen_name")
+	if err != nil {
+		return nil, err
+	}
+	l.members = removeID(l.members, userID)
+	return s.renderList(l), nil
+}
+
+func (s *Server) listsStatuses(req *http.Request, id string) (interface{}, *apiError) {
+	l, err := s.findList(req.Form)
+	if err != nil {
+		return nil, err
+	}
+	includeRetweets := boolParam(req.Form, "include_rts", true)
+	return s.renderTweets(s.timeline(req.Form, countParam(req.Form, 20, 200), func(t *tweet) bool {
+		return hasID(l.members, t.userID) && (includeRetweets || t.retweetOfID == 0)
+	})), nil
+}
+
+func (s *Server) listsMembersCreateAll(req *http.Request, id string) (interface{}, *apiError) {
+	l, err := s.ownedList(req.Form)
+	if err != nil {
+		return nil, err
+	}
+	for _, userID := range s.usersParam(req.Form) {
+		l.members = addID(l.members, userID)
+	}
+	return s.renderList(l), nil
+}
+
+func (s *Server) listsMembersDestroyAll(req *http.Request, id string) (interface{}, *apiError) {
+	l, err := s.ownedList(req.Form)
+	if err != nil {
+		return nil, err
+	}
+	for _, userID := range s.usersParam(req.Form) {
+		l.members = removeID(l.members, userID)
+	}
+	return s.renderList(l), nil
+}
+
+func (s *Server) listsSubscribers(req *http.Request, id string) (interface{}, *apiError) {
+	l, err := s.findList(req.Form)
+	if err != nil {
+		return nil, err
+	}
+	ids := reversed(l.subscribers)
+	start, end, page := cursorPage(req.Form, len(ids), 20, 5000)
+	return &struct {
+		Users []twitter.User `json:"users"`
+		cursors
+	}{s.renderUsers(ids[start:end]), page}, nil
+}
+
+func (s *Server) listsSubscribersShow(req *http.Request, id string) (interface{}, *apiError) {
+	l, err := s.findList(req.Form)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := s.findUser(req.Form, "user_id", "screen_name")
+	if err != nil {
+		return nil, err
+	}
+	if !hasID(l.subscribers, userID) {
+		return nil, errNotSubscriber
+	}
+	return s.renderUser(userID), nil
+}
+
+// listsSubscribersCreate subscribes the authenticated user to the List.
+func (s *Server) listsSubscribersCreate(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	l, err := s.findList(req.Form)
+	if err != nil {
+		return nil, err
+	}
+	l.subscribers = addID(l.subscribers, userID)
+	return s.renderList(l), nil
+}
+
+// listsSubscribersDestroy unsubscribes the authenticated user from the List.
+func (s *Server) listsSubscribersDestroy(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	l, err := s.findList(req.Form)
+	if err != nil {
+		return nil, err
+	}
+	l.subscribers = removeID(l.subscribers, userID)
+	return s.renderList(l), nil
+}
+
+// listSlug returns the slug of a List with the name.
+func listSlug(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), "-"))
+}
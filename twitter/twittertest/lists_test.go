@@ -0,0 +1,165 @@
+package twittertest
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/dghubble/go-twitter/twitter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLists(t *testing.T) {
+	server, client, alice, bob := testClient()
+	defer server.Close()
+
+	list, _, err := client.Lists.Create("Go Gophers", &twitter.ListsCreateParams{Description: "gophers"})
+	assert.Nil(t, err)
+	assert.Equal(t, "go-gophers", list.Slug)
+	assert.Equal(t, "@alice/go-gophers", list.FullName)
+	assert.Equal(t, "public", list.Mode)
+	assert.Equal(t, alice.ID, list.User.ID)
+
+	_, err = client.Lists.MembersCreate(&twitter.ListsMembersCreateParams{ListID: list.ID, UserID: bob.ID})
+	assert.Nil(t, err)
+	shown, _, err := client.Lists.Show(&twitter.ListsShowParams{Slug: "go-gophers", OwnerScreenName: "alice"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, shown.MemberCount)
+
+	members, _, err := client.Lists.Members(&twitter.ListsMembersParams{ListID: list.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{bob.ID}, userIDs(members.Users))
+	member, _, err := client.Lists.MembersShow(&twitter.ListsMembersShowParams{ListID: list.ID, UserID: bob.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, member.ListedCount)
+	_, resp, _ := client.Lists.MembersShow(&twitter.ListsMembersShowParams{ListID: list.ID, UserID: alice.ID})
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	tweet := server.AddTweet(bob.ID, "on the list")
+	server.AddTweet(alice.ID, "not on the list")
+	tweets, _, err := client.Lists.Statuses(&twitter.ListsStatusesParams{ListID: list.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{tweet.ID}, tweetIDs(tweets))
+
+	lists, _, err := client.Lists.List(&twitter.ListsListParams{ScreenName: "alice"})
+	assert.Nil(t, err)
+	assert.Len(t, lists, 1)
+	ownerships, _, err := client.Lists.Ownerships(&twitter.ListsOwnershipsParams{UserID: alice.ID})
+	assert.Nil(t, err)
+	assert.Len(t, ownerships.Lists, 1)
+
+	// only the owner may change a list
+	server.SetAuthUser(bob.ID)
+	resp, _ = client.Lists.MembersDestroy(&twitter.ListsMembersDestroyParams{ListID: list.ID, UserID: bob.ID})
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	_, resp, _ = client.Lists.Destroy(&twitter.ListsDestroyParams{ListID: list.ID})
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	server.SetAuthUser(alice.ID)
+	_, err = client.Lists.MembersDestroy(&twitter.ListsMembersDestroyParams{ListID: list.ID, UserID: bob.ID})
+	assert.Nil(t, err)
+	_, _, err = client.Lists.Destroy(&twitter.ListsDestroyParams{ListID: list.ID})
+	assert.Nil(t, err)
+	_, resp, _ = client.Lists.Show(&twitter.ListsShowParams{ListID: list.ID})
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestLists_CreateErrors(t *testing.T) {
+	server, client, _, _ := testClient()
+	defer server.Close()
+	_, resp, _ := client.Lists.Create(" ", nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestLists_MembersAll(t *testing.T) {
+	server, client, alice, bob := testClient()
+	defer server.Close()
+	carol := server.AddUser(&twitter.User{ScreenName: "carol"})
+
+	list, _, err := client.Lists.Create("gophers", nil)
+	assert.Nil(t, err)
+	_, err = client.Lists.MembersCreateAll(&twitter.ListsMembersCreateAllParams{
+		ListID:     list.ID,
+		UserID:     strconv.FormatInt(bob.ID, 10),
+		ScreenName: "carol,nobody",
+	})
+	assert.Nil(t, err)
+	members, _, err := client.Lists.Members(&twitter.ListsMembersParams{ListID: list.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{carol.ID, bob.ID}, userIDs(members.Users))
+
+	memberships, _, err := client.Lists.Memberships(&twitter.ListsMembershipsParams{UserID: bob.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{list.ID}, listIDs(memberships.Lists))
+	server.SetAuthUser(bob.ID)
+	memberships, _, err = client.Lists.Memberships(&twitter.ListsMembershipsParams{UserID: bob.ID, FilterToOwnedLists: twitter.Bool(true)})
+	assert.Nil(t, err)
+	assert.Empty(t, memberships.Lists)
+
+	server.SetAuthUser(alice.ID)
+	_, err = client.Lists.MembersDestroyAll(&twitter.ListsMembersDestroyAllParams{ListID: list.ID, ScreenName: "bob,carol"})
+	assert.Nil(t, err)
+	members, _, err = client.Lists.Members(&twitter.ListsMembersParams{ListID: list.ID})
+	assert.Nil(t, err)
+	assert.Empty(t, members.Users)
+}
+
+func TestLists_Subscribers(t *testing.T) {
+	server, client, alice, bob := testClient()
+	defer server.Close()
+
+	list, _, err := client.Lists.Create("gophers", nil)
+	assert.Nil(t, err)
+	server.SetAuthUser(bob.ID)
+	subscribed, _, err := client.Lists.SubscribersCreate(&twitter.ListsSubscribersCreateParams{Slug: "gophers", OwnerID: alice.ID})
+	assert.Nil(t, err)
+	assert.True(t, subscribed.Following)
+	assert.Equal(t, 1, subscribed.SubscriberCount)
+
+	subscribers, _, err := client.Lists.Subscribers(&twitter.ListsSubscribersParams{ListID: list.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{bob.ID}, userIDs(subscribers.Users))
+	subscriber, _, err := client.Lists.SubscribersShow(&twitter.ListsSubscribersShowParams{ListID: list.ID, UserID: bob.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, bob.ID, subscriber.ID)
+	_, resp, _ := client.Lists.SubscribersShow(&twitter.ListsSubscribersShowParams{ListID: list.ID, UserID: alice.ID})
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	subscriptions, _, err := client.Lists.Subscriptions(&twitter.ListsSubscriptionsParams{ScreenName: "bob"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{list.ID}, listIDs(subscriptions.Lists))
+
+	_, err = client.Lists.SubscribersDestroy(&twitter.ListsSubscribersDestroyParams{ListID: list.ID})
+	assert.Nil(t, err)
+	subscriptions, _, err = client.Lists.Subscriptions(nil)
+	assert.Nil(t, err)
+	assert.Empty(t, subscriptions.Lists)
+}
+
+func TestLists_Update(t *testing.T) {
+	server, client, _, bob := testClient()
+	defer server.Close()
+
+	list, _, err := client.Lists.Create("gophers", &twitter.ListsCreateParams{Description: "gophers"})
+	assert.Nil(t, err)
+	_, err = client.Lists.Update(&twitter.ListsUpdateParams{ListID: list.ID, Name: "Go Gophers", Mode: "private"})
+	assert.Nil(t, err)
+	updated, _, err := client.Lists.Show(&twitter.ListsShowParams{ListID: list.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, "Go Gophers", updated.Name)
+	assert.Equal(t, "go-gophers", updated.Slug)
+	assert.Equal(t, "private", updated.Mode)
+	assert.Equal(t, "gophers", updated.Description)
+
+	// only the owner may update a list
+	server.SetAuthUser(bob.ID)
+	resp, _ := client.Lists.Update(&twitter.ListsUpdateParams{ListID: list.ID, Name: "mine"})
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func listIDs(lists []twitter.List) []int64 {
+	var ids []int64
+	for _, list := range lists {
+		ids = append(ids, list.ID)
+	}
+	return ids
+}
@@ -0,0 +1,267 @@
+package twittertest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// apiError is an error response.
+type apiError struct {
+	status  int
+	code    int
+	message string
+}
+
+var (
+	errNotFound      = &apiError{http.StatusNotFound, 34, "Sorry, that page does not exist."}
+	errUnauthorized  = &apiError{http.StatusUnauthorized, 32, "Could not authenticate you."}
+	errForbidden     = &apiError{http.StatusForbidden, 220, "Your credentials do not allow access to this resource."}
+	errRateLimit     = &apiError{http.StatusTooManyRequests, 88, "Rate limit exceeded"}
+	errUserNotFound  = &apiError{http.StatusNotFound, 50, "User not found."}
+	errNoUserMatches = &apiError{http.StatusNotFound, 17, "No user matches for specified terms."}
+	errNoStatus      = &apiError{http.StatusNotFound, 144, "No status found with that ID."}
+)
+
+// writeError writes an error response in the API's format.
+func writeError(w http.ResponseWriter, err *apiError) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(err.status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]interface{}{
+			{"code": err.code, "message": err.message},
+		},
+	})
+}
+
+// handler handles a request to a route, with the route's id path segment
+// (if any). Handlers return the value to encode as the JSON response, nil
+// for an empty response, or an error.
+type handler func(s *Server, req *http.Request, id string) (interface{}, *apiError)
+
+// route is an endpoint of the API.
+type route struct {
+	method string
+	// path, which may contain an ":id" segment
+	path string
+	// requests per rate limit window, or 0 for endpoints without rate limits
+	limit  int
+	handle handler
+}
+
+// routes are the endpoints served by a Server, with the API's rate limits.
+var routes = []route{
+	{"GET", "/1.1/account/verify_credentials.json", 75, (*Server).accountVerifyCredentials},
+
+	{"POST", "/1.1/blocks/create.json", 0, (*Server).blocksCreate},
+	{"POST", "/1.1/blocks/destroy.json", 0, (*Server).blocksDestroy},
+
+	{"POST", "/1.1/direct_messages/events/new.json", 0, (*Server).directMessagesEventsNew},
+	{"GET", "/1.1/direct_messages/events/show.json", 15, (*Server).directMessagesEventsShow},
+	{"GET", "/1.1/direct_messages/events/list.json", 15, (*Server).directMessagesEventsList},
+	{"DELETE", "/1.1/direct_messages/events/destroy.json", 0, (*Server).directMessagesEventsDestroy},
+
+	{"GET", "/1.1/favorites/list.json", 75, (*Server).favoritesList},
+	{"POST", "/1.1/favorites/create.json", 0, (*Server).favoritesCreate},
+	{"POST", "/1.1/favorites/destroy.json", 0, (*Server).favoritesDestroy},
+
+	{"GET", "/1.1/followers/ids.json", 15, (*Server).followersIDs},
+	{"GET", "/1.1/followers/list.json", 15, (*Server).followersList},
+	{"GET", "/1.1/friends/ids.json", 15, (*Server).friendsIDs},
+	{"GET", "/1.1/friends/list.json", 15, (*Server).friendsList},
+	{"POST", "/1.1/friendships/create.json", 0, (*Server).friendshipsCreate},
+	{"POST", "/1.1/friendships/destroy.json", 0, (*Server).friendshipsDestroy},
+	{"GET", "/1.1/friendships/show.json", 180, (*Server).friendshipsShow},
+	{"GET", "/1.1/friendships/lookup.json", 15, (*Server).friendshipsLookup},
+	{"GET", "/1.1/friendships/incoming.json", 15, (*Server).friendshipsIncoming},
+	{"GET", "/1.1/friendships/outgoing.json", 15, (*Server).friendshipsOutgoing},
+
+	{"POST", "/1.1/lists/create.json", 0, (*Server).listsCreate},
+	{"POST", "/1.1/lists/update.json", 0, (*Server).listsUpdate},
+	{"POST", "/1.1/lists/destroy.json", 0, (*Server).listsDestroy},
+	{"GET", "/1.1/lists/show.json", 75, (*Server).listsShow},
+	{"GET", "/1.1/lists/list.json", 15, (*Server).listsList},
+	{"GET", "/1.1/lists/ownerships.json", 15, (*Server).listsOwnerships},
+	{"GET", "/1.1/lists/memberships.json", 75, (*Server).listsMemberships},
+	{"GET", "/1.1/lists/subscriptions.json", 15, (*Server).listsSubscriptions},
+	{"GET", "/1.1/lists/members.json", 900, (*Server).listsMembers},
+	{"GET", "/1.1/lists/members/show.json", 15, (*Server).listsMembersShow},
+	{"POST", "/1.1/lists/members/create.json", 0, (*Server).listsMembersCreate},
+	{"POST", "/1.1/lists/members/destroy.json", 0, (*Server).listsMembersDestroy},
+	{"POST", "/1.1/lists/members/create_all.json", 0, (*Server).listsMembersCreateAll},
+	{"POST", "/1.1/lists/members/destroy_all.json", 0, (*Server).listsMembersDestroyAll},
+	{"GET", "/1.1/lists/subscribers.json", 180, (*Server).listsSubscribers},
+	{"GET", "/1.1/lists/subscribers/show.json", 15, (*Server).listsSubscribersShow},
+	{"POST", "/1.1/lists/subscribers/create.json", 0, (*Server).listsSubscribersCreate},
+	{"POST", "/1.1/lists/subscribers/destroy.json", 0, (*Server).listsSubscribersDestroy},
+	{"GET", "/1.1/lists/statuses.json", 900, (*Server).listsStatuses},
+
+	{"GET", "/1.1/search/tweets.json", 180, (*Server).searchTweets},
+
+	{"GET", "/1.1/statuses/show.json", 900, (*Server).statusesShow},
+	{"GET", "/1.1/statuses/lookup.json", 900, (*Server).statusesLookup},
+	{"POST", "/1.1/statuses/update.json", 0, (*Server).statusesUpdate},
+	{"POST", "/1.1/statuses/destroy/:id.json", 0, (*Server).statusesDestroy},
+	{"POST", "/1.1/statuses/retweet/:id.json", 0, (*Server).statusesRetweet},
+	{"POST", "/1.1/statuses/unretweet/:id.json", 0, (*Server).statusesUnretweet},
+	{"GET", "/1.1/statuses/retweets/:id.json", 75, (*Server).statusesRetweets},
+	{"GET", "/1.1/statuses/retweeters/ids.json", 75, (*Server).statusesRetweeters},
+	{"GET", "/1.1/statuses/oembed.json", 180, (*Server).statusesOEmbed},
+	{"GET", "/1.1/statuses/user_timeline.json", 900, (*Server).statusesUserTimeline},
+	{"GET", "/1.1/statuses/home_timeline.json", 15, (*Server).statusesHomeTimeline},
+	{"GET", "/1.1/statuses/mentions_timeline.json", 75, (*Server).statusesMentionsTimeline},
+	{"GET", "/1.1/statuses/retweets_of_me.json", 75, (*Server).statusesRetweetsOfMe},
+
+	{"GET", "/1.1/users/show.json", 900, (*Server).usersShow},
+	{"GET", "/1.1/users/lookup.json", 900, (*Server).usersLookup},
+	{"GET", "/1.1/users/search.json", 900, (*Server).usersSearch},
+}
+
+// findRoute returns the route matching the request method and path, and the
+// path's id segment, if any.
+func findRoute(method, path string) (*route, string) {
+	for i := range routes {
+		r := &routes[i]
+		if r.method != method {
+			continue
+		}
+		if id, ok := matchPath(r.path, path); ok {
+			return r, id
+		}
+	}
+	return nil, ""
+}
+
+// matchPath reports whether the path matches the pattern and returns the
+// value of the pattern's ":id" segment, if any.
+func matchPath(pattern, path string) (string, bool) {
+	i := strings.Index(pattern, ":id")
+	if i < 0 {
+		return "", pattern == path
+	}
+	prefix, suffix := pattern[:i], pattern[i+len(":id"):]
+	if !strings.HasPrefix(path, prefix) || !strings.HasSuffix(path, suffix) || len(path) <= len(prefix)+len(suffix) {
+		return "", false
+	}
+	id := path[len(prefix) : len(path)-len(suffix)]
+	return id, !strings.Contains(id, "/")
+}
+
+// int64Param returns the integer value of a parameter, or 0.
+func int64Param(form url.Values, key string) int64 {
+	value, _ := strconv.ParseInt(form.Get(key), 10, 64)
+	return value
+}
+
+// int64sParam returns the integer values of a comma separated parameter.
+func int64sParam(form url.Values, key string) []int64 {
+	var values []int64
+	for _, field := range strings.Split(form.Get(key), ",") {
+		if value, err := strconv.ParseInt(strings.TrimSpace(field), 10, 64); err == nil {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// boolParam returns the boolean value of a parameter, or the default.
+func boolParam(form url.Values, key string, def bool) bool {
+	value, err := strconv.ParseBool(form.Get(key))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// countParam returns the count parameter, or the default, limited to max.
+func countParam(form url.Values, def, max int) int {
+	count, err := strconv.Atoi(form.Get("count"))
+	if err != nil || count <= 0 {
+		count = def
+	}
+	if count > max {
+		count = max
+	}
+	return count
+}
+
+// cursors are the cursors of a page of a cursored collection.
+type cursors struct {
+	NextCursor        int64  `json:"next_cursor"`
+	NextCursorStr     string `json:"next_cursor_str"`
+	PreviousCursor    int64  `json:"previous_cursor"`
+	PreviousCursorStr string `json:"previous_cursor_str"`
+}
+
+// cursorPage returns the bounds and cursors of the page of a collection of
+// n items requested by the cursor and count parameters. Cursors are offsets
+// into the collection: positive cursors are offsets, negative cursors are
+// offsets plus one (so the first page is -1), and 0 ends the collection.
+func cursorPage(form url.Values, n, defaultCount, maxCount int) (start, end int, page cursors) {
+	count := countParam(form, defaultCount, maxCount)
+	cursor := int64Param(form, "cursor")
+	if cursor < 0 {
+		cursor = -cursor - 1
+	}
+	start = int(cursor)
+	if start > n {
+		start = n
+	}
+	end = start + count
+	if end > n {
+		end = n
+	}
+	if end < n {
+		page.NextCursor = int64(end)
+	}
+	if start > 0 {
+		previous := start - count
+		if previous < 0 {
+			previous = 0
+		}
+		page.PreviousCursor = -int64(previous) - 1
+	}
+	page.NextCursorStr = strconv.FormatInt(page.NextCursor, 10)
+	page.PreviousCursorStr = strconv.FormatInt(page.PreviousCursor, 10)
+	return start, end, page
+}
+
+// hasID reports whether the IDs contain the ID.
+func hasID(ids []int64, id int64) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
+
+// addID appends the ID to the IDs, unless they already contain it.
+func addID(ids []int64, id int64) []int64 {
+	if hasID(ids, id) {
+		return ids
+	}
+	return append(ids, id)
+}
+
+// removeID returns the IDs without the ID.
+func removeID(ids []int64, id int64) []int64 {
+	kept := ids[:0]
+	for _, other := range ids {
+		if other != id {
+			kept = append(kept, other)
+		}
+	}
+	return kept
+}
+
+// reversed returns a reversed copy of the IDs.
+func reversed(ids []int64) []int64 {
+	reversed := make([]int64, len(ids))
+	for i, id := range ids {
+		reversed[len(ids)-1-i] = id
+	}
+	return reversed
+}
@@ -0,0 +1,431 @@
+/*
+Package twittertest provides an in-memory fake of the Twitter REST API v1.1
+for testing code which uses the twitter package.
+
+A Server keeps consistent state across requests: Tweets posted with
+Statuses.Update appear in timelines and search results, follows show up in
+friends and followers (with cursors), and so on. Responses carry rate limit
+headers and exhausting a limit returns a 429 error, as the API does. Faults
+inject error responses into matching requests.
+
+	server := twittertest.NewServer()
+	defer server.Close()
+	alice := server.AddUser(&twitter.User{ScreenName: "alice"})
+	server.SetAuthUser(alice.ID)
+
+	client := twitter.NewClient(server.Client())
+	tweet, _, err := client.Statuses.Update("just setting up my twttr", nil)
+
+The Server covers the statuses, timelines, search, favorites, users, account,
+friendships, friends, followers, blocks, lists, and Direct Message events
+endpoints. Following a protected user sends a follow request, which
+AcceptFollowRequest accepts. These endpoints the twitter package supports
+are not served and respond with a 404 error:
+
+  - account/update_profile
+  - application/rate_limit_status
+  - the legacy direct_messages endpoints
+  - trends
+  - premium search (tweets/search)
+  - streams (see StreamServer)
+*/
+package twittertest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dghubble/go-twitter/twitter"
+)
+
+// length of a rate limit window
+const rateLimitWindow = 15 * time.Minute
+
+// A Fault is an error response injected into matching requests.
+type Fault struct {
+	// request method to match, or "" to match any method
+	Method string
+	// request path to match, either exactly (e.g. "/1.1/statuses/destroy/20.json")
+	// or as an endpoint (e.g. "/1.1/statuses/destroy/:id.json"), or "" to
+	// match any path
+	Path string
+	// HTTP status code of the response (default 500)
+	Status int
+	// Twitter error code and message of the response
+	Code    int
+	Message string
+	// number of matching requests to fail, or 0 to fail every matching request
+	Times int
+}
+
+// Server is a fake Twitter REST API server backed by in-memory state. Its
+// methods are safe for concurrent use.
+type Server struct {
+	// base URL of the server, of the form http://ipaddr:port
+	URL    string
+	server *httptest.Server
+
+	mu         sync.Mutex
+	now        func() time.Time
+	lastID     int64
+	authUserID int64
+	users      map[int64]*twitter.User
+	userIDs    map[string]int64
+	tweets     map[int64]*tweet
+	statuses   []*tweet
+	following  map[int64][]int64
+	requests   map[int64][]int64
+	blocking   map[int64][]int64
+	favorites  map[int64][]int64
+	lists      map[int64]*list
+	ownLists   []*list
+	messages   []*twitter.DirectMessageEvent
+	limits     map[string]int
+	windows    map[string]*rateLimit
+	faults     []*Fault
+}
+
+// NewServer starts and returns a new Server with no users. The caller must
+// Close the Server when finished.
+func NewServer() *Server {
+	s := &Server{
+		now:       time.Now,
+		users:     make(map[int64]*twitter.User),
+		userIDs:   make(map[string]int64),
+		tweets:    make(map[int64]*tweet),
+		following: make(map[int64][]int64),
+		requests:  make(map[int64][]int64),
+		blocking:  make(map[int64][]int64),
+		favorites: make(map[int64][]int64),
+		lists:     make(map[int64]*list),
+		limits:    make(map[string]int),
+		windows:   make(map[string]*rateLimit),
+	}
+	s.server = httptest.NewServer(s)
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down the Server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client returns an http.Client which sends requests for any host (e.g.
+// https://api.twitter.com) to the Server. Pass it to twitter.NewClient.
+func (s *Server) Client() *http.Client {
+	target, _ := url.Parse(s.URL)
+	return &http.Client{Transport: &rewriteTransport{target: target, transport: s.server.Client().Transport}}
+}
+
+// AddUser adds a user and returns it as the API would. IDs and creation
+// times are assigned to users without them. Screen names should be unique.
+func (s *Server) AddUser(user *twitter.User) *twitter.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := *user
+	if u.ID == 0 {
+		u.ID = s.nextID()
+	}
+	u.IDStr = strconv.FormatInt(u.ID, 10)
+	if u.ScreenName == "" {
+		u.ScreenName = "user" + u.IDStr
+	}
+	if u.Name == "" {
+		u.Name = u.ScreenName
+	}
+	if u.CreatedAt.IsZero() {
+		u.CreatedAt = twitter.Time{Time: s.now().UTC()}
+	}
+	u.Raw = nil
+	s.users[u.ID] = &u
+	s.userIDs[strings.ToLower(u.ScreenName)] = u.ID
+	return s.renderUser(u.ID)
+}
+
+// SetAuthUser sets the user on whose behalf requests are made, as if the
+// Client were authorized with that user's access token. Without an
+// authenticated user, endpoints which require a user context respond with a
+// 401 error.
+func (s *Server) SetAuthUser(userID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mustUser(userID)
+	s.authUserID = userID
+}
+
+// AddTweet posts a Tweet by the given user and returns it as the API would.
+// It panics if the user does not exist.
+func (s *Server) AddTweet(userID int64, text string) *twitter.Tweet {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mustUser(userID)
+	t := s.addTweet(&tweet{userID: userID, text: text})
+	rendered := s.renderTweet(t)
+	return &rendered
+}
+
+// Follow makes the source user follow the target user. It panics if either
+// user does not exist.
+func (s *Server) Follow(sourceID, targetID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mustUser(sourceID)
+	s.mustUser(targetID)
+	s.following[sourceID] = addID(s.following[sourceID], targetID)
+}
+
+// AcceptFollowRequest accepts the source user's request to follow the
+// protected target user, as if the target user approved it. It panics if
+// the source user has not requested to follow the target user.
+func (s *Server) AcceptFollowRequest(sourceID, targetID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !hasID(s.requests[sourceID], targetID) {
+		panic(fmt.Sprintf("twittertest: no follow request from %d to %d", sourceID, targetID))
+	}
+	s.requests[sourceID] = removeID(s.requests[sourceID], targetID)
+	s.following[sourceID] = addID(s.following[sourceID], targetID)
+}
+
+// SetRateLimit sets the number of requests per 15 minute window allowed to
+// an endpoint (e.g. "/1.1/statuses/home_timeline.json") and starts a new
+// window.
+func (s *Server) SetRateLimit(path string, limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limits[path] = limit
+	delete(s.windows, path)
+}
+
+// ResetRateLimits starts a new rate limit window for every endpoint.
+func (s *Server) ResetRateLimits() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.windows = make(map[string]*rateLimit)
+}
+
+// Inject adds a Fault, which responds to matching requests with an error
+// until it has failed its number of requests. Faults are matched in the
+// order they were added.
+func (s *Server) Inject(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+// ClearFaults removes all Faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// ServeHTTP serves Twitter API requests from the Server's state.
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	route, id := findRoute(req.Method, req.URL.Path)
+	if fault := s.fault(req, route); fault != nil {
+		writeError(w, fault)
+		return
+	}
+	if route == nil {
+		writeError(w, errNotFound)
+		return
+	}
+	if route.limit > 0 {
+		window := s.rateLimit(route)
+		header := w.Header()
+		header.Set("x-rate-limit-limit", strconv.Itoa(window.limit))
+		header.Set("x-rate-limit-remaining", strconv.Itoa(window.remaining))
+		header.Set("x-rate-limit-reset", strconv.FormatInt(window.reset.Unix(), 10))
+		if window.exhausted {
+			writeError(w, errRateLimit)
+			return
+		}
+	}
+	if err := req.ParseForm(); err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, 44, err.Error()})
+		return
+	}
+	value, apiErr := route.handle(s, req, id)
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	if value == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(value)
+}
+
+// fault returns the error response of the first Fault matching the request,
+// if any.
+func (s *Server) fault(req *http.Request, route *route) *apiError {
+	for i, fault := range s.faults {
+		if fault.Method != "" && fault.Method != req.Method {
+			continue
+		}
+		if fault.Path != "" && fault.Path != req.URL.Path && (route == nil || fault.Path != route.path) {
+			continue
+		}
+		if fault.Times > 0 {
+			if fault.Times--; fault.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		status := fault.Status
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		return &apiError{status, fault.Code, fault.Message}
+	}
+	return nil
+}
+
+// rateLimit is an endpoint's rate limit window.
+type rateLimit struct {
+	limit     int
+	remaining int
+	reset     time.Time
+	exhausted bool
+}
+
+// rateLimit counts a request against the route's rate limit window and
+// returns the window.
+func (s *Server) rateLimit(route *route) *rateLimit {
+	now := s.now()
+	window := s.windows[route.path]
+	if window == nil || !now.Before(window.reset) {
+		limit, ok := s.limits[route.path]
+		if !ok {
+			limit = route.limit
+		}
+		window = &rateLimit{limit: limit, remaining: limit, reset: now.Add(rateLimitWindow)}
+		s.windows[route.path] = window
+	}
+	window.exhausted = window.remaining <= 0
+	if !window.exhausted {
+		window.remaining--
+	}
+	return window
+}
+
+// nextID returns a new Snowflake ID for the current time.
+func (s *Server) nextID() int64 {
+	id := twitter.MinSnowflakeID(s.now())
+	if id <= s.lastID {
+		id = s.lastID + 1
+	}
+	s.lastID = id
+	return id
+}
+
+// mustUser panics if the user does not exist.
+func (s *Server) mustUser(id int64) {
+	if s.users[id] == nil {
+		panic(fmt.Sprintf("twittertest: unknown user %d", id))
+	}
+}
+
+// authUser returns the authenticated user's ID or an error if requests are
+// not made in a user context.
+func (s *Server) authUser() (int64, *apiError) {
+	if s.authUserID == 0 {
+		return 0, errUnauthorized
+	}
+	return s.authUserID, nil
+}
+
+// findUser returns the ID of the user identified by the given id or screen
+// name parameters, or the authenticated user if neither is set.
+func (s *Server) findUser(form url.Values, idKey, nameKey string) (int64, *apiError) {
+	if id := int64Param(form, idKey); id != 0 {
+		if s.users[id] == nil {
+			return 0, errUserNotFound
+		}
+		return id, nil
+	}
+	if name := form.Get(nameKey); name != "" {
+		id, ok := s.userIDs[strings.ToLower(name)]
+		if !ok {
+			return 0, errUserNotFound
+		}
+		return id, nil
+	}
+	return s.authUser()
+}
+
+// renderUser returns the user as the API would, relative to the
+// authenticated user.
+func (s *Server) renderUser(id int64) *twitter.User {
+	user := *s.users[id]
+	user.FriendsCount = len(s.following[id])
+	user.FollowersCount = len(s.followers(id))
+	user.FavouritesCount = len(s.favorites[id])
+	user.StatusesCount = 0
+	for _, t := range s.statuses {
+		if t.userID == id {
+			user.StatusesCount++
+		}
+	}
+	user.ListedCount = 0
+	for _, l := range s.ownLists {
+		if hasID(l.members, id) {
+			user.ListedCount++
+		}
+	}
+	user.Following = hasID(s.following[s.authUserID], id)
+	user.FollowRequestSent = hasID(s.requests[s.authUserID], id)
+	return &user
+}
+
+// renderUsers returns the users as the API would.
+func (s *Server) renderUsers(ids []int64) []twitter.User {
+	users := make([]twitter.User, 0, len(ids))
+	for _, id := range ids {
+		users = append(users, *s.renderUser(id))
+	}
+	return users
+}
+
+// followers returns the IDs of the users following the user, most recent
+// first.
+func (s *Server) followers(id int64) []int64 {
+	var followers []int64
+	for follower, friends := range s.following {
+		if hasID(friends, id) {
+			followers = append(followers, follower)
+		}
+	}
+	// newest IDs first, since follow times are not kept
+	sort.Slice(followers, func(i, j int) bool { return followers[i] > followers[j] })
+	return followers
+}
+
+// rewriteTransport sends requests to the target URL.
+type rewriteTransport struct {
+	target    *url.URL
+	transport http.RoundTripper
+}
+
+// RoundTrip rewrites the request URL's scheme and host to those of the
+// target and calls through to the transport.
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = t.target.Host
+	return t.transport.RoundTrip(req)
+}
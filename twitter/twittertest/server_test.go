@@ -0,0 +1,190 @@
+package twittertest
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dghubble/go-twitter/twitter"
+	"github.com/stretchr/testify/assert"
+)
+
+// testClient returns a Server with users alice (authenticated) and bob, and
+// a Client of the Server. The caller must close the Server.
+func testClient() (*Server, *twitter.Client, *twitter.User, *twitter.User) {
+	server := NewServer()
+	alice := server.AddUser(&twitter.User{ScreenName: "alice", Name: "Alice"})
+	bob := server.AddUser(&twitter.User{ScreenName: "bob", Name: "Bob"})
+	server.SetAuthUser(alice.ID)
+	return server, twitter.NewClient(server.Client()), alice, bob
+}
+
+func TestServer_AddUser(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	user := server.AddUser(&twitter.User{ScreenName: "gopher"})
+	assert.NotZero(t, user.ID)
+	assert.Equal(t, "gopher", user.Name)
+	assert.False(t, user.CreatedAt.IsZero())
+
+	explicit := server.AddUser(&twitter.User{ID: 12, ScreenName: "jack"})
+	assert.Equal(t, int64(12), explicit.ID)
+	assert.Equal(t, "12", explicit.IDStr)
+
+	client := twitter.NewClient(server.Client())
+	shown, _, err := client.Users.Show(&twitter.UserShowParams{ScreenName: "JACK"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(12), shown.ID)
+}
+
+func TestServer_SetAuthUserPanics(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	assert.Panics(t, func() { server.SetAuthUser(42) })
+	assert.Panics(t, func() { server.AddTweet(42, "hello") })
+}
+
+func TestServer_Unauthorized(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := twitter.NewClient(server.Client())
+	_, resp, err := client.Accounts.VerifyCredentials(nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, twitter.APIError{Errors: []twitter.ErrorDetail{{Code: 32, Message: "Could not authenticate you."}}}, err)
+}
+
+func TestServer_NotFound(t *testing.T) {
+	server, client, _, _ := testClient()
+	defer server.Close()
+	_, resp, err := client.Trends.Available()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	if assert.IsType(t, twitter.APIError{}, err) {
+		assert.Equal(t, 34, err.(twitter.APIError).Errors[0].Code)
+	}
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	server, client, _, _ := testClient()
+	defer server.Close()
+	server.SetRateLimit("/1.1/statuses/home_timeline.json", 2)
+
+	_, resp, err := client.Timelines.HomeTimeline(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "2", resp.Header.Get("x-rate-limit-limit"))
+	assert.Equal(t, "1", resp.Header.Get("x-rate-limit-remaining"))
+	assert.NotEmpty(t, resp.Header.Get("x-rate-limit-reset"))
+	_, resp, err = client.Timelines.HomeTimeline(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "0", resp.Header.Get("x-rate-limit-remaining"))
+
+	_, resp, err = client.Timelines.HomeTimeline(nil)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	if assert.IsType(t, twitter.APIError{}, err) {
+		assert.Equal(t, 88, err.(twitter.APIError).Errors[0].Code)
+	}
+	// other endpoints have their own windows
+	_, resp, err = client.Timelines.UserTimeline(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "899", resp.Header.Get("x-rate-limit-remaining"))
+
+	server.ResetRateLimits()
+	_, _, err = client.Timelines.HomeTimeline(nil)
+	assert.Nil(t, err)
+}
+
+func TestServer_RateLimitWindow(t *testing.T) {
+	server, client, _, _ := testClient()
+	defer server.Close()
+	now := time.Now()
+	server.now = func() time.Time { return now }
+	server.SetRateLimit("/1.1/statuses/show.json", 1)
+	tweet := server.AddTweet(server.authUserID, "hello")
+
+	_, _, err := client.Statuses.Show(tweet.ID, nil)
+	assert.Nil(t, err)
+	_, _, err = client.Statuses.Show(tweet.ID, nil)
+	assert.NotNil(t, err)
+	now = now.Add(rateLimitWindow)
+	_, _, err = client.Statuses.Show(tweet.ID, nil)
+	assert.Nil(t, err)
+}
+
+func TestServer_Inject(t *testing.T) {
+	server, client, _, _ := testClient()
+	defer server.Close()
+	server.Inject(Fault{
+		Method:  "POST",
+		Path:    "/1.1/statuses/update.json",
+		Status:  http.StatusServiceUnavailable,
+		Code:    130,
+		Message: "Over capacity",
+		Times:   2,
+	})
+
+	for i := 0; i < 2; i++ {
+		_, resp, err := client.Statuses.Update("hello", nil)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, twitter.APIError{Errors: []twitter.ErrorDetail{{Code: 130, Message: "Over capacity"}}}, err)
+	}
+	// fault is exhausted
+	tweet, _, err := client.Statuses.Update("hello", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", tweet.Text)
+}
+
+func TestServer_InjectEndpoint(t *testing.T) {
+	server, client, _, _ := testClient()
+	defer server.Close()
+	tweet := server.AddTweet(server.authUserID, "hello")
+	server.Inject(Fault{Path: "/1.1/statuses/destroy/:id.json"})
+
+	_, resp, err := client.Statuses.Destroy(tweet.ID, nil)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.NotNil(t, err)
+	// faults are not counted against rate limits and fail until cleared
+	_, resp, _ = client.Statuses.Destroy(tweet.ID, nil)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	server.ClearFaults()
+	_, _, err = client.Statuses.Destroy(tweet.ID, nil)
+	assert.Nil(t, err)
+}
+
+func TestCursorPage(t *testing.T) {
+	cases := []struct {
+		cursor         string
+		start, end     int
+		next, previous int64
+	}{
+		{"", 0, 2, 2, 0},
+		{"-1", 0, 2, 2, 0},
+		{"2", 2, 4, 4, -1},
+		{"4", 4, 5, 0, -3},
+		{"-3", 2, 4, 4, -1},
+		{"9", 5, 5, 0, -4},
+	}
+	for _, c := range cases {
+		form := url.Values{"count": {"2"}}
+		if c.cursor != "" {
+			form.Set("cursor", c.cursor)
+		}
+		start, end, page := cursorPage(form, 5, 20, 200)
+		assert.Equal(t, c.start, start, c.cursor)
+		assert.Equal(t, c.end, end, c.cursor)
+		assert.Equal(t, c.next, page.NextCursor, c.cursor)
+		assert.Equal(t, c.previous, page.PreviousCursor, c.cursor)
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	id, ok := matchPath("/1.1/statuses/retweet/:id.json", "/1.1/statuses/retweet/20.json")
+	assert.True(t, ok)
+	assert.Equal(t, "20", id)
+	_, ok = matchPath("/1.1/statuses/retweet/:id.json", "/1.1/statuses/retweet/.json")
+	assert.False(t, ok)
+	_, ok = matchPath("/1.1/statuses/retweet/:id.json", "/1.1/statuses/retweet/1/2.json")
+	assert.False(t, ok)
+	_, ok = matchPath("/1.1/statuses/show.json", "/1.1/statuses/show.json")
+	assert.True(t, ok)
+}
@@ -0,0 +1,525 @@
+package twittertest
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dghubble/go-twitter/twitter"
+	"github.com/dghubble/go-twitter/twitter/twittertext"
+)
+
+var (
+	errMissingStatus   = &apiError{http.StatusBadRequest, 170, "Missing required parameter: status."}
+	errTweetTooLong    = &apiError{http.StatusForbidden, 186, "Tweet needs to be a bit shorter."}
+	errDuplicateStatus = &apiError{http.StatusForbidden, 187, "Status is a duplicate."}
+	errRetweeted       = &apiError{http.StatusForbidden, 327, "You have already retweeted this Tweet."}
+	errFavorited       = &apiError{http.StatusForbidden, 139, "You have already favorited this status."}
+	errNotFavorited    = &apiError{http.StatusNotFound, 144, "No status found with that ID."}
+)
+
+// tweet is a Tweet in the Server's state.
+type tweet struct {
+	id          int64
+	userID      int64
+	text        string
+	createdAt   time.Time
+	inReplyToID int64
+	retweetOfID int64
+}
+
+// addTweet assigns the Tweet an ID and creation time and adds it.
+func (s *Server) addTweet(t *tweet) *tweet {
+	t.id = s.nextID()
+	t.createdAt = s.now().UTC()
+	s.tweets[t.id] = t
+	s.statuses = append(s.statuses, t)
+	return t
+}
+
+// removeTweet removes the Tweet and its retweets.
+func (s *Server) removeTweet(id int64) {
+	kept := s.statuses[:0]
+	for _, t := range s.statuses {
+		if t.id == id || t.retweetOfID == id {
+			delete(s.tweets, t.id)
+			continue
+		}
+		kept = append(kept, t)
+	}
+	s.statuses = kept
+	for user, favorites := range s.favorites {
+		s.favorites[user] = removeID(favorites, id)
+	}
+}
+
+// retweetBy returns the user's retweet of the Tweet, if any.
+func (s *Server) retweetBy(userID, id int64) *tweet {
+	for _, t := range s.statuses {
+		if t.userID == userID && t.retweetOfID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+// renderTweet returns the Tweet as the API would, relative to the
+// authenticated user.
+func (s *Server) renderTweet(t *tweet) twitter.Tweet {
+	rendered := twitter.Tweet{
+		ID:               t.id,
+		IDStr:            strconv.FormatInt(t.id, 10),
+		Text:             t.text,
+		CreatedAt:        twitter.Time{Time: t.createdAt},
+		User:             s.renderUser(t.userID),
+		Entities:         s.entities(t.text),
+		DisplayTextRange: twitter.Indices{0, utf8.RuneCountInString(t.text)},
+	}
+	if original := s.tweets[t.inReplyToID]; original != nil {
+		rendered.InReplyToStatusID = original.id
+		rendered.InReplyToStatusIDStr = strconv.FormatInt(original.id, 10)
+		rendered.InReplyToUserID = original.userID
+		rendered.InReplyToUserIDStr = strconv.FormatInt(original.userID, 10)
+		rendered.InReplyToScreenName = s.users[original.userID].ScreenName
+	}
+	countsOf := t
+	if original := s.tweets[t.retweetOfID]; original != nil {
+		retweeted := s.renderTweet(original)
+		rendered.RetweetedStatus = &retweeted
+		countsOf = original
+	}
+	for _, other := range s.statuses {
+		if other.retweetOfID == countsOf.id {
+			rendered.RetweetCount++
+		}
+		if other.inReplyToID == countsOf.id {
+			rendered.ReplyCount++
+		}
+	}
+	for _, favorites := range s.favorites {
+		if hasID(favorites, countsOf.id) {
+			rendered.FavoriteCount++
+		}
+	}
+	rendered.Favorited = hasID(s.favorites[s.authUserID], countsOf.id)
+	if retweet := s.retweetBy(s.authUserID, countsOf.id); retweet != nil {
+		rendered.Retweeted = true
+		rendered.CurrentUserRetweet = &twitter.TweetIdentifier{
+			ID:    retweet.id,
+			IDStr: strconv.FormatInt(retweet.id, 10),
+		}
+	}
+	return rendered
+}
+
+// renderTweets returns the Tweets as the API would.
+func (s *Server) renderTweets(tweets []*tweet) []twitter.Tweet {
+	rendered := make([]twitter.Tweet, 0, len(tweets))
+	for _, t := range tweets {
+		rendered = append(rendered, s.renderTweet(t))
+	}
+	return rendered
+}
+
+// entities returns the hashtags, cashtags, URLs, and mentions of existing
+// users in the text.
+func (s *Server) entities(text string) *twitter.Entities {
+	entities := &twitter.Entities{
+		Hashtags:     []twitter.HashtagEntity{},
+		Media:        []twitter.MediaEntity{},
+		Urls:         []twitter.URLEntity{},
+		UserMentions: []twitter.MentionEntity{},
+		Symbols:      []twitter.SymbolEntity{},
+	}
+	for _, entity := range twittertext.Extract(text) {
+		indices := twitter.Indices(entity.Indices)
+		switch entity.Type {
+		case twittertext.Hashtag:
+			entities.Hashtags = append(entities.Hashtags, twitter.HashtagEntity{Indices: indices, Text: entity.Text})
+		case twittertext.Cashtag:
+			entities.Symbols = append(entities.Symbols, twitter.SymbolEntity{Indices: indices, Text: entity.Text})
+		case twittertext.URL:
+			entities.Urls = append(entities.Urls, twitter.URLEntity{URL: entity.Text, DisplayURL: entity.Text, ExpandedURL: entity.Text, Indices: indices})
+		case twittertext.Mention:
+			id, ok := s.userIDs[strings.ToLower(entity.Text)]
+			if !ok {
+				continue
+			}
+			user := s.users[id]
+			entities.UserMentions = append(entities.UserMentions, twitter.MentionEntity{
+				Indices:    indices,
+				ID:         user.ID,
+				IDStr:      user.IDStr,
+				Name:       user.Name,
+				ScreenName: user.ScreenName,
+			})
+		}
+	}
+	return entities
+}
+
+// mentions reports whether the text mentions the user.
+func (s *Server) mentions(text string, userID int64) bool {
+	for _, mention := range twittertext.ExtractMentions(text) {
+		if s.userIDs[strings.ToLower(mention.Text)] == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// timeline returns up to count Tweets kept by the filter, newest first,
+// within the since_id and max_id parameters.
+func (s *Server) timeline(form url.Values, count int, keep func(t *tweet) bool) []*tweet {
+	sinceID, maxID := int64Param(form, "since_id"), int64Param(form, "max_id")
+	var tweets []*tweet
+	for i := len(s.statuses) - 1; i >= 0 && len(tweets) < count; i-- {
+		t := s.statuses[i]
+		if t.id <= sinceID || maxID != 0 && t.id > maxID || !keep(t) {
+			continue
+		}
+		tweets = append(tweets, t)
+	}
+	return tweets
+}
+
+// findTweet returns the Tweet identified by the id, or by the id parameter if
+// the id is empty.
+func (s *Server) findTweet(form url.Values, id string) (*tweet, *apiError) {
+	if id == "" {
+		id = form.Get("id")
+	}
+	tweetID, _ := strconv.ParseInt(id, 10, 64)
+	t := s.tweets[tweetID]
+	if t == nil {
+		return nil, errNoStatus
+	}
+	return t, nil
+}
+
+func (s *Server) statusesShow(req *http.Request, id string) (interface{}, *apiError) {
+	t, err := s.findTweet(req.Form, "")
+	if err != nil {
+		return nil, err
+	}
+	return s.renderTweet(t), nil
+}
+
+func (s *Server) statusesLookup(req *http.Request, id string) (interface{}, *apiError) {
+	var tweets []*tweet
+	for _, id := range int64sParam(req.Form, "id") {
+		if t := s.tweets[id]; t != nil {
+			tweets = append(tweets, t)
+		}
+	}
+	return s.renderTweets(tweets), nil
+}
+
+func (s *Server) statusesUpdate(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	text := req.Form.Get("status")
+	if text == "" {
+		return nil, errMissingStatus
+	}
+	if twittertext.WeightedLength(text) > twittertext.MaxWeightedLength {
+		return nil, errTweetTooLong
+	}
+	for i := len(s.statuses) - 1; i >= 0; i-- {
+		if t := s.statuses[i]; t.userID == userID && t.retweetOfID == 0 {
+			if t.text == text {
+				return nil, errDuplicateStatus
+			}
+			break
+		}
+	}
+	t := &tweet{userID: userID, text: text}
+	if replyTo := int64Param(req.Form, "in_reply_to_status_id"); replyTo != 0 {
+		if s.tweets[replyTo] == nil {
+			return nil, errNoStatus
+		}
+		t.inReplyToID = replyTo
+	}
+	return s.renderTweet(s.addTweet(t)), nil
+}
+
+func (s *Server) statusesDestroy(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	t, err := s.findTweet(req.Form, id)
+	if err != nil {
+		return nil, err
+	}
+	if t.userID != userID {
+		return nil, errForbidden
+	}
+	rendered := s.renderTweet(t)
+	s.removeTweet(t.id)
+	return rendered, nil
+}
+
+func (s *Server) statusesRetweet(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	t, err := s.findTweet(req.Form, id)
+	if err != nil {
+		return nil, err
+	}
+	if original := s.tweets[t.retweetOfID]; original != nil {
+		t = original
+	}
+	if s.retweetBy(userID, t.id) != nil {
+		return nil, errRetweeted
+	}
+	retweet := s.addTweet(&tweet{
+		userID:      userID,
+		text:        "RT @" + s.users[t.userID].ScreenName + ": " + t.text,
+		retweetOfID: t.id,
+	})
+	return s.renderTweet(retweet), nil
+}
+
+func (s *Server) statusesUnretweet(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	t, err := s.findTweet(req.Form, id)
+	if err != nil {
+		return nil, err
+	}
+	if original := s.tweets[t.retweetOfID]; original != nil {
+		t = original
+	}
+	if retweet := s.retweetBy(userID, t.id); retweet != nil {
+		s.removeTweet(retweet.id)
+	}
+	return s.renderTweet(t), nil
+}
+
+func (s *Server) statusesRetweets(req *http.Request, id string) (interface{}, *apiError) {
+	t, err := s.findTweet(req.Form, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.renderTweets(s.timeline(req.Form, countParam(req.Form, 100, 100), func(other *tweet) bool {
+		return other.retweetOfID == t.id
+	})), nil
+}
+
+// statusesRetweeters returns the IDs of the users who retweeted the Tweet,
+// newest first.
+func (s *Server) statusesRetweeters(req *http.Request, id string) (interface{}, *apiError) {
+	t, err := s.findTweet(req.Form, "")
+	if err != nil {
+		return nil, err
+	}
+	var ids []int64
+	for i := len(s.statuses) - 1; i >= 0; i-- {
+		if other := s.statuses[i]; other.retweetOfID == t.id {
+			ids = append(ids, other.userID)
+		}
+	}
+	start, end, page := cursorPage(req.Form, len(ids), 100, 100)
+	return &struct {
+		IDs []int64 `json:"ids"`
+		cursors
+	}{ids[start:end], page}, nil
+}
+
+// statusesOEmbed returns the Tweet identified by the id or url parameter
+// as an embedded Tweet.
+func (s *Server) statusesOEmbed(req *http.Request, id string) (interface{}, *apiError) {
+	id = req.Form.Get("id")
+	if id == "" {
+		// e.g. https://twitter.com/alice/status/20
+		path := req.Form.Get("url")
+		id = path[strings.LastIndex(path, "/")+1:]
+	}
+	t, err := s.findTweet(req.Form, id)
+	if err != nil {
+		return nil, err
+	}
+	author := s.users[t.userID]
+	authorURL := "https://twitter.com/" + author.ScreenName
+	tweetURL := authorURL + "/status/" + strconv.FormatInt(t.id, 10)
+	width := int64Param(req.Form, "maxwidth")
+	if width < 220 || width > 550 {
+		width = 550
+	}
+	embed := fmt.Sprintf(`<blockquote class="twitter-tweet"><p>%s</p>&mdash; %s (@%s) <a href="%s">%s</a></blockquote>`,
+		html.EscapeString(t.text), html.EscapeString(author.Name), author.ScreenName, tweetURL, t.createdAt.Format("January 2, 2006"))
+	if !boolParam(req.Form, "omit_script", false) {
+		embed += "\n" + `<script async src="https://platform.twitter.com/widgets.js" charset="utf-8"></script>`
+	}
+	return &twitter.OEmbedTweet{
+		URL:          tweetURL,
+		ProviderURL:  "https://twitter.com",
+		ProviderName: "Twitter",
+		AuthorName:   author.Name,
+		Version:      "1.0",
+		AuthorURL:    authorURL,
+		Type:         "rich",
+		HTML:         embed + "\n",
+		Width:        width,
+		CacheAge:     "3153600000",
+	}, nil
+}
+
+func (s *Server) statusesUserTimeline(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.findUser(req.Form, "user_id", "screen_name")
+	if err != nil {
+		return nil, err
+	}
+	includeRetweets := boolParam(req.Form, "include_rts", true)
+	excludeReplies := boolParam(req.Form, "exclude_replies", false)
+	return s.renderTweets(s.timeline(req.Form, countParam(req.Form, 20, 200), func(t *tweet) bool {
+		return t.userID == userID &&
+			(includeRetweets || t.retweetOfID == 0) &&
+			(!excludeReplies || t.inReplyToID == 0)
+	})), nil
+}
+
+func (s *Server) statusesHomeTimeline(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	excludeReplies := boolParam(req.Form, "exclude_replies", false)
+	return s.renderTweets(s.timeline(req.Form, countParam(req.Form, 20, 200), func(t *tweet) bool {
+		return (t.userID == userID || hasID(s.following[userID], t.userID)) &&
+			(!excludeReplies || t.inReplyToID == 0)
+	})), nil
+}
+
+func (s *Server) statusesMentionsTimeline(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	return s.renderTweets(s.timeline(req.Form, countParam(req.Form, 20, 200), func(t *tweet) bool {
+		return t.retweetOfID == 0 && s.mentions(t.text, userID)
+	})), nil
+}
+
+func (s *Server) statusesRetweetsOfMe(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	return s.renderTweets(s.timeline(req.Form, countParam(req.Form, 20, 100), func(t *tweet) bool {
+		return t.userID == userID && t.retweetOfID == 0 && s.retweeted(t.id)
+	})), nil
+}
+
+// retweeted reports whether the Tweet has been retweeted.
+func (s *Server) retweeted(id int64) bool {
+	for _, t := range s.statuses {
+		if t.retweetOfID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// searchTweets matches Tweets containing every term of the query, ignoring
+// case. Terms of the form from:screen_name match Tweets by that user.
+func (s *Server) searchTweets(req *http.Request, id string) (interface{}, *apiError) {
+	query := req.Form.Get("q")
+	var terms []string
+	var fromIDs []int64
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		if strings.HasPrefix(term, "from:") {
+			fromIDs = append(fromIDs, s.userIDs[strings.TrimPrefix(term, "from:")])
+			continue
+		}
+		terms = append(terms, term)
+	}
+	count := countParam(req.Form, 15, 100)
+	tweets := s.timeline(req.Form, count+1, func(t *tweet) bool {
+		for _, fromID := range fromIDs {
+			if t.userID != fromID {
+				return false
+			}
+		}
+		text := strings.ToLower(t.text)
+		for _, term := range terms {
+			if !strings.Contains(text, term) {
+				return false
+			}
+		}
+		return true
+	})
+	metadata := &twitter.SearchMetadata{
+		Query:      query,
+		SinceID:    int64Param(req.Form, "since_id"),
+		SinceIDStr: strconv.FormatInt(int64Param(req.Form, "since_id"), 10),
+	}
+	if len(tweets) > count {
+		next := url.Values{}
+		next.Set("max_id", strconv.FormatInt(tweets[count].id, 10))
+		next.Set("q", query)
+		next.Set("count", strconv.Itoa(count))
+		metadata.NextResults = "?" + next.Encode()
+		tweets = tweets[:count]
+	}
+	if len(tweets) > 0 {
+		metadata.MaxID = tweets[0].id
+	}
+	metadata.MaxIDStr = strconv.FormatInt(metadata.MaxID, 10)
+	metadata.Count = len(tweets)
+	return &twitter.Search{Statuses: s.renderTweets(tweets), Metadata: metadata}, nil
+}
+
+func (s *Server) favoritesList(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.findUser(req.Form, "user_id", "screen_name")
+	if err != nil {
+		return nil, err
+	}
+	return s.renderTweets(s.timeline(req.Form, countParam(req.Form, 20, 200), func(t *tweet) bool {
+		return hasID(s.favorites[userID], t.id)
+	})), nil
+}
+
+func (s *Server) favoritesCreate(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	t, err := s.findTweet(req.Form, "")
+	if err != nil {
+		return nil, err
+	}
+	if hasID(s.favorites[userID], t.id) {
+		return nil, errFavorited
+	}
+	s.favorites[userID] = append(s.favorites[userID], t.id)
+	return s.renderTweet(t), nil
+}
+
+func (s *Server) favoritesDestroy(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	t, err := s.findTweet(req.Form, "")
+	if err != nil {
+		return nil, err
+	}
+	if !hasID(s.favorites[userID], t.id) {
+		return nil, errNotFavorited
+	}
+	s.favorites[userID] = removeID(s.favorites[userID], t.id)
+	return s.renderTweet(t), nil
+}
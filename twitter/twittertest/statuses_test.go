@@ -0,0 +1,252 @@
+package twittertest
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/dghubble/go-twitter/twitter"
+	"github.com/dghubble/go-twitter/twitter/twittertext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatuses_UpdateShowDestroy(t *testing.T) {
+	server, client, alice, bob := testClient()
+	defer server.Close()
+
+	tweet, _, err := client.Statuses.Update("hello @bob #golang", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, alice.ID, tweet.User.ID)
+	assert.Equal(t, 1, tweet.User.StatusesCount)
+	assert.False(t, tweet.CreatedAt.IsZero())
+	assert.Equal(t, []twitter.MentionEntity{{Indices: twitter.Indices{6, 10}, ID: bob.ID, IDStr: bob.IDStr, Name: "Bob", ScreenName: "bob"}}, tweet.Entities.UserMentions)
+	assert.Equal(t, []twitter.HashtagEntity{{Indices: twitter.Indices{11, 18}, Text: "golang"}}, tweet.Entities.Hashtags)
+
+	shown, _, err := client.Statuses.Show(tweet.ID, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, tweet.Text, shown.Text)
+	assert.Equal(t, twitter.SnowflakeTime(tweet.ID).Unix(), shown.CreatedAt.Unix())
+
+	reply, _, err := client.Statuses.Update("@alice hi", &twitter.StatusUpdateParams{InReplyToStatusID: tweet.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, tweet.ID, reply.InReplyToStatusID)
+	assert.Equal(t, "alice", reply.InReplyToScreenName)
+
+	destroyed, _, err := client.Statuses.Destroy(tweet.ID, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, tweet.ID, destroyed.ID)
+	_, resp, err := client.Statuses.Show(tweet.ID, nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, twitter.APIError{Errors: []twitter.ErrorDetail{{Code: 144, Message: "No status found with that ID."}}}, err)
+}
+
+func TestStatuses_UpdateErrors(t *testing.T) {
+	server, client, _, bob := testClient()
+	defer server.Close()
+
+	_, resp, _ := client.Statuses.Update("", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	// emoji weigh 2 characters
+	_, _, err := client.Statuses.Update(strings.Repeat("🐹", 140), nil)
+	assert.Nil(t, err)
+	_, resp, err = client.Statuses.Update(strings.Repeat("🐹", 140), nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, 187, err.(twitter.APIError).Errors[0].Code)
+
+	// the Client rejects long Tweets without a request, but the Server does too
+	_, resp, err = client.Statuses.Update(strings.Repeat("a", 281), nil)
+	assert.Nil(t, resp)
+	assert.Equal(t, twittertext.ErrTooLong, err)
+	resp, err = server.Client().PostForm("https://api.twitter.com/1.1/statuses/update.json", url.Values{"status": {strings.Repeat("🐹", 141)}})
+	if assert.Nil(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	}
+
+	// only the author may delete a Tweet
+	tweet := server.AddTweet(bob.ID, "mine")
+	_, resp, _ = client.Statuses.Destroy(tweet.ID, nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestStatuses_Lookup(t *testing.T) {
+	server, client, alice, bob := testClient()
+	defer server.Close()
+	first := server.AddTweet(alice.ID, "one")
+	second := server.AddTweet(bob.ID, "two")
+
+	tweets, _, err := client.Statuses.Lookup([]int64{first.ID, second.ID, 1}, nil)
+	assert.Nil(t, err)
+	if assert.Len(t, tweets, 2) {
+		assert.Equal(t, "one", tweets[0].Text)
+		assert.Equal(t, "two", tweets[1].Text)
+	}
+}
+
+func TestStatuses_RetweetUnretweet(t *testing.T) {
+	server, client, alice, bob := testClient()
+	defer server.Close()
+	original := server.AddTweet(bob.ID, "retweet me")
+
+	retweet, _, err := client.Statuses.Retweet(original.ID, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "RT @bob: retweet me", retweet.Text)
+	if assert.NotNil(t, retweet.RetweetedStatus) {
+		assert.Equal(t, original.ID, retweet.RetweetedStatus.ID)
+		assert.Equal(t, 1, retweet.RetweetedStatus.RetweetCount)
+		assert.True(t, retweet.RetweetedStatus.Retweeted)
+	}
+	_, resp, _ := client.Statuses.Retweet(original.ID, nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	retweets, _, err := client.Statuses.Retweets(original.ID, nil)
+	assert.Nil(t, err)
+	assert.Len(t, retweets, 1)
+	shown, _, _ := client.Statuses.Show(original.ID, nil)
+	assert.Equal(t, retweet.ID, shown.CurrentUserRetweet.ID)
+
+	server.SetAuthUser(bob.ID)
+	mine, _, err := client.Timelines.RetweetsOfMeTimeline(nil)
+	assert.Nil(t, err)
+	assert.Len(t, mine, 1)
+
+	server.SetAuthUser(alice.ID)
+	unretweeted, _, err := client.Statuses.Unretweet(original.ID, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, unretweeted.RetweetCount)
+	assert.False(t, unretweeted.Retweeted)
+}
+
+func TestStatuses_Retweeters(t *testing.T) {
+	server, client, alice, bob := testClient()
+	defer server.Close()
+	carol := server.AddUser(&twitter.User{ScreenName: "carol"})
+	original := server.AddTweet(bob.ID, "retweet me")
+
+	_, _, err := client.Statuses.Retweet(original.ID, nil)
+	assert.Nil(t, err)
+	server.SetAuthUser(carol.ID)
+	_, _, err = client.Statuses.Retweet(original.ID, nil)
+	assert.Nil(t, err)
+
+	retweeters, _, err := client.Statuses.Retweeters(&twitter.StatusRetweeterParams{ID: original.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{carol.ID, alice.ID}, retweeters.IDs)
+	retweeters, _, err = client.Statuses.Retweeters(&twitter.StatusRetweeterParams{ID: original.ID, Count: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{carol.ID}, retweeters.IDs)
+	assert.NotZero(t, retweeters.NextCursor)
+	_, resp, _ := client.Statuses.Retweeters(&twitter.StatusRetweeterParams{ID: 1})
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestStatuses_OEmbed(t *testing.T) {
+	server, client, _, bob := testClient()
+	defer server.Close()
+	tweet := server.AddTweet(bob.ID, "embed <me>")
+	tweetURL := "https://twitter.com/bob/status/" + tweet.IDStr
+
+	embed, _, err := client.Statuses.OEmbed(&twitter.StatusOEmbedParams{ID: tweet.ID, MaxWidth: 300})
+	assert.Nil(t, err)
+	assert.Equal(t, tweetURL, embed.URL)
+	assert.Equal(t, "Bob", embed.AuthorName)
+	assert.Equal(t, "https://twitter.com/bob", embed.AuthorURL)
+	assert.Equal(t, "rich", embed.Type)
+	assert.Equal(t, int64(300), embed.Width)
+	assert.Contains(t, embed.HTML, "<p>embed &lt;me&gt;</p>")
+	assert.Contains(t, embed.HTML, `<a href="`+tweetURL+`">`)
+
+	byURL, _, err := client.Statuses.OEmbed(&twitter.StatusOEmbedParams{URL: tweetURL})
+	assert.Nil(t, err)
+	assert.Equal(t, embed.HTML, byURL.HTML)
+	_, resp, _ := client.Statuses.OEmbed(&twitter.StatusOEmbedParams{URL: "https://twitter.com/bob/status/1"})
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestTimelines(t *testing.T) {
+	server, client, alice, bob := testClient()
+	carol := server.AddUser(&twitter.User{ScreenName: "carol"})
+	defer server.Close()
+	server.Follow(alice.ID, bob.ID)
+	aliceTweet := server.AddTweet(alice.ID, "alice here")
+	bobTweet := server.AddTweet(bob.ID, "bob here, hi @alice")
+	server.AddTweet(carol.ID, "carol here")
+
+	home, _, err := client.Timelines.HomeTimeline(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{bobTweet.ID, aliceTweet.ID}, tweetIDs(home))
+	home, _, err = client.Timelines.HomeTimeline(&twitter.HomeTimelineParams{SinceID: aliceTweet.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{bobTweet.ID}, tweetIDs(home))
+	home, _, err = client.Timelines.HomeTimeline(&twitter.HomeTimelineParams{MaxID: aliceTweet.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{aliceTweet.ID}, tweetIDs(home))
+	home, _, err = client.Timelines.HomeTimeline(&twitter.HomeTimelineParams{Count: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{bobTweet.ID}, tweetIDs(home))
+
+	user, _, err := client.Timelines.UserTimeline(&twitter.UserTimelineParams{ScreenName: "carol"})
+	assert.Nil(t, err)
+	assert.Len(t, user, 1)
+
+	mentions, _, err := client.Timelines.MentionTimeline(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{bobTweet.ID}, tweetIDs(mentions))
+}
+
+func TestSearch_Tweets(t *testing.T) {
+	server, client, alice, bob := testClient()
+	defer server.Close()
+	first := server.AddTweet(alice.ID, "Gophers love Go")
+	second := server.AddTweet(bob.ID, "go gophers go")
+	server.AddTweet(bob.ID, "something else")
+
+	search, _, err := client.Search.Tweets(&twitter.SearchTweetParams{Query: "gophers go"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{second.ID, first.ID}, tweetIDs(search.Statuses))
+	assert.Equal(t, 2, search.Metadata.Count)
+	assert.Equal(t, second.ID, search.Metadata.MaxID)
+	assert.Empty(t, search.Metadata.NextResults)
+
+	search, _, err = client.Search.Tweets(&twitter.SearchTweetParams{Query: "gophers from:alice"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{first.ID}, tweetIDs(search.Statuses))
+
+	search, _, err = client.Search.Tweets(&twitter.SearchTweetParams{Query: "gophers", Count: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{second.ID}, tweetIDs(search.Statuses))
+	assert.Contains(t, search.Metadata.NextResults, "max_id="+first.IDStr)
+}
+
+func TestFavorites(t *testing.T) {
+	server, client, alice, bob := testClient()
+	defer server.Close()
+	tweet := server.AddTweet(bob.ID, "like me")
+
+	favorited, _, err := client.Favorites.Create(&twitter.FavoriteCreateParams{ID: tweet.ID})
+	assert.Nil(t, err)
+	assert.True(t, favorited.Favorited)
+	assert.Equal(t, 1, favorited.FavoriteCount)
+	_, resp, _ := client.Favorites.Create(&twitter.FavoriteCreateParams{ID: tweet.ID})
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	favorites, _, err := client.Favorites.List(&twitter.FavoriteListParams{UserID: alice.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{tweet.ID}, tweetIDs(favorites))
+
+	unfavorited, _, err := client.Favorites.Destroy(&twitter.FavoriteDestroyParams{ID: tweet.ID})
+	assert.Nil(t, err)
+	assert.False(t, unfavorited.Favorited)
+	assert.Equal(t, 0, unfavorited.FavoriteCount)
+	_, resp, _ = client.Favorites.Destroy(&twitter.FavoriteDestroyParams{ID: tweet.ID})
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func tweetIDs(tweets []twitter.Tweet) []int64 {
+	var ids []int64
+	for _, tweet := range tweets {
+		ids = append(ids, tweet.ID)
+	}
+	return ids
+}
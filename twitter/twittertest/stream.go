@@ -0,0 +1,339 @@
+package twittertest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dghubble/go-twitter/twitter"
+)
+
+// StreamMessageKind is a kind of synthetic message sent by a StreamServer.
+type StreamMessageKind string
+
+// Kinds of synthetic stream messages.
+const (
+	StreamTweet      StreamMessageKind = "tweet"
+	StreamLimit      StreamMessageKind = "limit"
+	StreamDelete     StreamMessageKind = "delete"
+	StreamScrubGeo   StreamMessageKind = "scrub_geo"
+	StreamWarning    StreamMessageKind = "warning"
+	StreamDisconnect StreamMessageKind = "disconnect"
+)
+
+// order in which messages due at the same time are sent
+var streamMessageKinds = []StreamMessageKind{
+	StreamTweet,
+	StreamLimit,
+	StreamDelete,
+	StreamScrubGeo,
+	StreamWarning,
+	StreamDisconnect,
+}
+
+// default interval between keep-alive newlines
+const streamKeepAlive = 30 * time.Second
+
+// StreamServerParams are the parameters for NewStreamServer.
+type StreamServerParams struct {
+	// messages of each kind sent per second on each connection. Sending a
+	// disconnect message ends the connection.
+	Rates map[StreamMessageKind]float64
+	// interval between keep-alive newlines (default 30s)
+	KeepAlive time.Duration
+}
+
+// A StreamFault is injected into a connection to a StreamServer.
+type StreamFault struct {
+	// respond with this HTTP status code (e.g. 420, 429, or 503) instead of
+	// streaming
+	Status int
+	// abruptly close the connection, without a disconnect message, after this
+	// many messages (0 never closes)
+	DisconnectAfter int
+	// send nothing, not even keep-alives, for StallFor after StallAfter
+	// messages
+	StallAfter int
+	StallFor   time.Duration
+	// write each message in pieces, splitting the "\r\n" delimiter, with a
+	// newline inside the message
+	Partial bool
+}
+
+// StreamServer is a fake Twitter Streaming API server which sends synthetic
+// Tweets and control messages on every streaming endpoint. Faults make
+// connections fail, disconnect, stall, or split messages, to exercise
+// stream consumers and the Stream reconnect logic. Its methods are safe for
+// concurrent use.
+type StreamServer struct {
+	// base URL of the server, of the form http://ipaddr:port
+	URL       string
+	server    *httptest.Server
+	rates     map[StreamMessageKind]float64
+	keepAlive time.Duration
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	mu          sync.Mutex
+	faults      []StreamFault
+	connections int
+	sent        int64
+	lastTweet   *twitter.Tweet
+}
+
+// NewStreamServer starts and returns a new StreamServer. The caller must
+// Close the StreamServer when finished.
+func NewStreamServer(params *StreamServerParams) *StreamServer {
+	if params == nil {
+		params = &StreamServerParams{}
+	}
+	keepAlive := params.KeepAlive
+	if keepAlive <= 0 {
+		keepAlive = streamKeepAlive
+	}
+	rates := make(map[StreamMessageKind]float64)
+	for kind, rate := range params.Rates {
+		rates[kind] = rate
+	}
+	s := &StreamServer{
+		rates:     rates,
+		keepAlive: keepAlive,
+		closed:    make(chan struct{}),
+	}
+	s.server = httptest.NewServer(s)
+	s.URL = s.server.URL
+	return s
+}
+
+// Close ends open connections and shuts down the StreamServer.
+func (s *StreamServer) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+	s.server.Close()
+}
+
+// Client returns an http.Client which sends requests for any host (e.g.
+// https://stream.twitter.com) to the StreamServer. Pass it to
+// twitter.NewClient.
+func (s *StreamServer) Client() *http.Client {
+	target, _ := url.Parse(s.URL)
+	return &http.Client{Transport: &rewriteTransport{target: target, transport: s.server.Client().Transport}}
+}
+
+// Inject queues Faults for the next connections, one Fault per connection,
+// in order. Connections without a Fault stream normally.
+func (s *StreamServer) Inject(faults ...StreamFault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, faults...)
+}
+
+// Connections returns the number of connections (including failed ones)
+// made to the StreamServer.
+func (s *StreamServer) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections
+}
+
+// ServeHTTP streams synthetic messages until the connection's Fault or a
+// disconnect message ends it, the client disconnects, or the StreamServer
+// is closed.
+func (s *StreamServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	fault := s.connect()
+	if fault.Status != 0 {
+		http.Error(w, http.StatusText(fault.Status), fault.Status)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	now := time.Now()
+	next := make(map[StreamMessageKind]time.Time)
+	for kind, rate := range s.rates {
+		if rate > 0 {
+			next[kind] = now.Add(rateInterval(rate))
+		}
+	}
+	keepAlive := now.Add(s.keepAlive)
+	for sent := 0; ; sent++ {
+		if fault.StallFor > 0 && sent == fault.StallAfter {
+			if !s.sleep(req, fault.StallFor) {
+				return
+			}
+			keepAlive = time.Now().Add(s.keepAlive)
+		}
+		if fault.DisconnectAfter > 0 && sent == fault.DisconnectAfter {
+			abort(w)
+			return
+		}
+		// send keep-alives until the next message is due
+		kind, due := nextMessage(next)
+		for kind == "" || keepAlive.Before(due) {
+			if !s.sleep(req, time.Until(keepAlive)) || !writeFrame(w, flusher, nil, false) {
+				return
+			}
+			keepAlive = keepAlive.Add(s.keepAlive)
+		}
+		if !s.sleep(req, time.Until(due)) {
+			return
+		}
+		next[kind] = due.Add(rateInterval(s.rates[kind]))
+		if !writeFrame(w, flusher, s.message(kind), fault.Partial) {
+			return
+		}
+		if kind == StreamDisconnect {
+			return
+		}
+	}
+}
+
+// connect counts a connection and returns its Fault, if any.
+func (s *StreamServer) connect() StreamFault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.connections++
+	var fault StreamFault
+	if len(s.faults) > 0 {
+		fault, s.faults = s.faults[0], s.faults[1:]
+	}
+	return fault
+}
+
+// sleep waits for the duration. Returns false if the client disconnected or
+// the StreamServer was closed first.
+func (s *StreamServer) sleep(req *http.Request, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-req.Context().Done():
+		return false
+	case <-s.closed:
+		return false
+	}
+}
+
+// message returns a synthetic message of the given kind.
+func (s *StreamServer) message(kind StreamMessageKind) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent++
+	switch kind {
+	case StreamTweet:
+		id := twitter.MinSnowflakeID(time.Now()) + s.sent
+		userID := 1000 + s.sent%10
+		s.lastTweet = &twitter.Tweet{
+			ID:        id,
+			IDStr:     strconv.FormatInt(id, 10),
+			Text:      "synthetic Tweet " + strconv.FormatInt(s.sent, 10),
+			CreatedAt: twitter.Time{Time: time.Now().UTC()},
+			User: &twitter.User{
+				ID:         userID,
+				IDStr:      strconv.FormatInt(userID, 10),
+				ScreenName: "user" + strconv.FormatInt(userID, 10),
+			},
+		}
+		return s.lastTweet
+	case StreamLimit:
+		return map[string]interface{}{"limit": &twitter.StreamLimit{Track: s.sent}}
+	case StreamDelete:
+		deletion := &twitter.StatusDeletion{ID: s.sent, UserID: 1000}
+		if s.lastTweet != nil {
+			deletion.ID, deletion.UserID = s.lastTweet.ID, s.lastTweet.User.ID
+		}
+		deletion.IDStr = strconv.FormatInt(deletion.ID, 10)
+		deletion.UserIDStr = strconv.FormatInt(deletion.UserID, 10)
+		return map[string]interface{}{"delete": map[string]interface{}{"status": deletion}}
+	case StreamScrubGeo:
+		scrub := &twitter.LocationDeletion{UserID: 1000, UpToStatusID: s.sent}
+		if s.lastTweet != nil {
+			scrub.UserID, scrub.UpToStatusID = s.lastTweet.User.ID, s.lastTweet.ID
+		}
+		scrub.UserIDStr = strconv.FormatInt(scrub.UserID, 10)
+		scrub.UpToStatusIDStr = strconv.FormatInt(scrub.UpToStatusID, 10)
+		return map[string]interface{}{"scrub_geo": scrub}
+	case StreamWarning:
+		return map[string]interface{}{"warning": &twitter.StallWarning{
+			Code:        "FALLS_BEHIND",
+			Message:     "Your connection is falling behind and messages are being queued for delivery to you.",
+			PercentFull: 60,
+		}}
+	default:
+		return map[string]interface{}{"disconnect": &twitter.StreamDisconnect{
+			Code:       1,
+			StreamName: "twittertest",
+			Reason:     "Shutdown",
+		}}
+	}
+}
+
+// nextMessage returns the kind of message due next and when it is due, or
+// an empty kind if no messages are sent.
+func nextMessage(next map[StreamMessageKind]time.Time) (StreamMessageKind, time.Time) {
+	var kind StreamMessageKind
+	var due time.Time
+	for _, k := range streamMessageKinds {
+		if t, ok := next[k]; ok && (kind == "" || t.Before(due)) {
+			kind, due = k, t
+		}
+	}
+	return kind, due
+}
+
+// rateInterval returns the interval between messages sent at the rate per
+// second.
+func rateInterval(rate float64) time.Duration {
+	return time.Duration(float64(time.Second) / rate)
+}
+
+// writeFrame writes the message followed by the "\r\n" delimiter, or just
+// the delimiter as a keep-alive if the message is nil. Partial frames are
+// written in pieces, with a newline inside the message. Returns false if
+// the write failed.
+func writeFrame(w http.ResponseWriter, flusher http.Flusher, message interface{}, partial bool) bool {
+	var frame []byte
+	if message != nil {
+		data, err := json.Marshal(message)
+		if err != nil {
+			return false
+		}
+		if partial {
+			// JSON allows whitespace between tokens
+			data = append([]byte("{\n"), data[1:]...)
+		}
+		frame = data
+	}
+	frame = append(frame, '\r', '\n')
+	pieces := [][]byte{frame}
+	if partial {
+		middle := len(frame) / 2
+		pieces = [][]byte{frame[:middle], frame[middle : len(frame)-1], frame[len(frame)-1:]}
+	}
+	for _, piece := range pieces {
+		if _, err := w.Write(piece); err != nil {
+			return false
+		}
+		flusher.Flush()
+	}
+	return true
+}
+
+// abort closes the connection without ending the response.
+func abort(w http.ResponseWriter) {
+	if hijacker, ok := w.(http.Hijacker); ok {
+		if conn, _, err := hijacker.Hijack(); err == nil {
+			conn.Close()
+		}
+	}
+}
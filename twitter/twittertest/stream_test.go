@@ -0,0 +1,196 @@
+package twittertest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/dghubble/go-twitter/twitter"
+	"github.com/stretchr/testify/assert"
+)
+
+// testStreamClient returns a Client of the StreamServer which reconnects
+// without waiting.
+func testStreamClient(server *StreamServer) *twitter.Client {
+	noWait := func() backoff.BackOff { return &backoff.ZeroBackOff{} }
+	return twitter.NewClient(server.Client(), twitter.WithStreamBackOff(noWait, noWait))
+}
+
+// receiveTweets receives messages until count Tweets have been received and
+// returns the messages.
+func receiveTweets(t *testing.T, stream *twitter.Stream, count int) []interface{} {
+	var messages []interface{}
+	timeout := time.After(5 * time.Second)
+	for tweets := 0; tweets < count; {
+		select {
+		case message, ok := <-stream.Messages:
+			if !ok {
+				t.Fatalf("stream closed after %d messages", len(messages))
+			}
+			if _, ok := message.(*twitter.Tweet); ok {
+				tweets++
+			}
+			messages = append(messages, message)
+		case <-timeout:
+			t.Fatalf("timed out after %d messages", len(messages))
+		}
+	}
+	return messages
+}
+
+func TestStreamServer_Messages(t *testing.T) {
+	server := NewStreamServer(&StreamServerParams{
+		Rates: map[StreamMessageKind]float64{
+			StreamTweet:    500,
+			StreamLimit:    100,
+			StreamDelete:   100,
+			StreamScrubGeo: 100,
+			StreamWarning:  100,
+		},
+	})
+	defer server.Close()
+	stream, err := testStreamClient(server).Streams.Sample(nil)
+	assert.Nil(t, err)
+	defer stream.Stop()
+
+	kinds := make(map[string]bool)
+	for _, message := range receiveTweets(t, stream, 50) {
+		switch message := message.(type) {
+		case *twitter.Tweet:
+			kinds["tweet"] = true
+			assert.NotZero(t, message.ID)
+			assert.NotNil(t, message.User)
+		case *twitter.StreamLimit:
+			kinds["limit"] = true
+		case *twitter.StatusDeletion:
+			kinds["delete"] = true
+			assert.NotZero(t, message.ID)
+		case *twitter.LocationDeletion:
+			kinds["scrub_geo"] = true
+		case *twitter.StallWarning:
+			kinds["warning"] = true
+			assert.Equal(t, "FALLS_BEHIND", message.Code)
+		default:
+			t.Errorf("unexpected message %#v", message)
+		}
+	}
+	assert.Equal(t, map[string]bool{"tweet": true, "limit": true, "delete": true, "scrub_geo": true, "warning": true}, kinds)
+}
+
+func TestStreamServer_KeepAlive(t *testing.T) {
+	server := NewStreamServer(&StreamServerParams{KeepAlive: 10 * time.Millisecond})
+	defer server.Close()
+	client := server.Client()
+	resp, err := client.Get("https://stream.twitter.com/1.1/statuses/sample.json")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	data := make([]byte, 4)
+	n, err := io.ReadAtLeast(resp.Body, data, 4)
+	assert.Nil(t, err)
+	assert.Equal(t, "\r\n\r\n", string(data[:n]))
+}
+
+func TestStreamServer_Reconnects(t *testing.T) {
+	server := NewStreamServer(&StreamServerParams{
+		Rates: map[StreamMessageKind]float64{StreamTweet: 1000},
+	})
+	defer server.Close()
+	server.Inject(
+		StreamFault{Status: http.StatusServiceUnavailable},
+		StreamFault{Status: 420},
+		StreamFault{Status: http.StatusTooManyRequests},
+		StreamFault{DisconnectAfter: 3},
+	)
+	stream, err := testStreamClient(server).Streams.Filter(&twitter.StreamFilterParams{Track: []string{"go"}})
+	assert.Nil(t, err)
+	defer stream.Stop()
+
+	messages := receiveTweets(t, stream, 10)
+	assert.Len(t, messages, 10)
+	assert.Equal(t, 5, server.Connections())
+}
+
+func TestStreamServer_DisconnectMessage(t *testing.T) {
+	server := NewStreamServer(&StreamServerParams{
+		Rates: map[StreamMessageKind]float64{StreamTweet: 1000, StreamDisconnect: 200},
+	})
+	defer server.Close()
+	stream, err := testStreamClient(server).Streams.Sample(nil)
+	assert.Nil(t, err)
+	defer stream.Stop()
+
+	var disconnects int
+	for _, message := range receiveTweets(t, stream, 20) {
+		if disconnect, ok := message.(*twitter.StreamDisconnect); ok {
+			disconnects++
+			assert.Equal(t, "Shutdown", disconnect.Reason)
+		}
+	}
+	assert.True(t, disconnects > 0)
+	assert.Equal(t, disconnects+1, server.Connections())
+}
+
+func TestStreamServer_Stall(t *testing.T) {
+	server := NewStreamServer(&StreamServerParams{
+		Rates: map[StreamMessageKind]float64{StreamTweet: 1000},
+	})
+	defer server.Close()
+	server.Inject(StreamFault{StallAfter: 1, StallFor: 100 * time.Millisecond})
+	stream, err := testStreamClient(server).Streams.Sample(nil)
+	assert.Nil(t, err)
+	defer stream.Stop()
+
+	receiveTweets(t, stream, 1)
+	start := time.Now()
+	receiveTweets(t, stream, 1)
+	assert.True(t, time.Since(start) >= 100*time.Millisecond)
+	assert.Equal(t, 1, server.Connections())
+}
+
+func TestStreamServer_Partial(t *testing.T) {
+	server := NewStreamServer(&StreamServerParams{
+		Rates: map[StreamMessageKind]float64{StreamTweet: 1000, StreamWarning: 500},
+	})
+	defer server.Close()
+	server.Inject(StreamFault{Partial: true})
+	stream, err := testStreamClient(server).Streams.Sample(nil)
+	assert.Nil(t, err)
+	defer stream.Stop()
+
+	for _, message := range receiveTweets(t, stream, 10) {
+		switch message.(type) {
+		case *twitter.Tweet, *twitter.StallWarning:
+		default:
+			t.Errorf("unexpected message %#v", message)
+		}
+	}
+	assert.Equal(t, 1, server.Connections())
+}
+
+func TestStreamServer_Close(t *testing.T) {
+	server := NewStreamServer(nil)
+	stream, err := testStreamClient(server).Streams.Sample(nil)
+	assert.Nil(t, err)
+	// wait for the connection before closing
+	for server.Connections() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	server.Close()
+	// reconnecting to a closed server fails and ends the stream
+	for message := range stream.Messages {
+		assert.Implements(t, (*error)(nil), message)
+	}
+	stream.Stop()
+}
+
+func TestWriteFrame(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	assert.True(t, writeFrame(recorder, recorder, map[string]int{"a": 1}, true))
+	assert.True(t, writeFrame(recorder, recorder, nil, false))
+	assert.Equal(t, "{\n\"a\":1}\r\n\r\n", recorder.Body.String())
+}
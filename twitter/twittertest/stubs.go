@@ -0,0 +1,1010 @@
+package twittertest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dghubble/go-twitter/twitter"
+)
+
+// errNotStubbed returns the error of a call to a stub method without a Func.
+func errNotStubbed(method string) error {
+	return errors.New("twittertest: " + method + " is not stubbed")
+}
+
+// APIStub is a twitter.API whose services are set by the caller, such as the
+// service stubs below, and whose Do calls DoFunc. Unset services are empty
+// stubs.
+type APIStub struct {
+	Accounts       twitter.AccountsAPI
+	Blocks         twitter.BlocksAPI
+	DirectMessages twitter.DirectMessagesAPI
+	Favorites      twitter.FavoritesAPI
+	Followers      twitter.FollowersAPI
+	Friends        twitter.FriendsAPI
+	Friendships    twitter.FriendshipsAPI
+	Lists          twitter.ListsAPI
+	RateLimits     twitter.RateLimitsAPI
+	Search         twitter.SearchAPI
+	PremiumSearch  twitter.PremiumSearchAPI
+	Statuses       twitter.StatusesAPI
+	Streams        twitter.StreamsAPI
+	Timelines      twitter.TimelinesAPI
+	Trends         twitter.TrendsAPI
+	Users          twitter.UsersAPI
+	DoFunc         func(method, path string, params *twitter.RequestParams, v interface{}) (*http.Response, error)
+}
+
+var (
+	_ twitter.API               = (*APIStub)(nil)
+	_ twitter.AccountsAPI       = (*AccountsStub)(nil)
+	_ twitter.BlocksAPI         = (*BlocksStub)(nil)
+	_ twitter.DirectMessagesAPI = (*DirectMessagesStub)(nil)
+	_ twitter.FavoritesAPI      = (*FavoritesStub)(nil)
+	_ twitter.FollowersAPI      = (*FollowersStub)(nil)
+	_ twitter.FriendsAPI        = (*FriendsStub)(nil)
+	_ twitter.FriendshipsAPI    = (*FriendshipsStub)(nil)
+	_ twitter.ListsAPI          = (*ListsStub)(nil)
+	_ twitter.RateLimitsAPI     = (*RateLimitsStub)(nil)
+	_ twitter.SearchAPI         = (*SearchStub)(nil)
+	_ twitter.PremiumSearchAPI  = (*PremiumSearchStub)(nil)
+	_ twitter.StatusesAPI       = (*StatusesStub)(nil)
+	_ twitter.StreamsAPI        = (*StreamsStub)(nil)
+	_ twitter.TimelinesAPI      = (*TimelinesStub)(nil)
+	_ twitter.TrendsAPI         = (*TrendsStub)(nil)
+	_ twitter.UsersAPI          = (*UsersStub)(nil)
+)
+
+// Do calls DoFunc.
+func (a *APIStub) Do(method, path string, params *twitter.RequestParams, v interface{}) (*http.Response, error) {
+	if a.DoFunc == nil {
+		return nil, errNotStubbed("APIStub.Do")
+	}
+	return a.DoFunc(method, path, params, v)
+}
+
+// AccountsAPI returns the Accounts service, or an empty stub if unset.
+func (a *APIStub) AccountsAPI() twitter.AccountsAPI {
+	if a.Accounts == nil {
+		return &AccountsStub{}
+	}
+	return a.Accounts
+}
+
+// BlocksAPI returns the Blocks service, or an empty stub if unset.
+func (a *APIStub) BlocksAPI() twitter.BlocksAPI {
+	if a.Blocks == nil {
+		return &BlocksStub{}
+	}
+	return a.Blocks
+}
+
+// DirectMessagesAPI returns the DirectMessages service, or an empty stub if unset.
+func (a *APIStub) DirectMessagesAPI() twitter.DirectMessagesAPI {
+	if a.DirectMessages == nil {
+		return &DirectMessagesStub{}
+	}
+	return a.DirectMessages
+}
+
+// FavoritesAPI returns the Favorites service, or an empty stub if unset.
+func (a *APIStub) FavoritesAPI() twitter.FavoritesAPI {
+	if a.Favorites == nil {
+		return &FavoritesStub{}
+	}
+	return a.Favorites
+}
+
+// FollowersAPI returns the Followers service, or an empty stub if unset.
+func (a *APIStub) FollowersAPI() twitter.FollowersAPI {
+	if a.Followers == nil {
+		return &FollowersStub{}
+	}
+	return a.Followers
+}
+
+// FriendsAPI returns the Friends service, or an empty stub if unset.
+func (a *APIStub) FriendsAPI() twitter.FriendsAPI {
+	if a.Friends == nil {
+		return &FriendsStub{}
+	}
+	return a.Friends
+}
+
+// FriendshipsAPI returns the Friendships service, or an empty stub if unset.
+func (a *APIStub) FriendshipsAPI() twitter.FriendshipsAPI {
+	if a.Friendships == nil {
+		return &FriendshipsStub{}
+	}
+	return a.Friendships
+}
+
+// ListsAPI returns the Lists service, or an empty stub if unset.
+func (a *APIStub) ListsAPI() twitter.ListsAPI {
+	if a.Lists == nil {
+		return &ListsStub{}
+	}
+	return a.Lists
+}
+
+// RateLimitsAPI returns the RateLimits service, or an empty stub if unset.
+func (a *APIStub) RateLimitsAPI() twitter.RateLimitsAPI {
+	if a.RateLimits == nil {
+		return &RateLimitsStub{}
+	}
+	return a.RateLimits
+}
+
+// SearchAPI returns the Search service, or an empty stub if unset.
+func (a *APIStub) SearchAPI() twitter.SearchAPI {
+	if a.Search == nil {
+		return &SearchStub{}
+	}
+	return a.Search
+}
+
+// PremiumSearchAPI returns the PremiumSearch service, or an empty stub if unset.
+func (a *APIStub) PremiumSearchAPI() twitter.PremiumSearchAPI {
+	if a.PremiumSearch == nil {
+		return &PremiumSearchStub{}
+	}
+	return a.PremiumSearch
+}
+
+// StatusesAPI returns the Statuses service, or an empty stub if unset.
+func (a *APIStub) StatusesAPI() twitter.StatusesAPI {
+	if a.Statuses == nil {
+		return &StatusesStub{}
+	}
+	return a.Statuses
+}
+
+// StreamsAPI returns the Streams service, or an empty stub if unset.
+func (a *APIStub) StreamsAPI() twitter.StreamsAPI {
+	if a.Streams == nil {
+		return &StreamsStub{}
+	}
+	return a.Streams
+}
+
+// TimelinesAPI returns the Timelines service, or an empty stub if unset.
+func (a *APIStub) TimelinesAPI() twitter.TimelinesAPI {
+	if a.Timelines == nil {
+		return &TimelinesStub{}
+	}
+	return a.Timelines
+}
+
+// TrendsAPI returns the Trends service, or an empty stub if unset.
+func (a *APIStub) TrendsAPI() twitter.TrendsAPI {
+	if a.Trends == nil {
+		return &TrendsStub{}
+	}
+	return a.Trends
+}
+
+// UsersAPI returns the Users service, or an empty stub if unset.
+func (a *APIStub) UsersAPI() twitter.UsersAPI {
+	if a.Users == nil {
+		return &UsersStub{}
+	}
+	return a.Users
+}
+
+// AccountsStub is a stub twitter.AccountsAPI. Its methods call the
+// corresponding Func fields, or return an error if unset.
+type AccountsStub struct {
+	UpdateProfileFunc     func(params *twitter.AccountUpdateProfileParams) (*twitter.User, *http.Response, error)
+	VerifyCredentialsFunc func(params *twitter.AccountVerifyParams) (*twitter.User, *http.Response, error)
+}
+
+// UpdateProfile calls UpdateProfileFunc.
+func (s *AccountsStub) UpdateProfile(params *twitter.AccountUpdateProfileParams) (*twitter.User, *http.Response, error) {
+	if s.UpdateProfileFunc == nil {
+		return nil, nil, errNotStubbed("AccountsStub.UpdateProfile")
+	}
+	return s.UpdateProfileFunc(params)
+}
+
+// VerifyCredentials calls VerifyCredentialsFunc.
+func (s *AccountsStub) VerifyCredentials(params *twitter.AccountVerifyParams) (*twitter.User, *http.Response, error) {
+	if s.VerifyCredentialsFunc == nil {
+		return nil, nil, errNotStubbed("AccountsStub.VerifyCredentials")
+	}
+	return s.VerifyCredentialsFunc(params)
+}
+
+// BlocksStub is a stub twitter.BlocksAPI. Its methods call the
+// corresponding Func fields, or return an error if unset.
+type BlocksStub struct {
+	CreateFunc  func(params *twitter.BlockCreateParams) (twitter.User, *http.Response, error)
+	DestroyFunc func(params *twitter.BlockDestroyParams) (twitter.User, *http.Response, error)
+}
+
+// Create calls CreateFunc.
+func (s *BlocksStub) Create(params *twitter.BlockCreateParams) (twitter.User, *http.Response, error) {
+	if s.CreateFunc == nil {
+		return twitter.User{}, nil, errNotStubbed("BlocksStub.Create")
+	}
+	return s.CreateFunc(params)
+}
+
+// Destroy calls DestroyFunc.
+func (s *BlocksStub) Destroy(params *twitter.BlockDestroyParams) (twitter.User, *http.Response, error) {
+	if s.DestroyFunc == nil {
+		return twitter.User{}, nil, errNotStubbed("BlocksStub.Destroy")
+	}
+	return s.DestroyFunc(params)
+}
+
+// DirectMessagesStub is a stub twitter.DirectMessagesAPI. Its methods call the
+// corresponding Func fields, or return an error if unset.
+type DirectMessagesStub struct {
+	DestroyFunc       func(id int64, params *twitter.DirectMessageDestroyParams) (*twitter.DirectMessage, *http.Response, error)
+	EventsDestroyFunc func(id string) (*http.Response, error)
+	EventsListFunc    func(params *twitter.DirectMessageEventsListParams) (*twitter.DirectMessageEvents, *http.Response, error)
+	EventsNewFunc     func(params *twitter.DirectMessageEventsNewParams) (*twitter.DirectMessageEvent, *http.Response, error)
+	EventsShowFunc    func(id string, params *twitter.DirectMessageEventsShowParams) (*twitter.DirectMessageEvent, *http.Response, error)
+	GetFunc           func(params *twitter.DirectMessageGetParams) ([]twitter.DirectMessage, *http.Response, error)
+	NewFunc           func(params *twitter.DirectMessageNewParams) (*twitter.DirectMessage, *http.Response, error)
+	SentFunc          func(params *twitter.DirectMessageSentParams) ([]twitter.DirectMessage, *http.Response, error)
+	ShowFunc          func(id int64) (*twitter.DirectMessage, *http.Response, error)
+}
+
+// Destroy calls DestroyFunc.
+func (s *DirectMessagesStub) Destroy(id int64, params *twitter.DirectMessageDestroyParams) (*twitter.DirectMessage, *http.Response, error) {
+	if s.DestroyFunc == nil {
+		return nil, nil, errNotStubbed("DirectMessagesStub.Destroy")
+	}
+	return s.DestroyFunc(id, params)
+}
+
+// EventsDestroy calls EventsDestroyFunc.
+func (s *DirectMessagesStub) EventsDestroy(id string) (*http.Response, error) {
+	if s.EventsDestroyFunc == nil {
+		return nil, errNotStubbed("DirectMessagesStub.EventsDestroy")
+	}
+	return s.EventsDestroyFunc(id)
+}
+
+// EventsList calls EventsListFunc.
+func (s *DirectMessagesStub) EventsList(params *twitter.DirectMessageEventsListParams) (*twitter.DirectMessageEvents, *http.Response, error) {
+	if s.EventsListFunc == nil {
+		return nil, nil, errNotStubbed("DirectMessagesStub.EventsList")
+	}
+	return s.EventsListFunc(params)
+}
+
+// EventsNew calls EventsNewFunc.
+func (s *DirectMessagesStub) EventsNew(params *twitter.DirectMessageEventsNewParams) (*twitter.DirectMessageEvent, *http.Response, error) {
+	if s.EventsNewFunc == nil {
+		return nil, nil, errNotStubbed("DirectMessagesStub.EventsNew")
+	}
+	return s.EventsNewFunc(params)
+}
+
+// EventsShow calls EventsShowFunc.
+func (s *DirectMessagesStub) EventsShow(id string, params *twitter.DirectMessageEventsShowParams) (*twitter.DirectMessageEvent, *http.Response, error) {
+	if s.EventsShowFunc == nil {
+		return nil, nil, errNotStubbed("DirectMessagesStub.EventsShow")
+	}
+	return s.EventsShowFunc(id, params)
+}
+
+// Get calls GetFunc.
+func (s *DirectMessagesStub) Get(params *twitter.DirectMessageGetParams) ([]twitter.DirectMessage, *http.Response, error) {
+	if s.GetFunc == nil {
+		return nil, nil, errNotStubbed("DirectMessagesStub.Get")
+	}
+	return s.GetFunc(params)
+}
+
+// New calls NewFunc.
+func (s *DirectMessagesStub) New(params *twitter.DirectMessageNewParams) (*twitter.DirectMessage, *http.Response, error) {
+	if s.NewFunc == nil {
+		return nil, nil, errNotStubbed("DirectMessagesStub.New")
+	}
+	return s.NewFunc(params)
+}
+
+// Sent calls SentFunc.
+func (s *DirectMessagesStub) Sent(params *twitter.DirectMessageSentParams) ([]twitter.DirectMessage, *http.Response, error) {
+	if s.SentFunc == nil {
+		return nil, nil, errNotStubbed("DirectMessagesStub.Sent")
+	}
+	return s.SentFunc(params)
+}
+
+// Show calls ShowFunc.
+func (s *DirectMessagesStub) Show(id int64) (*twitter.DirectMessage, *http.Response, error) {
+	if s.ShowFunc == nil {
+		return nil, nil, errNotStubbed("DirectMessagesStub.Show")
+	}
+	return s.ShowFunc(id)
+}
+
+// FavoritesStub is a stub twitter.FavoritesAPI. Its methods call the
+// corresponding Func fields, or return an error if unset.
+type FavoritesStub struct {
+	CreateFunc  func(params *twitter.FavoriteCreateParams) (*twitter.Tweet, *http.Response, error)
+	DestroyFunc func(params *twitter.FavoriteDestroyParams) (*twitter.Tweet, *http.Response, error)
+	ListFunc    func(params *twitter.FavoriteListParams) ([]twitter.Tweet, *http.Response, error)
+}
+
+// Create calls CreateFunc.
+func (s *FavoritesStub) Create(params *twitter.FavoriteCreateParams) (*twitter.Tweet, *http.Response, error) {
+	if s.CreateFunc == nil {
+		return nil, nil, errNotStubbed("FavoritesStub.Create")
+	}
+	return s.CreateFunc(params)
+}
+
+// Destroy calls DestroyFunc.
+func (s *FavoritesStub) Destroy(params *twitter.FavoriteDestroyParams) (*twitter.Tweet, *http.Response, error) {
+	if s.DestroyFunc == nil {
+		return nil, nil, errNotStubbed("FavoritesStub.Destroy")
+	}
+	return s.DestroyFunc(params)
+}
+
+// List calls ListFunc.
+func (s *FavoritesStub) List(params *twitter.FavoriteListParams) ([]twitter.Tweet, *http.Response, error) {
+	if s.ListFunc == nil {
+		return nil, nil, errNotStubbed("FavoritesStub.List")
+	}
+	return s.ListFunc(params)
+}
+
+// FollowersStub is a stub twitter.FollowersAPI. Its methods call the
+// corresponding Func fields, or return an error if unset.
+type FollowersStub struct {
+	IDsFunc  func(params *twitter.FollowerIDParams) (*twitter.FollowerIDs, *http.Response, error)
+	ListFunc func(params *twitter.FollowerListParams) (*twitter.Followers, *http.Response, error)
+}
+
+// IDs calls IDsFunc.
+func (s *FollowersStub) IDs(params *twitter.FollowerIDParams) (*twitter.FollowerIDs, *http.Response, error) {
+	if s.IDsFunc == nil {
+		return nil, nil, errNotStubbed("FollowersStub.IDs")
+	}
+	return s.IDsFunc(params)
+}
+
+// List calls ListFunc.
+func (s *FollowersStub) List(params *twitter.FollowerListParams) (*twitter.Followers, *http.Response, error) {
+	if s.ListFunc == nil {
+		return nil, nil, errNotStubbed("FollowersStub.List")
+	}
+	return s.ListFunc(params)
+}
+
+// FriendsStub is a stub twitter.FriendsAPI. Its methods call the
+// corresponding Func fields, or return an error if unset.
+type FriendsStub struct {
+	IDsFunc  func(params *twitter.FriendIDParams) (*twitter.FriendIDs, *http.Response, error)
+	ListFunc func(params *twitter.FriendListParams) (*twitter.Friends, *http.Response, error)
+}
+
+// IDs calls IDsFunc.
+func (s *FriendsStub) IDs(params *twitter.FriendIDParams) (*twitter.FriendIDs, *http.Response, error) {
+	if s.IDsFunc == nil {
+		return nil, nil, errNotStubbed("FriendsStub.IDs")
+	}
+	return s.IDsFunc(params)
+}
+
+// List calls ListFunc.
+func (s *FriendsStub) List(params *twitter.FriendListParams) (*twitter.Friends, *http.Response, error) {
+	if s.ListFunc == nil {
+		return nil, nil, errNotStubbed("FriendsStub.List")
+	}
+	return s.ListFunc(params)
+}
+
+// FriendshipsStub is a stub twitter.FriendshipsAPI. Its methods call the
+// corresponding Func fields, or return an error if unset.
+type FriendshipsStub struct {
+	CreateFunc   func(params *twitter.FriendshipCreateParams) (*twitter.User, *http.Response, error)
+	DestroyFunc  func(params *twitter.FriendshipDestroyParams) (*twitter.User, *http.Response, error)
+	IncomingFunc func(params *twitter.FriendshipPendingParams) (*twitter.FriendIDs, *http.Response, error)
+	LookupFunc   func(params *twitter.FriendshipLookupParams) (*[]twitter.FriendshipResponse, *http.Response, error)
+	OutgoingFunc func(params *twitter.FriendshipPendingParams) (*twitter.FriendIDs, *http.Response, error)
+	ShowFunc     func(params *twitter.FriendshipShowParams) (*twitter.Relationship, *http.Response, error)
+}
+
+// Create calls CreateFunc.
+func (s *FriendshipsStub) Create(params *twitter.FriendshipCreateParams) (*twitter.User, *http.Response, error) {
+	if s.CreateFunc == nil {
+		return nil, nil, errNotStubbed("FriendshipsStub.Create")
+	}
+	return s.CreateFunc(params)
+}
+
+// Destroy calls DestroyFunc.
+func (s *FriendshipsStub) Destroy(params *twitter.FriendshipDestroyParams) (*twitter.User, *http.Response, error) {
+	if s.DestroyFunc == nil {
+		return nil, nil, errNotStubbed("FriendshipsStub.Destroy")
+	}
+	return s.DestroyFunc(params)
+}
+
+// Incoming calls IncomingFunc.
+func (s *FriendshipsStub) Incoming(params *twitter.FriendshipPendingParams) (*twitter.FriendIDs, *http.Response, error) {
+	if s.IncomingFunc == nil {
+		return nil, nil, errNotStubbed("FriendshipsStub.Incoming")
+	}
+	return s.IncomingFunc(params)
+}
+
+// Lookup calls LookupFunc.
+func (s *FriendshipsStub) Lookup(params *twitter.FriendshipLookupParams) (*[]twitter.FriendshipResponse, *http.Response, error) {
+	if s.LookupFunc == nil {
+		return nil, nil, errNotStubbed("FriendshipsStub.Lookup")
+	}
+	return s.LookupFunc(params)
+}
+
+// Outgoing calls OutgoingFunc.
+func (s *FriendshipsStub) Outgoing(params *twitter.FriendshipPendingParams) (*twitter.FriendIDs, *http.Response, error) {
+	if s.OutgoingFunc == nil {
+		return nil, nil, errNotStubbed("FriendshipsStub.Outgoing")
+	}
+	return s.OutgoingFunc(params)
+}
+
+// Show calls ShowFunc.
+func (s *FriendshipsStub) Show(params *twitter.FriendshipShowParams) (*twitter.Relationship, *http.Response, error) {
+	if s.ShowFunc == nil {
+		return nil, nil, errNotStubbed("FriendshipsStub.Show")
+	}
+	return s.ShowFunc(params)
+}
+
+// ListsStub is a stub twitter.ListsAPI. Its methods call the
+// corresponding Func fields, or return an error if unset.
+type ListsStub struct {
+	CreateFunc             func(name string, params *twitter.ListsCreateParams) (*twitter.List, *http.Response, error)
+	DestroyFunc            func(params *twitter.ListsDestroyParams) (*twitter.List, *http.Response, error)
+	ListFunc               func(params *twitter.ListsListParams) ([]twitter.List, *http.Response, error)
+	MembersFunc            func(params *twitter.ListsMembersParams) (*twitter.Members, *http.Response, error)
+	MembersCreateFunc      func(params *twitter.ListsMembersCreateParams) (*http.Response, error)
+	MembersCreateAllFunc   func(params *twitter.ListsMembersCreateAllParams) (*http.Response, error)
+	MembersDestroyFunc     func(params *twitter.ListsMembersDestroyParams) (*http.Response, error)
+	MembersDestroyAllFunc  func(params *twitter.ListsMembersDestroyAllParams) (*http.Response, error)
+	MembersShowFunc        func(params *twitter.ListsMembersShowParams) (*twitter.User, *http.Response, error)
+	MembershipsFunc        func(params *twitter.ListsMembershipsParams) (*twitter.Membership, *http.Response, error)
+	OwnershipsFunc         func(params *twitter.ListsOwnershipsParams) (*twitter.Ownership, *http.Response, error)
+	ShowFunc               func(params *twitter.ListsShowParams) (*twitter.List, *http.Response, error)
+	StatusesFunc           func(params *twitter.ListsStatusesParams) ([]twitter.Tweet, *http.Response, error)
+	SubscribersFunc        func(params *twitter.ListsSubscribersParams) (*twitter.Subscribers, *http.Response, error)
+	SubscribersCreateFunc  func(params *twitter.ListsSubscribersCreateParams) (*twitter.List, *http.Response, error)
+	SubscribersDestroyFunc func(params *twitter.ListsSubscribersDestroyParams) (*http.Response, error)
+	SubscribersShowFunc    func(params *twitter.ListsSubscribersShowParams) (*twitter.User, *http.Response, error)
+	SubscriptionsFunc      func(params *twitter.ListsSubscriptionsParams) (*twitter.Subscribed, *http.Response, error)
+	UpdateFunc             func(params *twitter.ListsUpdateParams) (*http.Response, error)
+}
+
+// Create calls CreateFunc.
+func (s *ListsStub) Create(name string, params *twitter.ListsCreateParams) (*twitter.List, *http.Response, error) {
+	if s.CreateFunc == nil {
+		return nil, nil, errNotStubbed("ListsStub.Create")
+	}
+	return s.CreateFunc(name, params)
+}
+
+// Destroy calls DestroyFunc.
+func (s *ListsStub) Destroy(params *twitter.ListsDestroyParams) (*twitter.List, *http.Response, error) {
+	if s.DestroyFunc == nil {
+		return nil, nil, errNotStubbed("ListsStub.Destroy")
+	}
+	return s.DestroyFunc(params)
+}
+
+// List calls ListFunc.
+func (s *ListsStub) List(params *twitter.ListsListParams) ([]twitter.List, *http.Response, error) {
+	if s.ListFunc == nil {
+		return nil, nil, errNotStubbed("ListsStub.List")
+	}
+	return s.ListFunc(params)
+}
+
+// Members calls MembersFunc.
+func (s *ListsStub) Members(params *twitter.ListsMembersParams) (*twitter.Members, *http.Response, error) {
+	if s.MembersFunc == nil {
+		return nil, nil, errNotStubbed("ListsStub.Members")
+	}
+	return s.MembersFunc(params)
+}
+
+// MembersCreate calls MembersCreateFunc.
+func (s *ListsStub) MembersCreate(params *twitter.ListsMembersCreateParams) (*http.Response, error) {
+	if s.MembersCreateFunc == nil {
+		return nil, errNotStubbed("ListsStub.MembersCreate")
+	}
+	return s.MembersCreateFunc(params)
+}
+
+// MembersCreateAll calls MembersCreateAllFunc.
+func (s *ListsStub) MembersCreateAll(params *twitter.ListsMembersCreateAllParams) (*http.Response, error) {
+	if s.MembersCreateAllFunc == nil {
+		return nil, errNotStubbed("ListsStub.MembersCreateAll")
+	}
+	return s.MembersCreateAllFunc(params)
+}
+
+// MembersDestroy calls MembersDestroyFunc.
+func (s *ListsStub) MembersDestroy(params *twitter.ListsMembersDestroyParams) (*http.Response, error) {
+	if s.MembersDestroyFunc == nil {
+		return nil, errNotStubbed("ListsStub.MembersDestroy")
+	}
+	return s.MembersDestroyFunc(params)
+}
+
+// MembersDestroyAll calls MembersDestroyAllFunc.
+func (s *ListsStub) MembersDestroyAll(params *twitter.ListsMembersDestroyAllParams) (*http.Response, error) {
+	if s.MembersDestroyAllFunc == nil {
+		return nil, errNotStubbed("ListsStub.MembersDestroyAll")
+	}
+	return s.MembersDestroyAllFunc(params)
+}
+
+// MembersShow calls MembersShowFunc.
+func (s *ListsStub) MembersShow(params *twitter.ListsMembersShowParams) (*twitter.User, *http.Response, error) {
+	if s.MembersShowFunc == nil {
+		return nil, nil, errNotStubbed("ListsStub.MembersShow")
+	}
+	return s.MembersShowFunc(params)
+}
+
+// Memberships calls MembershipsFunc.
+func (s *ListsStub) Memberships(params *twitter.ListsMembershipsParams) (*twitter.Membership, *http.Response, error) {
+	if s.MembershipsFunc == nil {
+		return nil, nil, errNotStubbed("ListsStub.Memberships")
+	}
+	return s.MembershipsFunc(params)
+}
+
+// Ownerships calls OwnershipsFunc.
+func (s *ListsStub) Ownerships(params *twitter.ListsOwnershipsParams) (*twitter.Ownership, *http.Response, error) {
+	if s.OwnershipsFunc == nil {
+		return nil, nil, errNotStubbed("ListsStub.Ownerships")
+	}
+	return s.OwnershipsFunc(params)
+}
+
+// Show calls ShowFunc.
+func (s *ListsStub) Show(params *twitter.ListsShowParams) (*twitter.List, *http.Response, error) {
+	if s.ShowFunc == nil {
+		return nil, nil, errNotStubbed("ListsStub.Show")
+	}
+	return s.ShowFunc(params)
+}
+
+// Statuses calls StatusesFunc.
+func (s *ListsStub) Statuses(params *twitter.ListsStatusesParams) ([]twitter.Tweet, *http.Response, error) {
+	if s.StatusesFunc == nil {
+		return nil, nil, errNotStubbed("ListsStub.Statuses")
+	}
+	return s.StatusesFunc(params)
+}
+
+// Subscribers calls SubscribersFunc.
+func (s *ListsStub) Subscribers(params *twitter.ListsSubscribersParams) (*twitter.Subscribers, *http.Response, error) {
+	if s.SubscribersFunc == nil {
+		return nil, nil, errNotStubbed("ListsStub.Subscribers")
+	}
+	return s.SubscribersFunc(params)
+}
+
+// SubscribersCreate calls SubscribersCreateFunc.
+func (s *ListsStub) SubscribersCreate(params *twitter.ListsSubscribersCreateParams) (*twitter.List, *http.Response, error) {
+	if s.SubscribersCreateFunc == nil {
+		return nil, nil, errNotStubbed("ListsStub.SubscribersCreate")
+	}
+	return s.SubscribersCreateFunc(params)
+}
+
+// SubscribersDestroy calls SubscribersDestroyFunc.
+func (s *ListsStub) SubscribersDestroy(params *twitter.ListsSubscribersDestroyParams) (*http.Response, error) {
+	if s.SubscribersDestroyFunc == nil {
+		return nil, errNotStubbed("ListsStub.SubscribersDestroy")
+	}
+	return s.SubscribersDestroyFunc(params)
+}
+
+// SubscribersShow calls SubscribersShowFunc.
+func (s *ListsStub) SubscribersShow(params *twitter.ListsSubscribersShowParams) (*twitter.User, *http.Response, error) {
+	if s.SubscribersShowFunc == nil {
+		return nil, nil, errNotStubbed("ListsStub.SubscribersShow")
+	}
+	return s.SubscribersShowFunc(params)
+}
+
+// Subscriptions calls SubscriptionsFunc.
+func (s *ListsStub) Subscriptions(params *twitter.ListsSubscriptionsParams) (*twitter.Subscribed, *http.Response, error) {
+	if s.SubscriptionsFunc == nil {
+		return nil, nil, errNotStubbed("ListsStub.Subscriptions")
+	}
+	return s.SubscriptionsFunc(params)
+}
+
+// Update calls UpdateFunc.
+func (s *ListsStub) Update(params *twitter.ListsUpdateParams) (*http.Response, error) {
+	if s.UpdateFunc == nil {
+		return nil, errNotStubbed("ListsStub.Update")
+	}
+	return s.UpdateFunc(params)
+}
+
+// RateLimitsStub is a stub twitter.RateLimitsAPI. Its methods call the
+// corresponding Func fields, or return an error if unset.
+type RateLimitsStub struct {
+	StatusFunc func(params *twitter.RateLimitParams) (*twitter.RateLimit, *http.Response, error)
+}
+
+// Status calls StatusFunc.
+func (s *RateLimitsStub) Status(params *twitter.RateLimitParams) (*twitter.RateLimit, *http.Response, error) {
+	if s.StatusFunc == nil {
+		return nil, nil, errNotStubbed("RateLimitsStub.Status")
+	}
+	return s.StatusFunc(params)
+}
+
+// SearchStub is a stub twitter.SearchAPI. Its methods call the
+// corresponding Func fields, or return an error if unset.
+type SearchStub struct {
+	TweetsFunc func(params *twitter.SearchTweetParams) (*twitter.Search, *http.Response, error)
+}
+
+// Tweets calls TweetsFunc.
+func (s *SearchStub) Tweets(params *twitter.SearchTweetParams) (*twitter.Search, *http.Response, error) {
+	if s.TweetsFunc == nil {
+		return nil, nil, errNotStubbed("SearchStub.Tweets")
+	}
+	return s.TweetsFunc(params)
+}
+
+// PremiumSearchStub is a stub twitter.PremiumSearchAPI. Its methods call the
+// corresponding Func fields, or return an error if unset.
+type PremiumSearchStub struct {
+	Count30DaysFunc       func(params *twitter.PremiumSearchCountTweetParams, label string) (*twitter.PremiumSearchCount, *http.Response, error)
+	CountFullArchiveFunc  func(params *twitter.PremiumSearchCountTweetParams, label string) (*twitter.PremiumSearchCount, *http.Response, error)
+	Search30DaysFunc      func(params *twitter.PremiumSearchTweetParams, label string) (*twitter.PremiumSearch, *http.Response, error)
+	SearchFullArchiveFunc func(params *twitter.PremiumSearchTweetParams, label string) (*twitter.PremiumSearch, *http.Response, error)
+}
+
+// Count30Days calls Count30DaysFunc.
+func (s *PremiumSearchStub) Count30Days(params *twitter.PremiumSearchCountTweetParams, label string) (*twitter.PremiumSearchCount, *http.Response, error) {
+	if s.Count30DaysFunc == nil {
+		return nil, nil, errNotStubbed("PremiumSearchStub.Count30Days")
+	}
+	return s.Count30DaysFunc(params, label)
+}
+
+// CountFullArchive calls CountFullArchiveFunc.
+func (s *PremiumSearchStub) CountFullArchive(params *twitter.PremiumSearchCountTweetParams, label string) (*twitter.PremiumSearchCount, *http.Response, error) {
+	if s.CountFullArchiveFunc == nil {
+		return nil, nil, errNotStubbed("PremiumSearchStub.CountFullArchive")
+	}
+	return s.CountFullArchiveFunc(params, label)
+}
+
+// Search30Days calls Search30DaysFunc.
+func (s *PremiumSearchStub) Search30Days(params *twitter.PremiumSearchTweetParams, label string) (*twitter.PremiumSearch, *http.Response, error) {
+	if s.Search30DaysFunc == nil {
+		return nil, nil, errNotStubbed("PremiumSearchStub.Search30Days")
+	}
+	return s.Search30DaysFunc(params, label)
+}
+
+// SearchFullArchive calls SearchFullArchiveFunc.
+func (s *PremiumSearchStub) SearchFullArchive(params *twitter.PremiumSearchTweetParams, label string) (*twitter.PremiumSearch, *http.Response, error) {
+	if s.SearchFullArchiveFunc == nil {
+		return nil, nil, errNotStubbed("PremiumSearchStub.SearchFullArchive")
+	}
+	return s.SearchFullArchiveFunc(params, label)
+}
+
+// StatusesStub is a stub twitter.StatusesAPI. Its methods call the
+// corresponding Func fields, or return an error if unset.
+type StatusesStub struct {
+	DestroyFunc        func(id int64, params *twitter.StatusDestroyParams) (*twitter.Tweet, *http.Response, error)
+	LookupFunc         func(ids []int64, params *twitter.StatusLookupParams) ([]twitter.Tweet, *http.Response, error)
+	OEmbedFunc         func(params *twitter.StatusOEmbedParams) (*twitter.OEmbedTweet, *http.Response, error)
+	ResumeThreadFunc   func(threadErr *twitter.ThreadError) ([]twitter.Tweet, *http.Response, error)
+	RetweetFunc        func(id int64, params *twitter.StatusRetweetParams) (*twitter.Tweet, *http.Response, error)
+	RetweetersFunc     func(params *twitter.StatusRetweeterParams) (*twitter.RetweeterIDs, *http.Response, error)
+	RetweetsFunc       func(id int64, params *twitter.StatusRetweetsParams) ([]twitter.Tweet, *http.Response, error)
+	RollbackThreadFunc func(threadErr *twitter.ThreadError) (*http.Response, error)
+	ShowFunc           func(id int64, params *twitter.StatusShowParams) (*twitter.Tweet, *http.Response, error)
+	ThreadFunc         func(text string, params *twitter.ThreadParams) ([]twitter.Tweet, *http.Response, error)
+	UnretweetFunc      func(id int64, params *twitter.StatusUnretweetParams) (*twitter.Tweet, *http.Response, error)
+	UpdateFunc         func(status string, params *twitter.StatusUpdateParams) (*twitter.Tweet, *http.Response, error)
+}
+
+// Destroy calls DestroyFunc.
+func (s *StatusesStub) Destroy(id int64, params *twitter.StatusDestroyParams) (*twitter.Tweet, *http.Response, error) {
+	if s.DestroyFunc == nil {
+		return nil, nil, errNotStubbed("StatusesStub.Destroy")
+	}
+	return s.DestroyFunc(id, params)
+}
+
+// Lookup calls LookupFunc.
+func (s *StatusesStub) Lookup(ids []int64, params *twitter.StatusLookupParams) ([]twitter.Tweet, *http.Response, error) {
+	if s.LookupFunc == nil {
+		return nil, nil, errNotStubbed("StatusesStub.Lookup")
+	}
+	return s.LookupFunc(ids, params)
+}
+
+// OEmbed calls OEmbedFunc.
+func (s *StatusesStub) OEmbed(params *twitter.StatusOEmbedParams) (*twitter.OEmbedTweet, *http.Response, error) {
+	if s.OEmbedFunc == nil {
+		return nil, nil, errNotStubbed("StatusesStub.OEmbed")
+	}
+	return s.OEmbedFunc(params)
+}
+
+// ResumeThread calls ResumeThreadFunc.
+func (s *StatusesStub) ResumeThread(threadErr *twitter.ThreadError) ([]twitter.Tweet, *http.Response, error) {
+	if s.ResumeThreadFunc == nil {
+		return nil, nil, errNotStubbed("StatusesStub.ResumeThread")
+	}
+	return s.ResumeThreadFunc(threadErr)
+}
+
+// Retweet calls RetweetFunc.
+func (s *StatusesStub) Retweet(id int64, params *twitter.StatusRetweetParams) (*twitter.Tweet, *http.Response, error) {
+	if s.RetweetFunc == nil {
+		return nil, nil, errNotStubbed("StatusesStub.Retweet")
+	}
+	return s.RetweetFunc(id, params)
+}
+
+// Retweeters calls RetweetersFunc.
+func (s *StatusesStub) Retweeters(params *twitter.StatusRetweeterParams) (*twitter.RetweeterIDs, *http.Response, error) {
+	if s.RetweetersFunc == nil {
+		return nil, nil, errNotStubbed("StatusesStub.Retweeters")
+	}
+	return s.RetweetersFunc(params)
+}
+
+// Retweets calls RetweetsFunc.
+func (s *StatusesStub) Retweets(id int64, params *twitter.StatusRetweetsParams) ([]twitter.Tweet, *http.Response, error) {
+	if s.RetweetsFunc == nil {
+		return nil, nil, errNotStubbed("StatusesStub.Retweets")
+	}
+	return s.RetweetsFunc(id, params)
+}
+
+// RollbackThread calls RollbackThreadFunc.
+func (s *StatusesStub) RollbackThread(threadErr *twitter.ThreadError) (*http.Response, error) {
+	if s.RollbackThreadFunc == nil {
+		return nil, errNotStubbed("StatusesStub.RollbackThread")
+	}
+	return s.RollbackThreadFunc(threadErr)
+}
+
+// Show calls ShowFunc.
+func (s *StatusesStub) Show(id int64, params *twitter.StatusShowParams) (*twitter.Tweet, *http.Response, error) {
+	if s.ShowFunc == nil {
+		return nil, nil, errNotStubbed("StatusesStub.Show")
+	}
+	return s.ShowFunc(id, params)
+}
+
+// Thread calls ThreadFunc.
+func (s *StatusesStub) Thread(text string, params *twitter.ThreadParams) ([]twitter.Tweet, *http.Response, error) {
+	if s.ThreadFunc == nil {
+		return nil, nil, errNotStubbed("StatusesStub.Thread")
+	}
+	return s.ThreadFunc(text, params)
+}
+
+// Unretweet calls UnretweetFunc.
+func (s *StatusesStub) Unretweet(id int64, params *twitter.StatusUnretweetParams) (*twitter.Tweet, *http.Response, error) {
+	if s.UnretweetFunc == nil {
+		return nil, nil, errNotStubbed("StatusesStub.Unretweet")
+	}
+	return s.UnretweetFunc(id, params)
+}
+
+// Update calls UpdateFunc.
+func (s *StatusesStub) Update(status string, params *twitter.StatusUpdateParams) (*twitter.Tweet, *http.Response, error) {
+	if s.UpdateFunc == nil {
+		return nil, nil, errNotStubbed("StatusesStub.Update")
+	}
+	return s.UpdateFunc(status, params)
+}
+
+// StreamsStub is a stub twitter.StreamsAPI. Its methods call the
+// corresponding Func fields, or return an error if unset.
+type StreamsStub struct {
+	FilterFunc          func(params *twitter.StreamFilterParams) (*twitter.Stream, error)
+	FirehoseFunc        func(params *twitter.StreamFirehoseParams) (*twitter.Stream, error)
+	SampleFunc          func(params *twitter.StreamSampleParams) (*twitter.Stream, error)
+	ShardedFilterFunc   func(params *twitter.StreamFilterParams, extra ...*twitter.StreamService) (*twitter.ShardedStream, error)
+	SiteFunc            func(params *twitter.StreamSiteParams) (*twitter.Stream, error)
+	SiteAddUsersFunc    func(controlURI string, userIDs []int64) (*http.Response, error)
+	SiteInfoFunc        func(controlURI string) (*twitter.SiteStreamInfo, *http.Response, error)
+	SiteRemoveUsersFunc func(controlURI string, userIDs []int64) (*http.Response, error)
+	UserFunc            func(params *twitter.StreamUserParams) (*twitter.Stream, error)
+}
+
+// Filter calls FilterFunc.
+func (s *StreamsStub) Filter(params *twitter.StreamFilterParams) (*twitter.Stream, error) {
+	if s.FilterFunc == nil {
+		return nil, errNotStubbed("StreamsStub.Filter")
+	}
+	return s.FilterFunc(params)
+}
+
+// Firehose calls FirehoseFunc.
+func (s *StreamsStub) Firehose(params *twitter.StreamFirehoseParams) (*twitter.Stream, error) {
+	if s.FirehoseFunc == nil {
+		return nil, errNotStubbed("StreamsStub.Firehose")
+	}
+	return s.FirehoseFunc(params)
+}
+
+// Sample calls SampleFunc.
+func (s *StreamsStub) Sample(params *twitter.StreamSampleParams) (*twitter.Stream, error) {
+	if s.SampleFunc == nil {
+		return nil, errNotStubbed("StreamsStub.Sample")
+	}
+	return s.SampleFunc(params)
+}
+
+// ShardedFilter calls ShardedFilterFunc.
+func (s *StreamsStub) ShardedFilter(params *twitter.StreamFilterParams, extra ...*twitter.StreamService) (*twitter.ShardedStream, error) {
+	if s.ShardedFilterFunc == nil {
+		return nil, errNotStubbed("StreamsStub.ShardedFilter")
+	}
+	return s.ShardedFilterFunc(params, extra...)
+}
+
+// Site calls SiteFunc.
+func (s *StreamsStub) Site(params *twitter.StreamSiteParams) (*twitter.Stream, error) {
+	if s.SiteFunc == nil {
+		return nil, errNotStubbed("StreamsStub.Site")
+	}
+	return s.SiteFunc(params)
+}
+
+// SiteAddUsers calls SiteAddUsersFunc.
+func (s *StreamsStub) SiteAddUsers(controlURI string, userIDs []int64) (*http.Response, error) {
+	if s.SiteAddUsersFunc == nil {
+		return nil, errNotStubbed("StreamsStub.SiteAddUsers")
+	}
+	return s.SiteAddUsersFunc(controlURI, userIDs)
+}
+
+// SiteInfo calls SiteInfoFunc.
+func (s *StreamsStub) SiteInfo(controlURI string) (*twitter.SiteStreamInfo, *http.Response, error) {
+	if s.SiteInfoFunc == nil {
+		return nil, nil, errNotStubbed("StreamsStub.SiteInfo")
+	}
+	return s.SiteInfoFunc(controlURI)
+}
+
+// SiteRemoveUsers calls SiteRemoveUsersFunc.
+func (s *StreamsStub) SiteRemoveUsers(controlURI string, userIDs []int64) (*http.Response, error) {
+	if s.SiteRemoveUsersFunc == nil {
+		return nil, errNotStubbed("StreamsStub.SiteRemoveUsers")
+	}
+	return s.SiteRemoveUsersFunc(controlURI, userIDs)
+}
+
+// User calls UserFunc.
+func (s *StreamsStub) User(params *twitter.StreamUserParams) (*twitter.Stream, error) {
+	if s.UserFunc == nil {
+		return nil, errNotStubbed("StreamsStub.User")
+	}
+	return s.UserFunc(params)
+}
+
+// TimelinesStub is a stub twitter.TimelinesAPI. Its methods call the
+// corresponding Func fields, or return an error if unset.
+type TimelinesStub struct {
+	HomeTimelineFunc         func(params *twitter.HomeTimelineParams) ([]twitter.Tweet, *http.Response, error)
+	MentionTimelineFunc      func(params *twitter.MentionTimelineParams) ([]twitter.Tweet, *http.Response, error)
+	RetweetsOfMeTimelineFunc func(params *twitter.RetweetsOfMeTimelineParams) ([]twitter.Tweet, *http.Response, error)
+	UserTimelineFunc         func(params *twitter.UserTimelineParams) ([]twitter.Tweet, *http.Response, error)
+}
+
+// HomeTimeline calls HomeTimelineFunc.
+func (s *TimelinesStub) HomeTimeline(params *twitter.HomeTimelineParams) ([]twitter.Tweet, *http.Response, error) {
+	if s.HomeTimelineFunc == nil {
+		return nil, nil, errNotStubbed("TimelinesStub.HomeTimeline")
+	}
+	return s.HomeTimelineFunc(params)
+}
+
+// MentionTimeline calls MentionTimelineFunc.
+func (s *TimelinesStub) MentionTimeline(params *twitter.MentionTimelineParams) ([]twitter.Tweet, *http.Response, error) {
+	if s.MentionTimelineFunc == nil {
+		return nil, nil, errNotStubbed("TimelinesStub.MentionTimeline")
+	}
+	return s.MentionTimelineFunc(params)
+}
+
+// RetweetsOfMeTimeline calls RetweetsOfMeTimelineFunc.
+func (s *TimelinesStub) RetweetsOfMeTimeline(params *twitter.RetweetsOfMeTimelineParams) ([]twitter.Tweet, *http.Response, error) {
+	if s.RetweetsOfMeTimelineFunc == nil {
+		return nil, nil, errNotStubbed("TimelinesStub.RetweetsOfMeTimeline")
+	}
+	return s.RetweetsOfMeTimelineFunc(params)
+}
+
+// UserTimeline calls UserTimelineFunc.
+func (s *TimelinesStub) UserTimeline(params *twitter.UserTimelineParams) ([]twitter.Tweet, *http.Response, error) {
+	if s.UserTimelineFunc == nil {
+		return nil, nil, errNotStubbed("TimelinesStub.UserTimeline")
+	}
+	return s.UserTimelineFunc(params)
+}
+
+// TrendsStub is a stub twitter.TrendsAPI. Its methods call the
+// corresponding Func fields, or return an error if unset.
+type TrendsStub struct {
+	AvailableFunc func() ([]twitter.Location, *http.Response, error)
+	ClosestFunc   func(params *twitter.ClosestParams) ([]twitter.Location, *http.Response, error)
+	PlaceFunc     func(woeid int64, params *twitter.TrendsPlaceParams) ([]twitter.TrendsList, *http.Response, error)
+}
+
+// Available calls AvailableFunc.
+func (s *TrendsStub) Available() ([]twitter.Location, *http.Response, error) {
+	if s.AvailableFunc == nil {
+		return nil, nil, errNotStubbed("TrendsStub.Available")
+	}
+	return s.AvailableFunc()
+}
+
+// Closest calls ClosestFunc.
+func (s *TrendsStub) Closest(params *twitter.ClosestParams) ([]twitter.Location, *http.Response, error) {
+	if s.ClosestFunc == nil {
+		return nil, nil, errNotStubbed("TrendsStub.Closest")
+	}
+	return s.ClosestFunc(params)
+}
+
+// Place calls PlaceFunc.
+func (s *TrendsStub) Place(woeid int64, params *twitter.TrendsPlaceParams) ([]twitter.TrendsList, *http.Response, error) {
+	if s.PlaceFunc == nil {
+		return nil, nil, errNotStubbed("TrendsStub.Place")
+	}
+	return s.PlaceFunc(woeid, params)
+}
+
+// UsersStub is a stub twitter.UsersAPI. Its methods call the
+// corresponding Func fields, or return an error if unset.
+type UsersStub struct {
+	LookupFunc func(params *twitter.UserLookupParams) ([]twitter.User, *http.Response, error)
+	SearchFunc func(query string, params *twitter.UserSearchParams) ([]twitter.User, *http.Response, error)
+	ShowFunc   func(params *twitter.UserShowParams) (*twitter.User, *http.Response, error)
+}
+
+// Lookup calls LookupFunc.
+func (s *UsersStub) Lookup(params *twitter.UserLookupParams) ([]twitter.User, *http.Response, error) {
+	if s.LookupFunc == nil {
+		return nil, nil, errNotStubbed("UsersStub.Lookup")
+	}
+	return s.LookupFunc(params)
+}
+
+// Search calls SearchFunc.
+func (s *UsersStub) Search(query string, params *twitter.UserSearchParams) ([]twitter.User, *http.Response, error) {
+	if s.SearchFunc == nil {
+		return nil, nil, errNotStubbed("UsersStub.Search")
+	}
+	return s.SearchFunc(query, params)
+}
+
+// Show calls ShowFunc.
+func (s *UsersStub) Show(params *twitter.UserShowParams) (*twitter.User, *http.Response, error) {
+	if s.ShowFunc == nil {
+		return nil, nil, errNotStubbed("UsersStub.Show")
+	}
+	return s.ShowFunc(params)
+}
@@ -0,0 +1,65 @@
+package twittertest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/dghubble/go-twitter/twitter"
+	"github.com/stretchr/testify/assert"
+)
+
+// postGreeting is application code which depends on the twitter.API.
+func postGreeting(api twitter.API, screenName string) (*twitter.Tweet, error) {
+	user, _, err := api.UsersAPI().Show(&twitter.UserShowParams{ScreenName: screenName})
+	if err != nil {
+		return nil, err
+	}
+	tweet, _, err := api.StatusesAPI().Update("hello @"+user.ScreenName, nil)
+	return tweet, err
+}
+
+func TestAPIStub(t *testing.T) {
+	var updates []string
+	api := &APIStub{
+		Users: &UsersStub{
+			ShowFunc: func(params *twitter.UserShowParams) (*twitter.User, *http.Response, error) {
+				return &twitter.User{ScreenName: params.ScreenName}, nil, nil
+			},
+		},
+		Statuses: &StatusesStub{
+			UpdateFunc: func(status string, params *twitter.StatusUpdateParams) (*twitter.Tweet, *http.Response, error) {
+				updates = append(updates, status)
+				return &twitter.Tweet{Text: status}, nil, nil
+			},
+		},
+	}
+	tweet, err := postGreeting(api, "gopher")
+	assert.Nil(t, err)
+	assert.Equal(t, "hello @gopher", tweet.Text)
+	assert.Equal(t, []string{"hello @gopher"}, updates)
+}
+
+func TestAPIStub_notStubbed(t *testing.T) {
+	api := &APIStub{Users: &UsersStub{}}
+	_, err := postGreeting(api, "gopher")
+	assert.EqualError(t, err, "twittertest: UsersStub.Show is not stubbed")
+
+	// unset services are empty stubs
+	_, _, err = api.TimelinesAPI().HomeTimeline(nil)
+	assert.EqualError(t, err, "twittertest: TimelinesStub.HomeTimeline is not stubbed")
+	_, err = api.Do("GET", "mutes/users/ids.json", nil, nil)
+	assert.EqualError(t, err, "twittertest: APIStub.Do is not stubbed")
+	user, _, err := api.BlocksAPI().Create(nil)
+	assert.Equal(t, twitter.User{}, user)
+	assert.NotNil(t, err)
+}
+
+func TestAPIStub_Client(t *testing.T) {
+	// stubs can delegate to a Client, such as one of a fake Server
+	server, client, _, _ := testClient()
+	defer server.Close()
+	api := &APIStub{Users: client.Users, Statuses: client.Statuses}
+	tweet, err := postGreeting(api, "bob")
+	assert.Nil(t, err)
+	assert.Equal(t, "hello @bob", tweet.Text)
+}
@@ -0,0 +1,322 @@
+package twittertest
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/dghubble/go-twitter/twitter"
+)
+
+var (
+	errFollowSelf = &apiError{http.StatusForbidden, 158, "You can't follow yourself."}
+	errBlocked    = &apiError{http.StatusForbidden, 162, "You have been blocked from following this account at the request of the user."}
+)
+
+func (s *Server) accountVerifyCredentials(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	return s.renderUser(userID), nil
+}
+
+func (s *Server) usersShow(req *http.Request, id string) (interface{}, *apiError) {
+	if req.Form.Get("user_id") == "" && req.Form.Get("screen_name") == "" {
+		return nil, errUserNotFound
+	}
+	userID, err := s.findUser(req.Form, "user_id", "screen_name")
+	if err != nil {
+		return nil, err
+	}
+	return s.renderUser(userID), nil
+}
+
+// usersParam returns the IDs of the existing users identified by the comma
+// separated user_id and screen_name parameters.
+func (s *Server) usersParam(form url.Values) []int64 {
+	var ids []int64
+	for _, userID := range int64sParam(form, "user_id") {
+		if s.users[userID] != nil {
+			ids = addID(ids, userID)
+		}
+	}
+	for _, name := range strings.Split(form.Get("screen_name"), ",") {
+		if userID, ok := s.userIDs[strings.ToLower(strings.TrimSpace(name))]; ok {
+			ids = addID(ids, userID)
+		}
+	}
+	return ids
+}
+
+func (s *Server) usersLookup(req *http.Request, id string) (interface{}, *apiError) {
+	ids := s.usersParam(req.Form)
+	if len(ids) == 0 {
+		return nil, errNoUserMatches
+	}
+	return s.renderUsers(ids), nil
+}
+
+// usersSearch matches users whose screen name or name contains the query,
+// ignoring case, ordered by ID.
+func (s *Server) usersSearch(req *http.Request, id string) (interface{}, *apiError) {
+	query := strings.ToLower(req.Form.Get("q"))
+	var ids []int64
+	for _, userID := range s.userIDs {
+		user := s.users[userID]
+		if strings.Contains(strings.ToLower(user.ScreenName), query) || strings.Contains(strings.ToLower(user.Name), query) {
+			ids = append(ids, userID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	count := countParam(req.Form, 20, 20)
+	page := int(int64Param(req.Form, "page"))
+	if page < 1 {
+		page = 1
+	}
+	start, end := (page-1)*count, page*count
+	if start > len(ids) {
+		start = len(ids)
+	}
+	if end > len(ids) {
+		end = len(ids)
+	}
+	return s.renderUsers(ids[start:end]), nil
+}
+
+// friendshipsCreate follows the user, or requests to follow a protected
+// user.
+func (s *Server) friendshipsCreate(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	targetID, err := s.findUser(req.Form, "user_id", "screen_name")
+	if err != nil {
+		return nil, err
+	}
+	if targetID == userID {
+		return nil, errFollowSelf
+	}
+	if hasID(s.blocking[targetID], userID) {
+		return nil, errBlocked
+	}
+	if s.users[targetID].Protected && !hasID(s.following[userID], targetID) {
+		s.requests[userID] = addID(s.requests[userID], targetID)
+	} else {
+		s.following[userID] = addID(s.following[userID], targetID)
+	}
+	return s.renderUser(targetID), nil
+}
+
+func (s *Server) friendshipsDestroy(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	targetID, err := s.findUser(req.Form, "user_id", "screen_name")
+	if err != nil {
+		return nil, err
+	}
+	s.following[userID] = removeID(s.following[userID], targetID)
+	s.requests[userID] = removeID(s.requests[userID], targetID)
+	return s.renderUser(targetID), nil
+}
+
+// friendshipsLookup returns the authenticated user's connections to the
+// users.
+func (s *Server) friendshipsLookup(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	ids := s.usersParam(req.Form)
+	if len(ids) > 100 {
+		ids = ids[:100]
+	}
+	friendships := make([]twitter.FriendshipResponse, 0, len(ids))
+	for _, targetID := range ids {
+		target := s.users[targetID]
+		var connections []string
+		if hasID(s.following[userID], targetID) {
+			connections = append(connections, "following")
+		}
+		if hasID(s.requests[userID], targetID) {
+			connections = append(connections, "following_requested")
+		}
+		if hasID(s.following[targetID], userID) {
+			connections = append(connections, "followed_by")
+		}
+		if hasID(s.blocking[userID], targetID) {
+			connections = append(connections, "blocking")
+		}
+		if len(connections) == 0 {
+			connections = []string{"none"}
+		}
+		friendships = append(friendships, twitter.FriendshipResponse{
+			ID:          target.ID,
+			IDStr:       target.IDStr,
+			ScreenName:  target.ScreenName,
+			Name:        target.Name,
+			Connections: connections,
+		})
+	}
+	return friendships, nil
+}
+
+// friendshipsIncoming returns the IDs of the users who requested to follow
+// the authenticated user, newest first.
+func (s *Server) friendshipsIncoming(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	var ids []int64
+	for requester, targets := range s.requests {
+		if hasID(targets, userID) {
+			ids = append(ids, requester)
+		}
+	}
+	// newest IDs first, since request times are not kept
+	sort.Slice(ids, func(i, j int) bool { return ids[i] > ids[j] })
+	start, end, page := cursorPage(req.Form, len(ids), 5000, 5000)
+	return &struct {
+		IDs []int64 `json:"ids"`
+		cursors
+	}{ids[start:end], page}, nil
+}
+
+// friendshipsOutgoing returns the IDs of the protected users the
+// authenticated user requested to follow, newest first.
+func (s *Server) friendshipsOutgoing(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	ids := reversed(s.requests[userID])
+	start, end, page := cursorPage(req.Form, len(ids), 5000, 5000)
+	return &struct {
+		IDs []int64 `json:"ids"`
+		cursors
+	}{ids[start:end], page}, nil
+}
+
+func (s *Server) friendshipsShow(req *http.Request, id string) (interface{}, *apiError) {
+	sourceID, err := s.findUser(req.Form, "source_id", "source_screen_name")
+	if err != nil {
+		return nil, err
+	}
+	targetID, err := s.findUser(req.Form, "target_id", "target_screen_name")
+	if err != nil {
+		return nil, err
+	}
+	source, target := s.users[sourceID], s.users[targetID]
+	following := hasID(s.following[sourceID], targetID)
+	followedBy := hasID(s.following[targetID], sourceID)
+	return &twitter.RelationshipResponse{
+		Relationship: &twitter.Relationship{
+			Source: twitter.RelationshipSource{
+				ID:           source.ID,
+				IDStr:        source.IDStr,
+				ScreenName:   source.ScreenName,
+				Following:    following,
+				FollowedBy:   followedBy,
+				CanDM:        followedBy && !hasID(s.blocking[targetID], sourceID),
+				Blocking:     hasID(s.blocking[sourceID], targetID),
+				WantRetweets: following,
+			},
+			Target: twitter.RelationshipTarget{
+				ID:         target.ID,
+				IDStr:      target.IDStr,
+				ScreenName: target.ScreenName,
+				Following:  followedBy,
+				FollowedBy: following,
+			},
+		},
+	}, nil
+}
+
+func (s *Server) friendsIDs(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.findUser(req.Form, "user_id", "screen_name")
+	if err != nil {
+		return nil, err
+	}
+	ids := reversed(s.following[userID])
+	start, end, page := cursorPage(req.Form, len(ids), 5000, 5000)
+	return &struct {
+		IDs []int64 `json:"ids"`
+		cursors
+	}{ids[start:end], page}, nil
+}
+
+func (s *Server) friendsList(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.findUser(req.Form, "user_id", "screen_name")
+	if err != nil {
+		return nil, err
+	}
+	ids := reversed(s.following[userID])
+	start, end, page := cursorPage(req.Form, len(ids), 20, 200)
+	return &struct {
+		Users []twitter.User `json:"users"`
+		cursors
+	}{s.renderUsers(ids[start:end]), page}, nil
+}
+
+func (s *Server) followersIDs(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.findUser(req.Form, "user_id", "screen_name")
+	if err != nil {
+		return nil, err
+	}
+	ids := s.followers(userID)
+	start, end, page := cursorPage(req.Form, len(ids), 5000, 5000)
+	return &struct {
+		IDs []int64 `json:"ids"`
+		cursors
+	}{ids[start:end], page}, nil
+}
+
+func (s *Server) followersList(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.findUser(req.Form, "user_id", "screen_name")
+	if err != nil {
+		return nil, err
+	}
+	ids := s.followers(userID)
+	start, end, page := cursorPage(req.Form, len(ids), 20, 200)
+	return &struct {
+		Users []twitter.User `json:"users"`
+		cursors
+	}{s.renderUsers(ids[start:end]), page}, nil
+}
+
+// blocksCreate blocks the user, which also removes follows and follow
+// requests between the users.
+func (s *Server) blocksCreate(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	targetID, err := s.findUser(req.Form, "user_id", "screen_name")
+	if err != nil {
+		return nil, err
+	}
+	s.blocking[userID] = addID(s.blocking[userID], targetID)
+	s.following[userID] = removeID(s.following[userID], targetID)
+	s.following[targetID] = removeID(s.following[targetID], userID)
+	s.requests[userID] = removeID(s.requests[userID], targetID)
+	s.requests[targetID] = removeID(s.requests[targetID], userID)
+	return s.renderUser(targetID), nil
+}
+
+func (s *Server) blocksDestroy(req *http.Request, id string) (interface{}, *apiError) {
+	userID, err := s.authUser()
+	if err != nil {
+		return nil, err
+	}
+	targetID, err := s.findUser(req.Form, "user_id", "screen_name")
+	if err != nil {
+		return nil, err
+	}
+	s.blocking[userID] = removeID(s.blocking[userID], targetID)
+	return s.renderUser(targetID), nil
+}
@@ -0,0 +1,187 @@
+package twittertest
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/dghubble/go-twitter/twitter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsers_ShowLookupSearch(t *testing.T) {
+	server, client, alice, bob := testClient()
+	defer server.Close()
+
+	user, _, err := client.Users.Show(&twitter.UserShowParams{UserID: bob.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, "bob", user.ScreenName)
+	_, resp, err := client.Users.Show(&twitter.UserShowParams{ScreenName: "nobody"})
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, twitter.APIError{Errors: []twitter.ErrorDetail{{Code: 50, Message: "User not found."}}}, err)
+
+	users, _, err := client.Users.Lookup(&twitter.UserLookupParams{UserID: []int64{bob.ID}, ScreenName: []string{"alice", "nobody"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{bob.ID, alice.ID}, userIDs(users))
+	_, resp, _ = client.Users.Lookup(&twitter.UserLookupParams{ScreenName: []string{"nobody"}})
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	users, _, err = client.Users.Search("BO", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{bob.ID}, userIDs(users))
+
+	verified, _, err := client.Accounts.VerifyCredentials(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, alice.ID, verified.ID)
+}
+
+func TestFriendships(t *testing.T) {
+	server, client, alice, bob := testClient()
+	defer server.Close()
+
+	followed, _, err := client.Friendships.Create(&twitter.FriendshipCreateParams{ScreenName: "bob"})
+	assert.Nil(t, err)
+	assert.Equal(t, bob.ID, followed.ID)
+	assert.True(t, followed.Following)
+	assert.Equal(t, 1, followed.FollowersCount)
+	_, resp, _ := client.Friendships.Create(&twitter.FriendshipCreateParams{UserID: alice.ID})
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	relationship, _, err := client.Friendships.Show(&twitter.FriendshipShowParams{TargetID: bob.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, alice.ID, relationship.Source.ID)
+	assert.True(t, relationship.Source.Following)
+	assert.False(t, relationship.Source.FollowedBy)
+	assert.True(t, relationship.Target.FollowedBy)
+
+	followers, _, err := client.Followers.IDs(&twitter.FollowerIDParams{UserID: bob.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{alice.ID}, followers.IDs)
+
+	unfollowed, _, err := client.Friendships.Destroy(&twitter.FriendshipDestroyParams{UserID: bob.ID})
+	assert.Nil(t, err)
+	assert.False(t, unfollowed.Following)
+	friends, _, err := client.Friends.IDs(nil)
+	assert.Nil(t, err)
+	assert.Empty(t, friends.IDs)
+}
+
+func TestFriendships_LookupPending(t *testing.T) {
+	server, client, alice, bob := testClient()
+	defer server.Close()
+	carol := server.AddUser(&twitter.User{ScreenName: "carol", Protected: true})
+	server.Follow(bob.ID, alice.ID)
+
+	requested, _, err := client.Friendships.Create(&twitter.FriendshipCreateParams{UserID: carol.ID})
+	assert.Nil(t, err)
+	assert.False(t, requested.Following)
+	assert.True(t, requested.FollowRequestSent)
+	outgoing, _, err := client.Friendships.Outgoing(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{carol.ID}, outgoing.IDs)
+
+	friendships, _, err := client.Friendships.Lookup(&twitter.FriendshipLookupParams{
+		UserID:     []int64{bob.ID},
+		ScreenName: []string{"carol", "nobody"},
+	})
+	assert.Nil(t, err)
+	if assert.Len(t, *friendships, 2) {
+		assert.Equal(t, "bob", (*friendships)[0].ScreenName)
+		assert.Equal(t, []string{"followed_by"}, (*friendships)[0].Connections)
+		assert.Equal(t, []string{"following_requested"}, (*friendships)[1].Connections)
+	}
+
+	server.SetAuthUser(carol.ID)
+	incoming, _, err := client.Friendships.Incoming(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{alice.ID}, incoming.IDs)
+
+	server.AcceptFollowRequest(alice.ID, carol.ID)
+	incoming, _, err = client.Friendships.Incoming(nil)
+	assert.Nil(t, err)
+	assert.Empty(t, incoming.IDs)
+	server.SetAuthUser(alice.ID)
+	friendships, _, err = client.Friendships.Lookup(&twitter.FriendshipLookupParams{UserID: []int64{carol.ID}})
+	assert.Nil(t, err)
+	if assert.Len(t, *friendships, 1) {
+		assert.Equal(t, []string{"following"}, (*friendships)[0].Connections)
+	}
+}
+
+func TestFriends_Cursors(t *testing.T) {
+	server, client, alice, _ := testClient()
+	defer server.Close()
+	var followed []int64
+	for i := 0; i < 5; i++ {
+		user := server.AddUser(&twitter.User{})
+		server.Follow(alice.ID, user.ID)
+		followed = append([]int64{user.ID}, followed...)
+	}
+
+	// page through friends, most recently followed first
+	var ids []int64
+	params := &twitter.FriendListParams{Count: 2}
+	for {
+		friends, _, err := client.Friends.List(params)
+		assert.Nil(t, err)
+		ids = append(ids, userIDs(friends.Users)...)
+		if friends.NextCursor == 0 {
+			break
+		}
+		assert.Equal(t, friends.NextCursorStr, strconv.FormatInt(friends.NextCursor, 10))
+		params.Cursor = friends.NextCursor
+	}
+	assert.Equal(t, followed, ids)
+
+	friendIDs, _, err := client.Friends.IDs(&twitter.FriendIDParams{Count: 3})
+	assert.Nil(t, err)
+	assert.Equal(t, followed[:3], friendIDs.IDs)
+	assert.Equal(t, int64(0), friendIDs.PreviousCursor)
+	friendIDs, _, err = client.Friends.IDs(&twitter.FriendIDParams{Count: 3, Cursor: friendIDs.NextCursor})
+	assert.Nil(t, err)
+	assert.Equal(t, followed[3:], friendIDs.IDs)
+	assert.Equal(t, int64(0), friendIDs.NextCursor)
+	friendIDs, _, err = client.Friends.IDs(&twitter.FriendIDParams{Count: 3, Cursor: friendIDs.PreviousCursor})
+	assert.Nil(t, err)
+	assert.Equal(t, followed[:3], friendIDs.IDs)
+
+	followers, _, err := client.Followers.List(&twitter.FollowerListParams{UserID: followed[0]})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{alice.ID}, userIDs(followers.Users))
+}
+
+func TestBlocks(t *testing.T) {
+	server, client, alice, bob := testClient()
+	defer server.Close()
+	server.Follow(alice.ID, bob.ID)
+	server.Follow(bob.ID, alice.ID)
+
+	blocked, _, err := client.Blocks.Create(&twitter.BlockCreateParams{UserID: bob.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, bob.ID, blocked.ID)
+	assert.False(t, blocked.Following)
+	relationship, _, err := client.Friendships.Show(&twitter.FriendshipShowParams{TargetID: bob.ID})
+	assert.Nil(t, err)
+	assert.True(t, relationship.Source.Blocking)
+	assert.False(t, relationship.Source.FollowedBy)
+
+	// blocked users cannot follow
+	server.SetAuthUser(bob.ID)
+	_, resp, _ := client.Friendships.Create(&twitter.FriendshipCreateParams{UserID: alice.ID})
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	server.SetAuthUser(alice.ID)
+	_, _, err = client.Blocks.Destroy(&twitter.BlockDestroyParams{ScreenName: "bob"})
+	assert.Nil(t, err)
+	relationship, _, err = client.Friendships.Show(&twitter.FriendshipShowParams{TargetScreenName: "bob"})
+	assert.Nil(t, err)
+	assert.False(t, relationship.Source.Blocking)
+}
+
+func userIDs(users []twitter.User) []int64 {
+	var ids []int64
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	return ids
+}
@@ -0,0 +1,221 @@
+package twittertext
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// EntityType is the type of an Entity.
+type EntityType int
+
+// Entity types.
+const (
+	Hashtag EntityType = iota
+	Mention
+	Cashtag
+	URL
+)
+
+// Entity is a hashtag, mention, cashtag, or URL in text.
+type Entity struct {
+	Type EntityType
+	// hashtag, screen name, or cashtag without its '#', '@', or '$', or the
+	// URL
+	Text string
+	// start (inclusive) and end (exclusive) offsets of the entity in the
+	// text, in code points, including any '#', '@', or '$'
+	Indices [2]int
+}
+
+var (
+	hashtagPattern = regexp.MustCompile(`(?:^|[^&\p{L}\p{M}\p{Nd}_])([#＃])([\p{L}\p{M}\p{Nd}_]*[\p{L}\p{M}][\p{L}\p{M}\p{Nd}_]*)`)
+	mentionPattern = regexp.MustCompile(`(?:^|[^a-zA-Z0-9_!#$%&*@＠]|(?:^|[^a-zA-Z0-9_+~.-])(?i:rt):?)([@＠])([a-zA-Z0-9_]{1,20})(/[a-zA-Z][a-zA-Z0-9_-]{0,24})?`)
+	cashtagPattern = regexp.MustCompile(`(?:^|\s)(\$)([a-zA-Z]{1,6}(?:[._][a-zA-Z]{1,2})?)`)
+	urlPattern     = regexp.MustCompile(`(?:^|[^\p{L}\p{N}@＠$#＃_./-])((?i:https?://)?((?:[\p{L}\p{N}](?:[\p{L}\p{N}_-]*[\p{L}\p{N}])?\.)+\p{L}{2,})(?::[0-9]{1,5})?(?:/[\p{Latin}\p{Cyrillic}\p{N}\-._~!$&'()*+,;=:@%/?#\[\]|]*)?)`)
+)
+
+// generic top level domains recognized in URLs without a scheme. All two
+// letter country code domains are recognized.
+var genericTLDs = map[string]bool{
+	"aero": true, "app": true, "art": true, "asia": true, "biz": true,
+	"blog": true, "cat": true, "club": true, "com": true, "coop": true,
+	"design": true, "dev": true, "edu": true, "gov": true, "info": true,
+	"int": true, "jobs": true, "live": true, "mil": true, "mobi": true,
+	"museum": true, "name": true, "net": true, "news": true, "online": true,
+	"org": true, "page": true, "post": true, "pro": true, "shop": true,
+	"site": true, "store": true, "tech": true, "tel": true, "travel": true,
+	"wiki": true, "xxx": true, "xyz": true,
+}
+
+// country code top level domains whose second level domains are URLs
+// without a scheme or path, e.g. "t.co"
+var specialCCTLDs = map[string]bool{"co": true, "tv": true}
+
+// Extract returns the hashtags, mentions, cashtags, and URLs in the text,
+// in order.
+func Extract(text string) []Entity {
+	urls := ExtractURLs(text)
+	entities := append([]Entity(nil), urls...)
+	entities = append(entities, removeOverlaps(extractHashtags(text), urls)...)
+	entities = append(entities, removeOverlaps(extractMentions(text), urls)...)
+	entities = append(entities, removeOverlaps(extractCashtags(text), urls)...)
+	sort.SliceStable(entities, func(i, j int) bool {
+		return entities[i].Indices[0] < entities[j].Indices[0]
+	})
+	return entities
+}
+
+// ExtractHashtags returns the hashtags in the text, excluding those inside
+// URLs.
+func ExtractHashtags(text string) []Entity {
+	return removeOverlaps(extractHashtags(text), ExtractURLs(text))
+}
+
+// ExtractMentions returns the mentioned screen names in the text, excluding
+// mentions of Lists (e.g. "@user/list") and those inside URLs.
+func ExtractMentions(text string) []Entity {
+	return removeOverlaps(extractMentions(text), ExtractURLs(text))
+}
+
+// ExtractCashtags returns the cashtags in the text, excluding those inside
+// URLs.
+func ExtractCashtags(text string) []Entity {
+	return removeOverlaps(extractCashtags(text), ExtractURLs(text))
+}
+
+// ExtractURLs returns the URLs in the text, with or without a scheme.
+// URLs without a scheme must have a known top level domain, and a second
+// level domain under a country code (e.g. "ok.so") must have a path unless
+// it is a short domain like "t.co".
+func ExtractURLs(text string) []Entity {
+	var entities []Entity
+	for _, match := range urlPattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := match[2], match[3]
+		url := text[start:end]
+		shortDomainEnd := -1
+		if !hasScheme(url) {
+			// shorten the host to its last known top level domain
+			host := text[match[4]:match[5]]
+			labels := strings.Split(host, ".")
+			k := len(labels)
+			for k >= 2 && !isKnownTLD(labels[k-1]) {
+				k--
+			}
+			if k < 2 {
+				continue
+			}
+			if k < len(labels) {
+				end = match[4] + len(strings.Join(labels[:k], "."))
+			}
+			if tld := strings.ToLower(labels[k-1]); k == 2 && len(tld) == 2 && !specialCCTLDs[tld] {
+				shortDomainEnd = match[4] + len(strings.Join(labels[:k], "."))
+			}
+		}
+		end = start + len(trimURL(text[start:end]))
+		if end <= shortDomainEnd {
+			// e.g. "ok.so what" is a sentence, not a URL
+			continue
+		}
+		entities = append(entities, Entity{Type: URL, Text: text[start:end], Indices: runeOffsets(text, start, end)})
+	}
+	return entities
+}
+
+func extractHashtags(text string) []Entity {
+	var entities []Entity
+	for _, match := range hashtagPattern.FindAllStringSubmatchIndex(text, -1) {
+		rest := text[match[1]:]
+		if strings.HasPrefix(rest, "#") || strings.HasPrefix(rest, "＃") || strings.HasPrefix(rest, "://") {
+			continue
+		}
+		entities = append(entities, Entity{Type: Hashtag, Text: text[match[4]:match[5]], Indices: runeOffsets(text, match[2], match[5])})
+	}
+	return entities
+}
+
+func extractMentions(text string) []Entity {
+	var entities []Entity
+	for _, match := range mentionPattern.FindAllStringSubmatchIndex(text, -1) {
+		if match[6] >= 0 {
+			// List mention
+			continue
+		}
+		rest := text[match[1]:]
+		if r, _ := utf8.DecodeRuneInString(rest); r == '@' || r == '＠' || unicode.Is(unicode.Latin, r) || strings.HasPrefix(rest, "://") {
+			continue
+		}
+		entities = append(entities, Entity{Type: Mention, Text: text[match[4]:match[5]], Indices: runeOffsets(text, match[2], match[5])})
+	}
+	return entities
+}
+
+func extractCashtags(text string) []Entity {
+	var entities []Entity
+	for _, match := range cashtagPattern.FindAllStringSubmatchIndex(text, -1) {
+		if r, size := utf8.DecodeRuneInString(text[match[1]:]); size > 0 && !unicode.IsSpace(r) && !unicode.IsPunct(r) {
+			continue
+		}
+		entities = append(entities, Entity{Type: Cashtag, Text: text[match[4]:match[5]], Indices: runeOffsets(text, match[2], match[5])})
+	}
+	return entities
+}
+
+// removeOverlaps returns the entities which don't overlap the URLs.
+func removeOverlaps(entities, urls []Entity) []Entity {
+	var kept []Entity
+	for _, entity := range entities {
+		overlaps := false
+		for _, url := range urls {
+			if entity.Indices[0] < url.Indices[1] && url.Indices[0] < entity.Indices[1] {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			kept = append(kept, entity)
+		}
+	}
+	return kept
+}
+
+// hasScheme returns true if the URL starts with http:// or https://.
+func hasScheme(url string) bool {
+	url = strings.ToLower(url)
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")
+}
+
+// isKnownTLD returns true for country code and known generic top level
+// domains.
+func isKnownTLD(tld string) bool {
+	tld = strings.ToLower(tld)
+	if len(tld) == 2 && tld[0] >= 'a' && tld[0] <= 'z' && tld[1] >= 'a' && tld[1] <= 'z' {
+		return true
+	}
+	return genericTLDs[tld]
+}
+
+// trimURL trims trailing punctuation which ends a sentence rather than the
+// URL, including closing brackets without an opening bracket in the URL.
+func trimURL(url string) string {
+	for url != "" {
+		last := url[len(url)-1]
+		switch {
+		case strings.IndexByte(".,:;!?'\"", last) >= 0:
+		case last == ')' && strings.Count(url, "(") < strings.Count(url, ")"):
+		case last == ']' && strings.Count(url, "[") < strings.Count(url, "]"):
+		default:
+			return url
+		}
+		url = url[:len(url)-1]
+	}
+	return url
+}
+
+// runeOffsets converts byte offsets in the text to code point offsets.
+func runeOffsets(text string, start, end int) [2]int {
+	first := utf8.RuneCountInString(text[:start])
+	return [2]int{first, first + utf8.RuneCountInString(text[start:end])}
+}
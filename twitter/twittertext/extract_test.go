@@ -0,0 +1,84 @@
+package twittertext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtract(t *testing.T) {
+	text := "🐹 @gopher loves #golang and $GOOG, see https://go.dev/doc/#intro."
+	expected := []Entity{
+		{Mention, "gopher", [2]int{2, 9}},
+		{Hashtag, "golang", [2]int{16, 23}},
+		{Cashtag, "GOOG", [2]int{28, 33}},
+		{URL, "https://go.dev/doc/#intro", [2]int{39, 64}},
+	}
+	assert.Equal(t, expected, Extract(text))
+}
+
+func TestExtractHashtags(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected []Entity
+	}{
+		{"#go", []Entity{{Hashtag, "go", [2]int{0, 3}}}},
+		{"日本語 ＃ハッシュタグ", []Entity{{Hashtag, "ハッシュタグ", [2]int{4, 11}}}},
+		{"#café_2 #123", []Entity{{Hashtag, "café_2", [2]int{0, 7}}}},
+		{"a#go &#39; #a#b #go://", nil},
+		{"https://example.com/#anchor", nil},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, ExtractHashtags(c.text), c.text)
+	}
+}
+
+func TestExtractMentions(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected []Entity
+	}{
+		{"@gopher", []Entity{{Mention, "gopher", [2]int{0, 7}}}},
+		{"RT@gopher: hi ＠Bob_1", []Entity{{Mention, "gopher", [2]int{2, 9}}, {Mention, "Bob_1", [2]int{14, 20}}}},
+		{"me@example.com @abcdefghijklmnopqrstu @gopher/list @élan", nil},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, ExtractMentions(c.text), c.text)
+	}
+}
+
+func TestExtractCashtags(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected []Entity
+	}{
+		{"$TWTR", []Entity{{Cashtag, "TWTR", [2]int{0, 5}}}},
+		{"buy $BRK.A, not $abc1 or a$B", []Entity{{Cashtag, "BRK.A", [2]int{4, 10}}}},
+		{"$1000 $TOOLONGX", nil},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, ExtractCashtags(c.text), c.text)
+	}
+}
+
+func TestExtractURLs(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected []Entity
+	}{
+		{"https://example.com/a?b=c", []Entity{{URL, "https://example.com/a?b=c", [2]int{0, 25}}}},
+		{"see go.dev.", []Entity{{URL, "go.dev", [2]int{4, 10}}}},
+		{"(http://a.example/wiki/Go_(lang)) x", []Entity{{URL, "http://a.example/wiki/Go_(lang)", [2]int{1, 32}}}},
+		{"example.com.Next sentence", []Entity{{URL, "example.com", [2]int{0, 11}}}},
+		{"日本 example.co.jp/パス", []Entity{{URL, "example.co.jp/", [2]int{3, 17}}}},
+		{"me@example.com file.txt e.g. http://localhost", nil},
+		// second level ccTLD domains need a path, unless they are short
+		{"ok.so what, fine.no really", nil},
+		{"t.co and twitch.tv", []Entity{{URL, "t.co", [2]int{0, 4}}, {URL, "twitch.tv", [2]int{9, 18}}}},
+		{"bit.ly/go and go.bit.ly", []Entity{{URL, "bit.ly/go", [2]int{0, 9}}, {URL, "go.bit.ly", [2]int{14, 23}}}},
+		{"https://ok.so", []Entity{{URL, "https://ok.so", [2]int{0, 13}}}},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, ExtractURLs(c.text), c.text)
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Package twittertext extracts entities from Tweet text and counts its weighted
+length, compatible with the twitter-text libraries (configuration v3).
+
+Twitter limits Tweets to 280 weighted characters. Most characters, including
+CJK characters, weigh 2 while Latin and other characters in the light ranges
+weigh 1, each emoji sequence weighs 2, and each URL counts as 23 characters
+however long it is (as t.co shortens it). Parse reports the weighted length
+and whether text can be Tweeted, so text can be checked before sending it:
+
+	result := twittertext.Parse("just setting up my twttr 🐹")
+	// result.WeightedLength == 27, result.Valid == true
+
+Entity Indices are in code points (runes), like those of Tweets returned by
+the API. Text is counted as given; the twitter-text libraries first
+normalize text to Unicode NFC, which callers can do with
+golang.org/x/text/unicode/norm.
+*/
+package twittertext
+
+import (
+	"errors"
+	"strings"
+)
+
+// Weighted length limits of Tweets.
+const (
+	// MaxWeightedLength is the maximum weighted length of a Tweet.
+	MaxWeightedLength = 280
+	// URLLength is the weighted length of every URL.
+	URLLength = 23
+)
+
+// weights are in hundredths of a weighted character
+const (
+	scale         = 100
+	defaultWeight = 200
+	lightWeight   = 100
+)
+
+// code point ranges of characters with the light weight
+var lightRanges = [][2]rune{
+	{0x0000, 0x10FF},
+	{0x2000, 0x200D},
+	{0x2010, 0x201F},
+	{0x2032, 0x2037},
+}
+
+// Errors of invalid Tweet text.
+var (
+	ErrEmpty            = errors.New("twittertext: text is empty")
+	ErrTooLong          = errors.New("twittertext: text is longer than 280 weighted characters")
+	ErrInvalidCharacter = errors.New("twittertext: text contains an invalid character")
+)
+
+// ParseResult is the weighted length and validity of Tweet text.
+type ParseResult struct {
+	// weighted length of the text
+	WeightedLength int
+	// weighted length per mille of MaxWeightedLength
+	Permillage int
+	// whether the text can be Tweeted
+	Valid bool
+	// range of the text, in code points (end exclusive), which fits in a
+	// Tweet
+	ValidRange [2]int
+	// why the text is invalid, if it is
+	Err error
+}
+
+// Parse returns the weighted length and validity of the text.
+func Parse(text string) ParseResult {
+	var result ParseResult
+	urls := ExtractURLs(text)
+	runes := []rune(text)
+	weight, next := 0, 0
+	invalid := false
+	for i := 0; i < len(runes); {
+		var width, w int
+		if next < len(urls) && urls[next].Indices[0] == i {
+			width, w = urls[next].Indices[1]-i, URLLength*scale
+			next++
+		} else if n := emojiLength(runes[i:]); n > 0 {
+			width, w = n, defaultWeight
+		} else {
+			width, w = 1, charWeight(runes[i])
+			invalid = invalid || isInvalid(runes[i])
+		}
+		weight += w
+		i += width
+		if weight <= MaxWeightedLength*scale && !invalid {
+			result.ValidRange[1] = i
+		}
+	}
+	result.WeightedLength = weight / scale
+	result.Permillage = result.WeightedLength * 1000 / MaxWeightedLength
+	switch {
+	case strings.TrimSpace(text) == "":
+		result.Err = ErrEmpty
+	case invalid:
+		result.Err = ErrInvalidCharacter
+	case result.WeightedLength > MaxWeightedLength:
+		result.Err = ErrTooLong
+	}
+	result.Valid = result.Err == nil
+	return result
+}
+
+// WeightedLength returns the weighted length of the text.
+func WeightedLength(text string) int {
+	return Parse(text).WeightedLength
+}
+
+// Validate returns ErrEmpty, ErrInvalidCharacter, or ErrTooLong if the text
+// can't be Tweeted, or nil if it can.
+func Validate(text string) error {
+	return Parse(text).Err
+}
+
+// charWeight returns the weight of a character which is not part of a URL
+// or emoji.
+func charWeight(r rune) int {
+	for _, lr := range lightRanges {
+		if r >= lr[0] && r <= lr[1] {
+			return lightWeight
+		}
+	}
+	return defaultWeight
+}
+
+// isInvalid returns true for characters which Tweets may not contain.
+func isInvalid(r rune) bool {
+	return r == 0xFFFE || r == 0xFEFF || r == 0xFFFF || (r >= 0x202A && r <= 0x202E)
+}
+
+// emojiLength returns the number of code points of the emoji sequence at
+// the start of the runes, or 0 if they don't start with an emoji. Sequences
+// include skin tone modifiers, variation selectors, zero width joined
+// emoji, tags, flags, and keycaps.
+func emojiLength(runes []rune) int {
+	if len(runes) == 0 {
+		return 0
+	}
+	// keycaps, e.g. "1️⃣"
+	if strings.ContainsRune("0123456789#*", runes[0]) {
+		n := 1
+		if n < len(runes) && runes[n] == 0xFE0F {
+			n++
+		}
+		if n < len(runes) && runes[n] == 0x20E3 {
+			return n + 1
+		}
+		return 0
+	}
+	// flags are pairs of regional indicators
+	if isRegionalIndicator(runes[0]) {
+		if len(runes) > 1 && isRegionalIndicator(runes[1]) {
+			return 2
+		}
+		return 1
+	}
+	n := 1
+	if isTextEmoji(runes[0]) {
+		// emoji only with the emoji variation selector, e.g. "©️"
+		if len(runes) < 2 || runes[1] != 0xFE0F {
+			return 0
+		}
+		n = 2
+	} else if !isEmoji(runes[0]) {
+		return 0
+	}
+	for n < len(runes) {
+		switch r := runes[n]; {
+		case r == 0xFE0F || (r >= 0x1F3FB && r <= 0x1F3FF) || (r >= 0xE0020 && r <= 0xE007F):
+			n++
+		case r == 0x200D && n+1 < len(runes) && isEmoji(runes[n+1]):
+			n += 2
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+// isEmoji returns true for pictographic emoji characters.
+func isEmoji(r rune) bool {
+	return (r >= 0x1F000 && r <= 0x1FAFF) ||
+		(r >= 0x2600 && r <= 0x27BF) ||
+		(r >= 0x2300 && r <= 0x23FF) ||
+		(r >= 0x2B00 && r <= 0x2BFF) ||
+		r == 0x3030 || r == 0x303D || r == 0x3297 || r == 0x3299
+}
+
+// isTextEmoji returns true for light characters which are emoji when
+// followed by the emoji variation selector.
+func isTextEmoji(r rune) bool {
+	return r == 0x00A9 || r == 0x00AE || r == 0x203C || r == 0x2049 ||
+		r == 0x2122 || r == 0x2139 || (r >= 0x2194 && r <= 0x21AA)
+}
+
+// isRegionalIndicator returns true for the regional indicator symbols which
+// pair up into flags.
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
@@ -0,0 +1,57 @@
+package twittertext
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeightedLength(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected int
+	}{
+		{"", 0},
+		{"hello", 5},
+		{"日本語", 6},
+		{"café “quoted”", 13},
+		{"🐹", 2},
+		{"👍\U0001F3FD", 2},
+		{"👨\u200D👩\u200D👧\u200D👦", 2},
+		{"🇯🇵", 2},
+		{"1\uFE0F\u20E3", 2},
+		{"© ©\uFE0F", 4},
+		{"see https://example.com/a/very/long/path/which/is/shortened/by/tco", 27},
+		{"go.dev rocks", 29},
+		{"ok.so what", 10},
+		{"fine.no really", 14},
+		{"t.co/abc ok.so/path", 47},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, WeightedLength(c.text), c.text)
+	}
+}
+
+func TestParse(t *testing.T) {
+	result := Parse("just setting up my twttr 🐹")
+	assert.Equal(t, ParseResult{WeightedLength: 27, Permillage: 96, Valid: true, ValidRange: [2]int{0, 26}}, result)
+
+	result = Parse(strings.Repeat("a", 280))
+	assert.True(t, result.Valid)
+	assert.Equal(t, 1000, result.Permillage)
+
+	result = Parse(strings.Repeat("日", 141))
+	assert.False(t, result.Valid)
+	assert.Equal(t, ErrTooLong, result.Err)
+	assert.Equal(t, 282, result.WeightedLength)
+	assert.Equal(t, [2]int{0, 140}, result.ValidRange)
+}
+
+func TestValidate(t *testing.T) {
+	assert.Nil(t, Validate(strings.Repeat("🐹", 140)))
+	assert.Equal(t, ErrTooLong, Validate(strings.Repeat("🐹", 141)))
+	assert.Equal(t, ErrEmpty, Validate(" \n"))
+	assert.Equal(t, ErrInvalidCharacter, Validate("bad \uFFFE"))
+	assert.Equal(t, ErrInvalidCharacter, Validate("\u202Eright to left"))
+}
@@ -1,6 +1,7 @@
 package twitter
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/dghubble/sling"
@@ -10,7 +11,7 @@ import (
 // https://dev.twitter.com/overview/api/users
 type User struct {
 	ContributorsEnabled            bool          `json:"contributors_enabled"`
-	CreatedAt                      string        `json:"created_at"`
+	CreatedAt                      Time          `json:"created_at"`
 	DefaultProfile                 bool          `json:"default_profile"`
 	DefaultProfileImage            bool          `json:"default_profile_image"`
 	Description                    string        `json:"description"`
@@ -53,6 +54,8 @@ type User struct {
 	Verified                       bool          `json:"verified"`
 	WithheldInCountries            []string      `json:"withheld_in_countries"`
 	WithholdScope                  string        `json:"withheld_scope"`
+	// original JSON, if retained (see WithRawJSON)
+	Raw json.RawMessage `json:"-"`
 }
 
 // UserService provides methods for accessing Twitter user API endpoints.